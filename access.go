@@ -9,6 +9,8 @@ type (
 		AccessAsNumber
 		AccessAsBool
 		AccessAsNull
+		AccessAsInteger
+		AccessAsFormat
 	}
 	AccessAsObject interface {
 		AccessAsObject(...Accessor) (Object, error)
@@ -78,7 +80,7 @@ func accessAsBool(v JsonValue, ptr ...Accessor) (Bool, error) {
 func accessAsNull(v JsonValue, ptr ...Accessor) (Null, error) {
 	v, err := Pointer(ptr).Accessing(v)
 	if err != nil {
-		return nil, err
+		return Null{}, err
 	}
 	return v.AsNull()
 }
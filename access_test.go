@@ -17,7 +17,7 @@ func TestAccessAsValue(t *testing.T) {
 		}{
 			"root": {
 				target:   `{"hello":"world"}`,
-				accessor: HelperFatalParsePointer(t, "/"),
+				accessor: HelperFatalParsePointer(t, ""),
 				expected: fluffyjson.Object{"hello": HelperCastString(t, "world")},
 				err:      nil,
 			},
@@ -47,7 +47,7 @@ func TestAccessAsValue(t *testing.T) {
 		}{
 			"root": {
 				target:   `["hello", "world"]`,
-				accessor: HelperFatalParsePointer(t, "/"),
+				accessor: HelperFatalParsePointer(t, ""),
 				expected: fluffyjson.Array{HelperCastString(t, "hello"), HelperCastString(t, "world")},
 				err:      nil,
 			},
@@ -77,7 +77,7 @@ func TestAccessAsValue(t *testing.T) {
 		}{
 			"root": {
 				target:   `"hello world"`,
-				accessor: HelperFatalParsePointer(t, "/"),
+				accessor: HelperFatalParsePointer(t, ""),
 				expected: *HelperCastString(t, "hello world"),
 				err:      nil,
 			},
@@ -107,7 +107,7 @@ func TestAccessAsValue(t *testing.T) {
 		}{
 			"root": {
 				target:   `100`,
-				accessor: HelperFatalParsePointer(t, "/"),
+				accessor: HelperFatalParsePointer(t, ""),
 				expected: *HelperCastNumber(t, 100),
 				err:      nil,
 			},
@@ -137,7 +137,7 @@ func TestAccessAsValue(t *testing.T) {
 		}{
 			"root": {
 				target:   `true`,
-				accessor: HelperFatalParsePointer(t, "/"),
+				accessor: HelperFatalParsePointer(t, ""),
 				expected: *HelperCastBool(t, true),
 				err:      nil,
 			},
@@ -167,7 +167,7 @@ func TestAccessAsValue(t *testing.T) {
 		}{
 			"root": {
 				target:   `null`,
-				accessor: HelperFatalParsePointer(t, "/"),
+				accessor: HelperFatalParsePointer(t, ""),
 				expected: *HelperCastNull(t, nil),
 				err:      nil,
 			},
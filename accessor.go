@@ -2,6 +2,9 @@ package fluffyjson
 
 import (
 	"fmt"
+	"iter"
+	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 )
@@ -10,6 +13,7 @@ type (
 	Access interface {
 		Access(...Accessor) (JsonValue, error)
 		Slice(SliceAccessor) ([]JsonValue, error)
+		Match(...Accessor) iter.Seq2[Pointer, JsonValue]
 	}
 
 	Accessor interface {
@@ -24,9 +28,20 @@ type (
 	KeyIndexAccess string
 	Pointer        []Accessor
 
+	// FuzzyKeyAccess matches the key of object case-insensitively and across naming conventions,
+	// such as `userId`, `user_id` and `UserID`. The exact match takes precedence.
+	FuzzyKeyAccess string
+	// FuzzyPointer resolves the keys of the pointer as [FuzzyKeyAccess].
+	FuzzyPointer Pointer
+
+	// SliceAccess slices array like Python, such as a[Start:End:Step].
+	//   - Negative Start and End count from the end of the array.
+	//   - Out of range Start and End are clamped, so math.MaxInt or math.MinInt can be used as omitted bound.
+	//   - Negative Step slices in reverse order, and zero Step is regarded as 1.
 	SliceAccess struct {
 		Start int
 		End   int
+		Step  int
 	}
 
 	ErrAccess struct {
@@ -34,11 +49,109 @@ type (
 		Expected representation
 		Actual   representation
 	}
+	// ErrNotFound is returned when the key is missing in the object.
+	// Pointer is the path up to the failure point, and Value is the last value successfully accessed.
+	// The existing keys close to the missing key are computed by [ErrNotFound.Suggestions] only when needed.
+	ErrNotFound struct {
+		Pointer Pointer
+		Value   JsonValue
+		Key     string
+	}
+	// ErrAmbiguousKey is returned when more than one key matches the [FuzzyKeyAccess].
+	ErrAmbiguousKey struct {
+		Pointer    Pointer
+		Value      JsonValue
+		Key        string
+		Candidates []string
+	}
+	// ErrInvalidIndex is returned when the token of [KeyIndexAccess] accessing the array is not `0` or digits without leading zero.
+	// Pointer is the path up to the failure point, and Value is the last value successfully accessed.
+	ErrInvalidIndex struct {
+		Pointer Pointer
+		Value   JsonValue
+		Token   string
+	}
+	// ErrOutOfRange is returned when the index is out of range of the array.
+	// Pointer is the path up to the failure point, and Value is the last value successfully accessed.
+	ErrOutOfRange struct {
+		Pointer Pointer
+		Value   JsonValue
+		Index   int
+		Length  int
+	}
 )
 
 func (e ErrAccess) Error() string {
 	return fmt.Sprintf("%s only allowed on %s, got %s", e.Accessor, e.Expected, e.Actual)
 }
+func (e ErrNotFound) Error() string {
+	return fmt.Sprintf("key %q not found at %s%s", e.Key, e.Pointer.location(), didYouMean(e.Suggestions()))
+}
+
+// Suggestions returns the existing keys of the accessed object close to the missing key, nearest first.
+func (e ErrNotFound) Suggestions() []string {
+	if o, ok := e.Value.(*Object); ok {
+		return suggestKeys(e.Key, *o)
+	}
+	return nil
+}
+func (e ErrNotFound) Is(target error) bool {
+	_, ok := target.(ErrNotFound)
+	return ok
+}
+func (e ErrAmbiguousKey) Error() string {
+	return fmt.Sprintf("key %q is ambiguous at %s, candidates are %q", e.Key, e.Pointer.location(), e.Candidates)
+}
+func (e ErrAmbiguousKey) Is(target error) bool {
+	_, ok := target.(ErrAmbiguousKey)
+	return ok
+}
+func (e ErrInvalidIndex) Error() string {
+	return fmt.Sprintf("invalid array index %q at %s", e.Token, e.Pointer.location())
+}
+func (e ErrInvalidIndex) Is(target error) bool {
+	_, ok := target.(ErrInvalidIndex)
+	return ok
+}
+func (e ErrOutOfRange) Error() string {
+	return fmt.Sprintf("index %d out of range with length %d at %s", e.Index, e.Length, e.Pointer.location())
+}
+func (e ErrOutOfRange) Is(target error) bool {
+	_, ok := target.(ErrOutOfRange)
+	return ok
+}
+
+// prefixed returns the error whose pointer is prefixed by the given pointer.
+func prefixed(prefix Pointer, err error) error {
+	switch e := err.(type) {
+	case ErrNotFound:
+		e.Pointer = append(slices.Clone(prefix), e.Pointer...)
+		return e
+	case ErrAmbiguousKey:
+		e.Pointer = append(slices.Clone(prefix), e.Pointer...)
+		return e
+	case ErrInvalidIndex:
+		e.Pointer = append(slices.Clone(prefix), e.Pointer...)
+		return e
+	case ErrOutOfRange:
+		e.Pointer = append(slices.Clone(prefix), e.Pointer...)
+		return e
+	case ErrNotIntegral:
+		e.Pointer = append(slices.Clone(prefix), e.Pointer...)
+		return e
+	case ErrOverflow:
+		e.Pointer = append(slices.Clone(prefix), e.Pointer...)
+		return e
+	case ErrFormat:
+		e.Pointer = append(slices.Clone(prefix), e.Pointer...)
+		return e
+	case ErrMutate:
+		e.Pointer = append(slices.Clone(prefix), e.Pointer...)
+		return e
+	default:
+		return err
+	}
+}
 
 func (o *Object) Access(ptr ...Accessor) (JsonValue, error)    { return Pointer(ptr).Accessing(o) }
 func (o *Object) Slice(acc SliceAccessor) ([]JsonValue, error) { return acc.Slicing(o) }
@@ -52,11 +165,32 @@ func (b *Bool) Access(ptr ...Accessor) (JsonValue, error)      { return Pointer(
 func (b *Bool) Slice(acc SliceAccessor) ([]JsonValue, error)   { return acc.Slicing(b) }
 func (n *Null) Access(ptr ...Accessor) (JsonValue, error)      { return Pointer(ptr).Accessing(n) }
 func (n *Null) Slice(acc SliceAccessor) ([]JsonValue, error)   { return acc.Slicing(n) }
+func (o *Object) Match(ptr ...Accessor) iter.Seq2[Pointer, JsonValue] {
+	return Pointer(ptr).Matching(o)
+}
+func (a *Array) Match(ptr ...Accessor) iter.Seq2[Pointer, JsonValue] {
+	return Pointer(ptr).Matching(a)
+}
+func (s *String) Match(ptr ...Accessor) iter.Seq2[Pointer, JsonValue] {
+	return Pointer(ptr).Matching(s)
+}
+func (n *Number) Match(ptr ...Accessor) iter.Seq2[Pointer, JsonValue] {
+	return Pointer(ptr).Matching(n)
+}
+func (b *Bool) Match(ptr ...Accessor) iter.Seq2[Pointer, JsonValue] {
+	return Pointer(ptr).Matching(b)
+}
+func (n *Null) Match(ptr ...Accessor) iter.Seq2[Pointer, JsonValue] {
+	return Pointer(ptr).Matching(n)
+}
 
 func (k KeyAccess) Accessing(v JsonValue) (JsonValue, error) {
 	switch o := v.(type) {
 	case *Object:
-		return (*o)[string(k)], nil
+		if v, ok := (*o)[string(k)]; ok {
+			return v, nil
+		}
+		return nil, ErrNotFound{Pointer: Pointer{k}, Value: v, Key: string(k)}
 	default:
 		return nil, ErrAccess{
 			Accessor: fmt.Sprintf("%T", k),
@@ -68,7 +202,14 @@ func (k KeyAccess) Accessing(v JsonValue) (JsonValue, error) {
 func (i IndexAccess) Accessing(v JsonValue) (JsonValue, error) {
 	switch a := v.(type) {
 	case *Array:
-		return (*a)[i], nil
+		index := int(i)
+		if index < 0 {
+			index += len(*a)
+		}
+		if index < 0 || index >= len(*a) {
+			return nil, ErrOutOfRange{Pointer: Pointer{i}, Value: v, Index: int(i), Length: len(*a)}
+		}
+		return (*a)[index], nil
 	default:
 		return nil, ErrAccess{
 			Accessor: fmt.Sprintf("%T", i),
@@ -82,11 +223,11 @@ func (ki KeyIndexAccess) Accessing(v JsonValue) (JsonValue, error) {
 	case *Object:
 		return KeyAccess(ki).Accessing(t)
 	case *Array:
-		index, err := strconv.Atoi(string(ki))
-		if err != nil {
-			return nil, err
+		index, ok := arrayIndex(string(ki))
+		if !ok {
+			return nil, ErrInvalidIndex{Pointer: Pointer{ki}, Value: v, Token: string(ki)}
 		}
-		return IndexAccess(index).Accessing(t)
+		return index.Accessing(t)
 	default:
 		return nil, ErrAccess{
 			Accessor: fmt.Sprintf("%T", ki),
@@ -96,10 +237,60 @@ func (ki KeyIndexAccess) Accessing(v JsonValue) (JsonValue, error) {
 	}
 }
 
+// arrayIndex parses the array index of JSON Pointer, which is `0` or digits without leading zero.
+// Negative indices are not allowed here, and only [IndexAccess] built in Go counts them from the end.
+func arrayIndex(token string) (IndexAccess, bool) {
+	if token == "" || len(token) > 1 && token[0] == '0' {
+		return 0, false
+	}
+	for _, c := range token {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+	}
+	index, err := strconv.Atoi(token)
+	return IndexAccess(index), err == nil
+}
+
+func (fk FuzzyKeyAccess) Accessing(v JsonValue) (JsonValue, error) {
+	switch o := v.(type) {
+	case *Object:
+		if v, ok := (*o)[string(fk)]; ok {
+			return v, nil
+		}
+		normalized, candidates := normalizeKey(string(fk)), make([]string, 0)
+		for k := range *o {
+			if normalizeKey(k) == normalized {
+				candidates = append(candidates, k)
+			}
+		}
+		switch len(candidates) {
+		case 0:
+			return nil, ErrNotFound{Pointer: Pointer{fk}, Value: v, Key: string(fk)}
+		case 1:
+			return (*o)[candidates[0]], nil
+		default:
+			slices.Sort(candidates)
+			return nil, ErrAmbiguousKey{Pointer: Pointer{fk}, Value: v, Key: string(fk), Candidates: candidates}
+		}
+	default:
+		return nil, ErrAccess{
+			Accessor: fmt.Sprintf("%T", fk),
+			Expected: OBJECT,
+			Actual:   v.representation(),
+		}
+	}
+}
+
 func (s SliceAccess) Slicing(v JsonValue) ([]JsonValue, error) {
 	switch a := v.(type) {
 	case *Array:
-		return (*a)[s.Start:s.End], nil
+		start, end, step := s.Indices(len(*a))
+		slice := make([]JsonValue, 0)
+		for i := start; (step > 0 && i < end) || (step < 0 && i > end); i += step {
+			slice = append(slice, (*a)[i])
+		}
+		return slice, nil
 	default:
 		return nil, ErrAccess{
 			Accessor: fmt.Sprintf("%T", s),
@@ -109,12 +300,36 @@ func (s SliceAccess) Slicing(v JsonValue) ([]JsonValue, error) {
 	}
 }
 
+// Indices returns the clamped start, end, and step of the slice for the array of given length.
+func (s SliceAccess) Indices(length int) (start, end, step int) {
+	step = s.Step
+	if step == 0 {
+		step = 1
+	}
+	lower, upper := 0, length
+	if step < 0 {
+		lower, upper = -1, length-1
+	}
+	clamp := func(i int) int {
+		if i < 0 {
+			return max(i+length, lower)
+		}
+		return min(i, upper)
+	}
+	return clamp(s.Start), clamp(s.End), step
+}
+
+// ParsePointer parses the pointer into [KeyIndexAccess] tokens, which access the array only by `0` or digits without leading zero.
+// The empty string is the root, and `/` is the member of the empty key.
 // https://tools.ietf.org/html/rfc6901
+//
+// Breaking change: `/` was parsed as the root before, and now it is the member of the empty key as RFC 6901 defines.
+// Callers that pass `/` to refer to the root must pass the empty string instead.
 func ParsePointer(p string) (Pointer, error) {
-	if !strings.HasPrefix(p, "/") {
-		return nil, fmt.Errorf("%s is not prefixed by /", p)
-	} else if p == "/" {
+	if p == "" {
 		return nil, nil
+	} else if !strings.HasPrefix(p, "/") {
+		return nil, fmt.Errorf("%s is not prefixed by /", p)
 	}
 
 	parsed := make([]string, 0)
@@ -134,32 +349,152 @@ func ParsePointer(p string) (Pointer, error) {
 	}
 	return pointer, nil
 }
+
+// PointerString returns the string representation of the pointer, which can be parsed back by [ParsePointer].
+// It fails if the pointer contains negative [IndexAccess], [MultiAccessor], or other accessors than keys and indices,
+// because they cannot be parsed back. Nested [Pointer] and [FuzzyPointer] are expanded.
+//
+// Breaking change: the root is represented as the empty string instead of `/`, so callers comparing the result with `/`
+// must compare it with the empty string, or check the length of the pointer.
 func (p Pointer) PointerString() (string, error) {
-	escaped := make([]string, 0, len(p))
+	var b strings.Builder
+	for _, acc := range p.tokens() {
+		token, err := referenceToken(acc)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString("/" + escapeToken(token))
+	}
+	return b.String(), nil
+}
+
+// tokens expands the nested [Pointer] and [FuzzyPointer] into the accessors of each reference token.
+func (p Pointer) tokens() Pointer {
+	tokens := make(Pointer, 0, len(p))
 	for _, acc := range p {
-		switch a := acc.(type) {
+		switch nested := acc.(type) {
 		case Pointer:
-			p, err := a.PointerString()
-			if err != nil {
-				return "", err
-			}
-			escaped = append(escaped, p)
+			tokens = append(tokens, nested.tokens()...)
+		case FuzzyPointer:
+			tokens = append(tokens, Pointer(nested).tokens()...)
 		default:
-			pointer := fmt.Sprint(a)
-			pointer = strings.ReplaceAll(pointer, "~", "~0")
-			pointer = strings.ReplaceAll(pointer, "/", "~1")
-			escaped = append(escaped, pointer)
+			tokens = append(tokens, acc)
 		}
 	}
-	return "/" + strings.Join(escaped, "/"), nil
+	return tokens
+}
+
+// referenceToken returns the unescaped reference token of the accessor, which fails if it cannot be parsed back by [ParsePointer].
+func referenceToken(acc Accessor) (string, error) {
+	switch a := acc.(type) {
+	case KeyAccess:
+		return string(a), nil
+	case KeyIndexAccess:
+		return string(a), nil
+	case FuzzyKeyAccess:
+		return string(a), nil
+	case IndexAccess:
+		if a < 0 {
+			return "", fmt.Errorf("negative index %d cannot be represented as pointer", a)
+		}
+		return strconv.Itoa(int(a)), nil
+	default:
+		return "", fmt.Errorf("%T cannot be represented as pointer", acc)
+	}
 }
+func escapeToken(token string) string {
+	token = strings.ReplaceAll(token, "~", "~0")
+	return strings.ReplaceAll(token, "/", "~1")
+}
+
+// location returns the pointer string for error messages, in which the root is represented as `root` instead of the empty string.
+// Unlike [Pointer.PointerString], it never fails, and represents the accessors that cannot be parsed back as they are formatted.
+func (p Pointer) location() string {
+	tokens := p.tokens()
+	if len(tokens) == 0 {
+		return "root"
+	}
+	var b strings.Builder
+	for _, acc := range tokens {
+		token, err := referenceToken(acc)
+		if err != nil {
+			token = fmt.Sprint(acc)
+		}
+		b.WriteString("/" + escapeToken(token))
+	}
+	return b.String()
+}
+
+// ParsePointerFragment parses the URI fragment representation of the pointer, such as `#/a%20b/~1c`.
+// https://tools.ietf.org/html/rfc6901#section-6
+func ParsePointerFragment(f string) (Pointer, error) {
+	if !strings.HasPrefix(f, "#") {
+		return nil, fmt.Errorf("%s is not prefixed by #", f)
+	}
+	p, err := url.PathUnescape(f[1:])
+	if err != nil {
+		return nil, err
+	}
+	return ParsePointer(p)
+}
+
+// FragmentString returns the URI fragment representation of the pointer, percent-encoding the characters not allowed in fragment.
+func (p Pointer) FragmentString() (string, error) {
+	s, err := p.PointerString()
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	b.WriteString("#")
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; isFragmentChar(c) {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String(), nil
+}
+
+// isFragmentChar reports whether the character is allowed in URI fragment without percent-encoding.
+// https://tools.ietf.org/html/rfc3986#section-3.5
+func isFragmentChar(c byte) bool {
+	switch {
+	case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		return true
+	default:
+		return strings.IndexByte("-._~!$&'()*+,;=:@/?", c) >= 0
+	}
+}
+
 func (p Pointer) Accessing(v JsonValue) (JsonValue, error) {
 	curr := v
-	for _, a := range p {
+	for i, a := range p {
+		curr = unwrapRoot(curr)
+		var err error
+		curr, err = a.Accessing(curr)
+		if err != nil {
+			return nil, prefixed(p[:i], err)
+		}
+	}
+	return curr, nil
+}
+func (fp FuzzyPointer) Accessing(v JsonValue) (JsonValue, error) {
+	curr := v
+	for i, a := range fp {
+		curr = unwrapRoot(curr)
+		if _, ok := curr.(*Object); ok {
+			switch k := a.(type) {
+			case KeyAccess:
+				a = FuzzyKeyAccess(k)
+			case KeyIndexAccess:
+				a = FuzzyKeyAccess(k)
+			}
+		}
 		var err error
 		curr, err = a.Accessing(curr)
 		if err != nil {
-			return nil, err
+			return nil, prefixed(Pointer(fp[:i]), err)
 		}
 	}
 	return curr, nil
@@ -1,7 +1,9 @@
 package fluffyjson_test
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"testing"
 
 	fluffyjson "github.com/hayas1/go-fluffy-json"
@@ -26,6 +28,54 @@ func TestAccess(t *testing.T) {
 			expected: HelperCastString(t, "world"),
 			err:      nil,
 		},
+		"negative index access": {
+			target:   `["hello", "world"]`,
+			accessor: fluffyjson.IndexAccess(-2),
+			expected: HelperCastString(t, "hello"),
+			err:      nil,
+		},
+		"out of range index access": {
+			target:   `["hello", "world"]`,
+			accessor: fluffyjson.IndexAccess(2),
+			expected: nil,
+			err:      fluffyjson.ErrOutOfRange{Index: 2, Length: 2},
+		},
+		"out of range negative index access": {
+			target:   `["hello", "world"]`,
+			accessor: fluffyjson.IndexAccess(-3),
+			expected: nil,
+			err:      fluffyjson.ErrOutOfRange{Index: -3, Length: 2},
+		},
+		"key index access": {
+			target:   `["hello", "world"]`,
+			accessor: fluffyjson.KeyIndexAccess("1"),
+			expected: HelperCastString(t, "world"),
+			err:      nil,
+		},
+		"negative key index access": {
+			target:   `["hello", "world"]`,
+			accessor: fluffyjson.KeyIndexAccess("-1"),
+			expected: nil,
+			err:      fluffyjson.ErrInvalidIndex{Token: "-1"},
+		},
+		"leading zero key index access": {
+			target:   `["hello", "world"]`,
+			accessor: fluffyjson.KeyIndexAccess("01"),
+			expected: nil,
+			err:      fluffyjson.ErrInvalidIndex{Token: "01"},
+		},
+		"signed key index access": {
+			target:   `["hello", "world"]`,
+			accessor: fluffyjson.KeyIndexAccess("+1"),
+			expected: nil,
+			err:      fluffyjson.ErrInvalidIndex{Token: "+1"},
+		},
+		"negative key index object access": {
+			target:   `{"-1": "world"}`,
+			accessor: fluffyjson.KeyIndexAccess("-1"),
+			expected: HelperCastString(t, "world"),
+			err:      nil,
+		},
 		"invalid key access": {
 			target:   `["hello", "world"]`,
 			accessor: fluffyjson.KeyAccess("hello"),
@@ -63,6 +113,60 @@ func TestSliceAccess(t *testing.T) {
 			},
 			err: nil,
 		},
+		"negative slice access": {
+			target:   `["one", "two", "three"]`,
+			accessor: fluffyjson.SliceAccess{Start: -2, End: math.MaxInt},
+			expected: []fluffyjson.JsonValue{
+				HelperCastString(t, "two"),
+				HelperCastString(t, "three"),
+			},
+			err: nil,
+		},
+		"clamped slice access": {
+			target:   `["one", "two", "three"]`,
+			accessor: fluffyjson.SliceAccess{Start: -100, End: 100},
+			expected: []fluffyjson.JsonValue{
+				HelperCastString(t, "one"),
+				HelperCastString(t, "two"),
+				HelperCastString(t, "three"),
+			},
+			err: nil,
+		},
+		"empty slice access": {
+			target:   `["one", "two", "three"]`,
+			accessor: fluffyjson.SliceAccess{Start: 2, End: 1},
+			expected: []fluffyjson.JsonValue{},
+			err:      nil,
+		},
+		"step slice access": {
+			target:   `["one", "two", "three", "four", "five"]`,
+			accessor: fluffyjson.SliceAccess{Start: 0, End: math.MaxInt, Step: 2},
+			expected: []fluffyjson.JsonValue{
+				HelperCastString(t, "one"),
+				HelperCastString(t, "three"),
+				HelperCastString(t, "five"),
+			},
+			err: nil,
+		},
+		"reverse slice access": {
+			target:   `["one", "two", "three"]`,
+			accessor: fluffyjson.SliceAccess{Start: -1, End: math.MinInt, Step: -1},
+			expected: []fluffyjson.JsonValue{
+				HelperCastString(t, "three"),
+				HelperCastString(t, "two"),
+				HelperCastString(t, "one"),
+			},
+			err: nil,
+		},
+		"reverse step slice access": {
+			target:   `["one", "two", "three", "four", "five"]`,
+			accessor: fluffyjson.SliceAccess{Start: 3, End: 0, Step: -2},
+			expected: []fluffyjson.JsonValue{
+				HelperCastString(t, "four"),
+				HelperCastString(t, "two"),
+			},
+			err: nil,
+		},
 		"invalid slice access": {
 			target:   `{"hello":"world"}`,
 			accessor: fluffyjson.SliceAccess{Start: 0, End: 2},
@@ -92,10 +196,20 @@ func TestPointer(t *testing.T) {
 			err      error
 		}{
 			"root": {
-				target:   "/",
+				target:   "",
 				expected: nil,
 				err:      nil,
 			},
+			"empty key": {
+				target:   "/",
+				expected: fluffyjson.Pointer{fluffyjson.KeyIndexAccess("")},
+				err:      nil,
+			},
+			"trailing empty key": {
+				target:   "/a/",
+				expected: fluffyjson.Pointer{fluffyjson.KeyIndexAccess("a"), fluffyjson.KeyIndexAccess("")},
+				err:      nil,
+			},
 		}
 		for name, tc := range testcases {
 			t.Run(name, func(t *testing.T) {
@@ -113,10 +227,16 @@ func TestPointer(t *testing.T) {
 		}{
 			"root": {
 				target:   `{"hello":"world"}`,
-				pointer:  "/",
+				pointer:  "",
 				expected: &fluffyjson.Object{"hello": HelperCastString(t, "world")},
 				err:      nil,
 			},
+			"empty key": {
+				target:   `{"": "empty", "hello": "world"}`,
+				pointer:  "/",
+				expected: HelperCastString(t, "empty"),
+				err:      nil,
+			},
 			"slice access": {
 				target:   `{"number": ["zero", "one", "two"]}`,
 				pointer:  "/number/1",
@@ -154,8 +274,16 @@ func TestPointer(t *testing.T) {
 		}{
 			"root": {
 				pointer:  nil,
+				expected: "",
+			},
+			"empty key": {
+				pointer:  fluffyjson.Pointer{fluffyjson.KeyAccess("")},
 				expected: "/",
 			},
+			"nested": {
+				pointer:  fluffyjson.Pointer{fluffyjson.KeyAccess("a"), fluffyjson.Pointer{fluffyjson.IndexAccess(0), fluffyjson.KeyAccess("b")}},
+				expected: "/a/0/b",
+			},
 			"slice access": {
 				pointer:  fluffyjson.Pointer{fluffyjson.KeyAccess("number"), fluffyjson.IndexAccess(1)},
 				expected: "/number/1",
@@ -173,6 +301,208 @@ func TestPointer(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("round trip", func(t *testing.T) {
+		testcases := map[string]struct {
+			pointer  fluffyjson.Pointer
+			expected fluffyjson.Pointer
+		}{
+			"index": {
+				pointer:  fluffyjson.Pointer{fluffyjson.KeyAccess("a"), fluffyjson.IndexAccess(0)},
+				expected: fluffyjson.Pointer{fluffyjson.KeyIndexAccess("a"), fluffyjson.KeyIndexAccess("0")},
+			},
+			"fuzzy key": {
+				pointer:  fluffyjson.Pointer{fluffyjson.FuzzyKeyAccess("userId")},
+				expected: fluffyjson.Pointer{fluffyjson.KeyIndexAccess("userId")},
+			},
+			"fuzzy pointer": {
+				pointer:  fluffyjson.Pointer{fluffyjson.KeyAccess("a"), fluffyjson.FuzzyPointer{fluffyjson.KeyAccess("b"), fluffyjson.KeyAccess("c/d")}},
+				expected: fluffyjson.Pointer{fluffyjson.KeyIndexAccess("a"), fluffyjson.KeyIndexAccess("b"), fluffyjson.KeyIndexAccess("c/d")},
+			},
+			"empty fuzzy pointer": {
+				pointer:  fluffyjson.Pointer{fluffyjson.KeyAccess("a"), fluffyjson.FuzzyPointer{}},
+				expected: fluffyjson.Pointer{fluffyjson.KeyIndexAccess("a")},
+			},
+		}
+
+		for name, tc := range testcases {
+			t.Run(name, func(t *testing.T) {
+				parsed := HelperFatalParsePointer(t, HelperFatalPointerString(t, tc.pointer))
+				HelperFatalEvaluate(t, tc.expected, parsed)
+			})
+		}
+	})
+
+	t.Run("cannot round trip", func(t *testing.T) {
+		for name, pointer := range map[string]fluffyjson.Pointer{
+			"negative index":        {fluffyjson.KeyAccess("a"), fluffyjson.IndexAccess(-1)},
+			"nested negative index": {fluffyjson.FuzzyPointer{fluffyjson.IndexAccess(-2)}},
+		} {
+			if s, err := pointer.PointerString(); err == nil {
+				t.Fatalf("%s should not be represented as pointer, but got %s", name, s)
+			}
+		}
+	})
+
+	t.Run("fragment", func(t *testing.T) {
+		testcases := map[string]struct {
+			fragment string
+			pointer  fluffyjson.Pointer
+		}{
+			"root": {
+				fragment: "#",
+				pointer:  nil,
+			},
+			"empty key": {
+				fragment: "#/",
+				pointer:  fluffyjson.Pointer{fluffyjson.KeyIndexAccess("")},
+			},
+			"slice access": {
+				fragment: "#/number/1",
+				pointer:  fluffyjson.Pointer{fluffyjson.KeyIndexAccess("number"), fluffyjson.KeyIndexAccess("1")},
+			},
+			"percent encoding": {
+				fragment: "#/a%20b/c%25d/%5E%7C%22%5C",
+				pointer:  fluffyjson.Pointer{fluffyjson.KeyIndexAccess("a b"), fluffyjson.KeyIndexAccess("c%d"), fluffyjson.KeyIndexAccess(`^|"\`)},
+			},
+			"escape": {
+				fragment: "#/~1c/~0d",
+				pointer:  fluffyjson.Pointer{fluffyjson.KeyIndexAccess("/c"), fluffyjson.KeyIndexAccess("~d")},
+			},
+			"non ascii": {
+				fragment: "#/%E3%81%82",
+				pointer:  fluffyjson.Pointer{fluffyjson.KeyIndexAccess("あ")},
+			},
+		}
+
+		for name, tc := range testcases {
+			t.Run(name, func(t *testing.T) {
+				parsed, err := fluffyjson.ParsePointerFragment(tc.fragment)
+				HelperFatalEvaluateError(t, tc.pointer, parsed, nil, err)
+				formatted, err := tc.pointer.FragmentString()
+				HelperFatalEvaluateError(t, tc.fragment, formatted, nil, err)
+			})
+		}
+
+		for _, invalid := range []string{"/a", "#a", "#/a%2"} {
+			if _, err := fluffyjson.ParsePointerFragment(invalid); err == nil {
+				t.Fatalf("%s should be invalid fragment", invalid)
+			}
+		}
+	})
+}
+
+func TestAccessError(t *testing.T) {
+	testcases := map[string]struct {
+		target   string
+		pointer  string
+		expected error
+	}{
+		"missing key": {
+			target:  `{"a": {"b": {"c": 1}}}`,
+			pointer: "/a/x/c",
+			expected: fluffyjson.ErrNotFound{
+				Pointer: fluffyjson.Pointer{fluffyjson.KeyIndexAccess("a"), fluffyjson.KeyAccess("x")},
+				Value:   &fluffyjson.Object{"b": &fluffyjson.Object{"c": HelperCastNumber(t, 1)}},
+				Key:     "x",
+			},
+		},
+		"out of range": {
+			target:  `{"a": [{"b": 1}]}`,
+			pointer: "/a/1/b",
+			expected: fluffyjson.ErrOutOfRange{
+				Pointer: fluffyjson.Pointer{fluffyjson.KeyIndexAccess("a"), fluffyjson.IndexAccess(1)},
+				Value:   &fluffyjson.Array{&fluffyjson.Object{"b": HelperCastNumber(t, 1)}},
+				Index:   1,
+				Length:  1,
+			},
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			value := HelperUnmarshalValue(t, tc.target)
+			_, err := value.Access(HelperFatalParsePointer(t, tc.pointer))
+			if !errors.Is(err, tc.expected) {
+				t.Fatal(err)
+			}
+			HelperFatalEvaluate(t, tc.expected, err)
+		})
+	}
+
+	t.Run("negative index message", func(t *testing.T) {
+		value := HelperUnmarshalValue(t, `{"a": [1]}`)
+		_, err := value.Access(fluffyjson.KeyAccess("a"), fluffyjson.IndexAccess(-2))
+		HelperFatalEvaluate(t, "index -2 out of range with length 1 at /a/-2", err.Error())
+	})
+}
+
+func TestFuzzyAccess(t *testing.T) {
+	t.Run("fuzzy key access", func(t *testing.T) {
+		testcases := map[string]struct {
+			target   string
+			accessor fluffyjson.Accessor
+			expected fluffyjson.JsonValue
+			err      error
+		}{
+			"exact": {
+				target:   `{"userId": 1, "user_id": 2}`,
+				accessor: fluffyjson.FuzzyKeyAccess("user_id"),
+				expected: HelperCastNumber(t, 2),
+				err:      nil,
+			},
+			"camel to snake": {
+				target:   `{"user_id": 1}`,
+				accessor: fluffyjson.FuzzyKeyAccess("userId"),
+				expected: HelperCastNumber(t, 1),
+				err:      nil,
+			},
+			"case insensitive": {
+				target:   `{"UserID": 1}`,
+				accessor: fluffyjson.FuzzyKeyAccess("userid"),
+				expected: HelperCastNumber(t, 1),
+				err:      nil,
+			},
+			"ambiguous": {
+				target:   `{"userId": 1, "user_id": 2}`,
+				accessor: fluffyjson.FuzzyKeyAccess("UserID"),
+				expected: nil,
+				err:      fluffyjson.ErrAmbiguousKey{},
+			},
+			"not found": {
+				target:   `{"userId": 1}`,
+				accessor: fluffyjson.FuzzyKeyAccess("groupId"),
+				expected: nil,
+				err:      fluffyjson.ErrNotFound{},
+			},
+		}
+
+		for name, tc := range testcases {
+			t.Run(name, func(t *testing.T) {
+				value := HelperUnmarshalValue(t, tc.target)
+				actual, err := value.Access(tc.accessor)
+				HelperFatalEvaluateError(t, tc.expected, actual, tc.err, err)
+			})
+		}
+	})
+
+	t.Run("fuzzy pointer", func(t *testing.T) {
+		value := HelperUnmarshalValue(t, `{"user_info": {"Emails": ["a@example.com", "b@example.com"]}, "userInfo": 0}`)
+
+		actual, err := value.Access(fluffyjson.FuzzyPointer(HelperFatalParsePointer(t, "/UserInfo/emails/1")))
+		HelperFatalEvaluateError(t, nil, actual, fluffyjson.ErrAmbiguousKey{}, err)
+
+		actual, err = value.Access(fluffyjson.FuzzyPointer(HelperFatalParsePointer(t, "/user_info/emails/1")))
+		HelperFatalEvaluateError(t, fluffyjson.JsonValue(HelperCastString(t, "b@example.com")), actual, nil, err)
+
+		_, err = value.Access(fluffyjson.FuzzyPointer(HelperFatalParsePointer(t, "/user_info/Email/1")))
+		var notFound fluffyjson.ErrNotFound
+		if !errors.As(err, &notFound) {
+			t.Fatal(err)
+		}
+		HelperFatalEvaluate(t, []string{"Emails"}, notFound.Suggestions())
+		HelperFatalEvaluate(t, "/user_info/Email", HelperFatalPointerString(t, notFound.Pointer))
+	})
 }
 
 func TestAccessVariadic(t *testing.T) {
@@ -209,6 +539,14 @@ func FuzzPointerRoundtrip(f *testing.F) {
 	})
 }
 
+func HelperFatalPointerString(t *testing.T, pointer fluffyjson.Pointer) string {
+	t.Helper()
+	s, err := pointer.PointerString()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
 func HelperFatalParsePointer(t *testing.T, target string) fluffyjson.Pointer {
 	t.Helper()
 	pointer, err := fluffyjson.ParsePointer(target)
@@ -10,6 +10,8 @@ type (
 		AsNumber
 		AsBool
 		AsNull
+		AsInteger
+		AsFormat
 	}
 
 	AsObject interface {
@@ -58,7 +60,7 @@ func (o Object) AsNumber() (Number, error) { return 0, ErrAsValue{Expected: NUMB
 func (o Object) IsBool() bool              { return false }
 func (o Object) AsBool() (Bool, error)     { return false, ErrAsValue{Expected: BOOL, Actual: OBJECT} }
 func (o Object) IsNull() bool              { return false }
-func (o Object) AsNull() (Null, error)     { return nil, ErrAsValue{Expected: NULL, Actual: OBJECT} }
+func (o Object) AsNull() (Null, error)     { return Null{}, ErrAsValue{Expected: NULL, Actual: OBJECT} }
 
 func (a Array) IsObject() bool            { return false }
 func (a Array) AsObject() (Object, error) { return nil, ErrAsValue{Expected: OBJECT, Actual: ARRAY} }
@@ -71,7 +73,7 @@ func (a Array) AsNumber() (Number, error) { return 0, ErrAsValue{Expected: NUMBE
 func (a Array) IsBool() bool              { return false }
 func (a Array) AsBool() (Bool, error)     { return false, ErrAsValue{Expected: BOOL, Actual: ARRAY} }
 func (a Array) IsNull() bool              { return false }
-func (a Array) AsNull() (Null, error)     { return nil, ErrAsValue{Expected: NULL, Actual: ARRAY} }
+func (a Array) AsNull() (Null, error)     { return Null{}, ErrAsValue{Expected: NULL, Actual: ARRAY} }
 
 func (s String) IsArray() bool             { return false }
 func (s String) AsArray() (Array, error)   { return nil, ErrAsValue{Expected: ARRAY, Actual: STRING} }
@@ -84,7 +86,7 @@ func (s String) AsNumber() (Number, error) { return 0, ErrAsValue{Expected: NUMB
 func (s String) IsBool() bool              { return false }
 func (s String) AsBool() (Bool, error)     { return false, ErrAsValue{Expected: BOOL, Actual: STRING} }
 func (s String) IsNull() bool              { return false }
-func (s String) AsNull() (Null, error)     { return nil, ErrAsValue{Expected: NULL, Actual: STRING} }
+func (s String) AsNull() (Null, error)     { return Null{}, ErrAsValue{Expected: NULL, Actual: STRING} }
 
 func (n Number) IsObject() bool            { return false }
 func (n Number) AsObject() (Object, error) { return nil, ErrAsValue{Expected: OBJECT, Actual: NUMBER} }
@@ -97,7 +99,7 @@ func (n Number) AsNumber() (Number, error) { return n, nil }
 func (n Number) IsBool() bool              { return false }
 func (n Number) AsBool() (Bool, error)     { return false, ErrAsValue{Expected: BOOL, Actual: NUMBER} }
 func (n Number) IsNull() bool              { return false }
-func (n Number) AsNull() (Null, error)     { return nil, ErrAsValue{Expected: NULL, Actual: NUMBER} }
+func (n Number) AsNull() (Null, error)     { return Null{}, ErrAsValue{Expected: NULL, Actual: NUMBER} }
 
 func (b Bool) IsObject() bool            { return false }
 func (b Bool) AsObject() (Object, error) { return nil, ErrAsValue{Expected: OBJECT, Actual: BOOL} }
@@ -110,7 +112,7 @@ func (b Bool) AsNumber() (Number, error) { return 0, ErrAsValue{Expected: NUMBER
 func (b Bool) IsBool() bool              { return true }
 func (b Bool) AsBool() (Bool, error)     { return b, nil }
 func (b Bool) IsNull() bool              { return false }
-func (b Bool) AsNull() (Null, error)     { return nil, ErrAsValue{Expected: NULL, Actual: BOOL} }
+func (b Bool) AsNull() (Null, error)     { return Null{}, ErrAsValue{Expected: NULL, Actual: BOOL} }
 
 func (n Null) IsObject() bool            { return false }
 func (n Null) AsObject() (Object, error) { return nil, ErrAsValue{Expected: OBJECT, Actual: NULL} }
@@ -150,12 +150,12 @@ func TestAsValue(t *testing.T) {
 		}{
 			"null as null": {
 				target:   `null`,
-				expected: nil,
+				expected: fluffyjson.NullValue,
 				err:      nil,
 			},
 			"object as null": {
 				target:   `{"hello": "world"}`,
-				expected: nil,
+				expected: fluffyjson.NullValue,
 				err:      fluffyjson.ErrAsValue{Expected: fluffyjson.NULL, Actual: fluffyjson.OBJECT},
 			},
 		}
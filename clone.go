@@ -0,0 +1,167 @@
+package fluffyjson
+
+type (
+	CloneOption  func(*cloneOptions)
+	cloneOptions struct {
+		copyOnWrite bool
+	}
+
+	// CowValue is the copy-on-write clone created by [Clone] with [CopyOnWrite]. It shares the tree with the original,
+	// and copies only the objects and arrays on the path of each mutation by [CowValue.Set], [CowValue.Insert] and [CowValue.Delete],
+	// at most once for each of them.
+	//
+	// The values read from CowValue, such as by Access, AccessAs* and Slice*, alias the tree shared with the original and the other clones,
+	// so modifying them in place leaks into all of them. The original must not be modified in place either. Mutate through CowValue,
+	// or [Clone] the read value to modify it freely.
+	CowValue struct {
+		RootValue
+		// owned is the set of the objects and arrays copied by this value, which are not shared with others
+		owned map[JsonValue]struct{}
+	}
+)
+
+// CopyOnWrite makes [Clone] return [CowValue] instead of copying the whole tree.
+func CopyOnWrite() CloneOption {
+	return func(o *cloneOptions) { o.copyOnWrite = true }
+}
+
+// Clone returns the copy of the value that shares nothing with the original, and [RootValue] is unwrapped.
+// With [CopyOnWrite], it returns *[CowValue] that copies lazily.
+func Clone(v JsonValue, opts ...CloneOption) JsonValue {
+	var options cloneOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.copyOnWrite {
+		if cow, ok := v.(*CowValue); ok {
+			return cow.Clone()
+		}
+		return &CowValue{RootValue: RootValue{unwrapRoot(v)}}
+	}
+	return deepCopy(v)
+}
+
+// deepCopy returns the copy of the value that shares nothing with the original.
+func deepCopy(v JsonValue) JsonValue {
+	switch t := unwrapRoot(v).(type) {
+	case *Object:
+		o := make(Object, len(*t))
+		for k, v := range *t {
+			o[k] = deepCopy(v)
+		}
+		return &o
+	case *Array:
+		a := make(Array, 0, len(*t))
+		for _, v := range *t {
+			a = append(a, deepCopy(v))
+		}
+		return &a
+	case *String:
+		s := *t
+		return &s
+	case *Number:
+		n := *t
+		return &n
+	case *Bool:
+		b := *t
+		return &b
+	case *Null:
+		n := *t
+		return &n
+	default:
+		return t
+	}
+}
+
+// Clone returns another copy-on-write clone. The objects and arrays already copied by c are copied again for the returned clone,
+// so both of them keep mutating their own copies in place, and the rest of the tree is still shared.
+func (c *CowValue) Clone() *CowValue {
+	clone := &CowValue{owned: make(map[JsonValue]struct{})}
+	clone.JsonValue = clone.adopt(c.owned, unwrapRoot(c.JsonValue))
+	return clone
+}
+
+// adopt returns the value whose objects and arrays in owned are replaced with the shallow copies owned by c.
+// The owned values are connected from the root, so the children of the others need not be visited.
+func (c *CowValue) adopt(owned map[JsonValue]struct{}, v JsonValue) JsonValue {
+	if _, ok := owned[v]; !ok {
+		return v
+	}
+	copied := c.shallowCopy(v)
+	switch t := copied.(type) {
+	case *Object:
+		for k, child := range *t {
+			(*t)[k] = c.adopt(owned, child)
+		}
+	case *Array:
+		for i, child := range *t {
+			(*t)[i] = c.adopt(owned, child)
+		}
+	}
+	return copied
+}
+
+func (c *CowValue) Set(ptr Pointer, value JsonValue, opts ...MutateOption) error {
+	c.own(ptr)
+	return c.RootValue.Set(ptr, value, opts...)
+}
+func (c *CowValue) Insert(ptr Pointer, value JsonValue, opts ...MutateOption) error {
+	c.own(ptr)
+	return c.RootValue.Insert(ptr, value, opts...)
+}
+func (c *CowValue) Delete(ptr Pointer) error {
+	c.own(ptr)
+	return c.RootValue.Delete(ptr)
+}
+
+// own copies the shared objects and arrays from the root to the parent of the value at the pointer.
+// It stops at the missing value, which is created by the mutation if needed.
+func (c *CowValue) own(ptr Pointer) {
+	if c.owned == nil {
+		c.owned = make(map[JsonValue]struct{})
+	}
+	ptr = ptr.flatten()
+	if len(ptr) == 0 {
+		return
+	}
+
+	c.JsonValue = c.shallowCopy(unwrapRoot(c.JsonValue))
+	curr := c.JsonValue
+	for _, acc := range ptr[:len(ptr)-1] {
+		next, err := acc.Accessing(curr)
+		if err != nil {
+			return
+		}
+		copied := c.shallowCopy(unwrapRoot(next))
+		if copied != next {
+			// the parent is already owned, so setting the copy does not affect others
+			if err := setChild(curr, concreteAccessor(acc, curr), copied, false); err != nil {
+				return
+			}
+		}
+		curr = copied
+	}
+}
+
+// shallowCopy returns the value itself if it is owned or is not object nor array, otherwise its owned shallow copy.
+func (c *CowValue) shallowCopy(v JsonValue) JsonValue {
+	if _, ok := c.owned[v]; ok {
+		return v
+	}
+	var copied JsonValue
+	switch t := v.(type) {
+	case *Object:
+		o := make(Object, len(*t))
+		for k, v := range *t {
+			o[k] = v
+		}
+		copied = &o
+	case *Array:
+		a := append(make(Array, 0, len(*t)), *t...)
+		copied = &a
+	default:
+		return v
+	}
+	c.owned[copied] = struct{}{}
+	return copied
+}
@@ -0,0 +1,197 @@
+package fluffyjson_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	fluffyjson "github.com/hayas1/go-fluffy-json"
+)
+
+func ExampleClone() {
+	var original fluffyjson.RootValue
+	if err := json.Unmarshal([]byte(`{"users": [{"name": "alice"}, {"name": "bob"}], "count": 2}`), &original); err != nil {
+		panic(err)
+	}
+
+	cloned := fluffyjson.Clone(&original, fluffyjson.CopyOnWrite()).(*fluffyjson.CowValue)
+	name := fluffyjson.String("carol")
+	if err := cloned.Set(fluffyjson.Pointer{fluffyjson.KeyAccess("users"), fluffyjson.IndexAccess(1), fluffyjson.KeyAccess("name")}, &name); err != nil {
+		panic(err)
+	}
+
+	o, err := json.Marshal(original)
+	if err != nil {
+		panic(err)
+	}
+	c, err := json.Marshal(cloned)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(o))
+	fmt.Println(string(c))
+	// Output:
+	// {"count":2,"users":[{"name":"alice"},{"name":"bob"}]}
+	// {"count":2,"users":[{"name":"alice"},{"name":"carol"}]}
+}
+
+func TestClone(t *testing.T) {
+	const target = `{"a": {"b": [1, {"c": null}]}, "d": "e", "f": true}`
+
+	t.Run("deep copy", func(t *testing.T) {
+		original := HelperUnmarshalValue(t, target)
+		cloned := fluffyjson.RootValue{JsonValue: fluffyjson.Clone(&original)}
+		HelperFatalEvaluate(t, original, cloned)
+
+		if err := cloned.Set(HelperFatalParsePointer(t, "/a/b/1/c"), HelperCastNumber(t, 1)); err != nil {
+			t.Fatal(err)
+		}
+		if err := original.Delete(HelperFatalParsePointer(t, "/a/b/0")); err != nil {
+			t.Fatal(err)
+		}
+		HelperFatalEvaluate(t, HelperUnmarshalValue(t, `{"a": {"b": [{"c": null}]}, "d": "e", "f": true}`), original)
+		HelperFatalEvaluate(t, HelperUnmarshalValue(t, `{"a": {"b": [1, {"c": 1}]}, "d": "e", "f": true}`), cloned)
+	})
+
+	t.Run("scalar", func(t *testing.T) {
+		original := HelperCastString(t, "hello")
+		cloned := fluffyjson.Clone(original).(*fluffyjson.String)
+		*cloned = "world"
+		HelperFatalEvaluate(t, *HelperCastString(t, "hello"), *original)
+	})
+}
+
+func TestCowValue(t *testing.T) {
+	const target = `{"a": {"b": [1, {"c": null}]}, "d": {"e": [2, 3]}}`
+
+	testcases := map[string]struct {
+		mutate   func(c *fluffyjson.CowValue) error
+		expected string
+	}{
+		"set": {
+			mutate: func(c *fluffyjson.CowValue) error {
+				return c.Set(HelperFatalParsePointer(t, "/a/b/1/c"), HelperCastNumber(t, 1))
+			},
+			expected: `{"a": {"b": [1, {"c": 1}]}, "d": {"e": [2, 3]}}`,
+		},
+		"insert": {
+			mutate: func(c *fluffyjson.CowValue) error {
+				return c.Insert(HelperFatalParsePointer(t, "/a/b/0"), HelperCastNumber(t, 0))
+			},
+			expected: `{"a": {"b": [0, 1, {"c": null}]}, "d": {"e": [2, 3]}}`,
+		},
+		"delete": {
+			mutate: func(c *fluffyjson.CowValue) error {
+				return c.Delete(HelperFatalParsePointer(t, "/a/b/1/c"))
+			},
+			expected: `{"a": {"b": [1, {}]}, "d": {"e": [2, 3]}}`,
+		},
+		"create missing": {
+			mutate: func(c *fluffyjson.CowValue) error {
+				return c.Set(HelperFatalParsePointer(t, "/a/x/y"), HelperCastBool(t, true), fluffyjson.CreateMissing())
+			},
+			expected: `{"a": {"b": [1, {"c": null}], "x": {"y": true}}, "d": {"e": [2, 3]}}`,
+		},
+		"root": {
+			mutate: func(c *fluffyjson.CowValue) error {
+				return c.Set(fluffyjson.Pointer{}, HelperCastNumber(t, 1))
+			},
+			expected: `1`,
+		},
+		"multiple": {
+			mutate: func(c *fluffyjson.CowValue) error {
+				for _, p := range []string{"/a/b/1/c", "/a/b/0", "/a/b/0"} {
+					if err := c.Delete(HelperFatalParsePointer(t, p)); err != nil {
+						return err
+					}
+				}
+				return c.Set(HelperFatalParsePointer(t, "/a/z"), HelperCastNull(t, nil))
+			},
+			expected: `{"a": {"b": [], "z": null}, "d": {"e": [2, 3]}}`,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			original := HelperUnmarshalValue(t, target)
+			cloned := fluffyjson.Clone(&original, fluffyjson.CopyOnWrite()).(*fluffyjson.CowValue)
+			another := cloned.Clone()
+			if err := tc.mutate(cloned); err != nil {
+				t.Fatal(err)
+			}
+
+			expected := HelperUnmarshalValue(t, tc.expected)
+			HelperFatalEvaluate(t, true, fluffyjson.Equal(&expected, cloned))
+			HelperFatalEvaluate(t, HelperUnmarshalValue(t, target), original)
+			HelperFatalEvaluate(t, true, fluffyjson.Equal(&original, another))
+		})
+	}
+
+	t.Run("share untouched", func(t *testing.T) {
+		original := HelperUnmarshalValue(t, target)
+		cloned := fluffyjson.Clone(&original, fluffyjson.CopyOnWrite()).(*fluffyjson.CowValue)
+		if err := cloned.Set(HelperFatalParsePointer(t, "/a/b/0"), HelperCastNumber(t, 0)); err != nil {
+			t.Fatal(err)
+		}
+
+		o, err := original.Access(HelperFatalParsePointer(t, "/d"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, err := cloned.Access(HelperFatalParsePointer(t, "/d"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		HelperFatalEvaluate(t, true, o == c)
+
+		o, err = original.Access(HelperFatalParsePointer(t, "/a"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, err = cloned.Access(HelperFatalParsePointer(t, "/a"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		HelperFatalEvaluate(t, false, o == c)
+	})
+
+	t.Run("mutate original after clone", func(t *testing.T) {
+		original := HelperUnmarshalValue(t, target)
+		cow := fluffyjson.Clone(&original, fluffyjson.CopyOnWrite()).(*fluffyjson.CowValue)
+		if err := cow.Set(HelperFatalParsePointer(t, "/a/b/0"), HelperCastNumber(t, 0)); err != nil {
+			t.Fatal(err)
+		}
+		owned, err := cow.Access(HelperFatalParsePointer(t, "/a"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cloned := fluffyjson.Clone(cow, fluffyjson.CopyOnWrite()).(*fluffyjson.CowValue)
+		if err := cow.Set(HelperFatalParsePointer(t, "/a/b/1/c"), HelperCastNumber(t, 1)); err != nil {
+			t.Fatal(err)
+		}
+		if err := cloned.Delete(HelperFatalParsePointer(t, "/d/e/0")); err != nil {
+			t.Fatal(err)
+		}
+
+		mutated, expected := HelperUnmarshalValue(t, `{"a": {"b": [0, {"c": 1}]}, "d": {"e": [2, 3]}}`), HelperUnmarshalValue(t, `{"a": {"b": [0, {"c": null}]}, "d": {"e": [3]}}`)
+		HelperFatalEvaluate(t, true, fluffyjson.Equal(&mutated, cow))
+		HelperFatalEvaluate(t, true, fluffyjson.Equal(&expected, cloned))
+		HelperFatalEvaluate(t, HelperUnmarshalValue(t, target), original)
+
+		// the original keeps mutating its own copies in place after clone
+		a, err := cow.Access(HelperFatalParsePointer(t, "/a"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		HelperFatalEvaluate(t, true, owned == a)
+	})
+
+	t.Run("failed mutation", func(t *testing.T) {
+		original := HelperUnmarshalValue(t, target)
+		cloned := fluffyjson.Clone(&original, fluffyjson.CopyOnWrite()).(*fluffyjson.CowValue)
+		err := cloned.Set(HelperFatalParsePointer(t, "/a/b/5"), HelperCastNumber(t, 0))
+		HelperFatalEvaluateError(t, true, fluffyjson.Equal(&original, cloned), fluffyjson.ErrOutOfRange{}, err)
+		HelperFatalEvaluate(t, HelperUnmarshalValue(t, target), original)
+	})
+}
@@ -0,0 +1,171 @@
+package fluffyjson
+
+import (
+	"maps"
+	"slices"
+)
+
+// Diff returns the JSON Patch that turns a into b. Objects and arrays are compared recursively,
+// and arrays are aligned by the longest common subsequence with replacement, so that an inserted element is reported as single add.
+// Object members are reported in key order.
+func Diff(a, b JsonValue) Patch {
+	return differ{equal: equal}.diff(a, b)
+}
+
+// differ compares the values with the equality, which may tolerate some differences.
+type differ struct {
+	equal func(a, b JsonValue) bool
+}
+
+func (d differ) diff(a, b JsonValue) Patch {
+	patch := make(Patch, 0)
+	d.diffValue(unwrapRoot(a), unwrapRoot(b), Pointer{}, &patch)
+	return patch
+}
+func (d differ) diffValue(a, b JsonValue, ptr Pointer, patch *Patch) {
+	if d.equal(a, b) {
+		return
+	}
+	switch at := a.(type) {
+	case *Object:
+		if bt, ok := b.(*Object); ok {
+			d.diffObject(*at, *bt, ptr, patch)
+			return
+		}
+	case *Array:
+		if bt, ok := b.(*Array); ok {
+			d.diffArray(*at, *bt, ptr, patch)
+			return
+		}
+	}
+	*patch = append(*patch, PatchOperation{Op: "replace", Path: ptr, Value: deepCopy(b)})
+}
+
+func (d differ) diffObject(a, b Object, ptr Pointer, patch *Patch) {
+	for _, k := range slices.Sorted(maps.Keys(a)) {
+		if _, ok := b[k]; !ok {
+			*patch = append(*patch, PatchOperation{Op: "remove", Path: append(slices.Clip(ptr), KeyAccess(k))})
+		}
+	}
+	for _, k := range slices.Sorted(maps.Keys(b)) {
+		if av, ok := a[k]; ok {
+			d.diffValue(unwrapRoot(av), unwrapRoot(b[k]), append(slices.Clip(ptr), KeyAccess(k)), patch)
+		}
+	}
+	for _, k := range slices.Sorted(maps.Keys(b)) {
+		if _, ok := a[k]; !ok {
+			*patch = append(*patch, PatchOperation{Op: "add", Path: append(slices.Clip(ptr), KeyAccess(k)), Value: deepCopy(b[k])})
+		}
+	}
+}
+
+// diffArray aligns the arrays by the edit distance, which extends the longest common subsequence with replacement,
+// so that inserted or removed element does not shift the rest. Index is the position in the array being patched.
+func (d differ) diffArray(a, b Array, ptr Pointer, patch *Patch) {
+	matches := align(len(a), len(b), func(i, j int) bool { return d.equal(a[i], b[j]) })
+	index, i, j := 0, 0, 0
+	for _, match := range append(matches, [2]int{len(a), len(b)}) {
+		// the unmatched elements between the matches are replaced in pairs, and the rest is removed or added
+		for ; i < match[0] && j < match[1]; i, j, index = i+1, j+1, index+1 {
+			d.diffValue(unwrapRoot(a[i]), unwrapRoot(b[j]), append(slices.Clip(ptr), IndexAccess(index)), patch)
+		}
+		for ; i < match[0]; i++ {
+			*patch = append(*patch, PatchOperation{Op: "remove", Path: append(slices.Clip(ptr), IndexAccess(index))})
+		}
+		for ; j < match[1]; j, index = j+1, index+1 {
+			*patch = append(*patch, PatchOperation{Op: "add", Path: append(slices.Clip(ptr), IndexAccess(index)), Value: deepCopy(b[j])})
+		}
+		i, j, index = i+1, j+1, index+1
+	}
+}
+
+// maxAlignment is the maximum number of element pairs compared by [align] for the part that remains after trimming.
+const maxAlignment = 1 << 22
+
+// align returns the index pairs of the equal elements matched by the alignment of the sequences of length n and m
+// with the minimum edit distance, where replacement costs as much as insertion or deletion, in increasing order.
+// The common prefix and suffix are trimmed first, and the rest is solved by Hirschberg's algorithm in linear space,
+// unless it has more than [maxAlignment] pairs, where nothing in the rest is matched.
+func align(n, m int, equal func(i, j int) bool) [][2]int {
+	matches := make([][2]int, 0, min(n, m))
+	var solve func(a0, a1, b0, b1 int)
+	solve = func(a0, a1, b0, b1 int) {
+		for a0 < a1 && b0 < b1 && equal(a0, b0) {
+			matches = append(matches, [2]int{a0, b0})
+			a0, b0 = a0+1, b0+1
+		}
+		suffix := 0
+		for a0 < a1-suffix && b0 < b1-suffix && equal(a1-suffix-1, b1-suffix-1) {
+			suffix++
+		}
+		a1, b1 = a1-suffix, b1-suffix
+
+		switch {
+		case a0 == a1 || b0 == b1:
+		case (a1-a0)*(b1-b0) > maxAlignment:
+			// too large to compare all pairs, so the elements are regarded as replaced one by one
+		case a1-a0 == 1:
+			for j := b0; j < b1; j++ {
+				if equal(a0, j) {
+					matches = append(matches, [2]int{a0, j})
+					break
+				}
+			}
+		default:
+			// split b where the distances of the upper half and the lower half sum to the minimum
+			mid := (a0 + a1) / 2
+			upper, lower := editDistances(a0, mid, b0, b1, equal, false), editDistances(mid, a1, b0, b1, equal, true)
+			split := 0
+			for k := range upper {
+				if upper[k]+lower[k] < upper[split]+lower[split] {
+					split = k
+				}
+			}
+			solve(a0, mid, b0, b0+split)
+			solve(mid, a1, b0+split, b1)
+		}
+
+		for k := range suffix {
+			matches = append(matches, [2]int{a1 + k, b1 + k})
+		}
+	}
+	solve(0, n, 0, m)
+	return matches
+}
+
+// editDistances returns the edit distances between a[a0:a1] and b[b0:b0+k] for each k,
+// or between a[a0:a1] and b[b0+k:b1] if reverse, keeping only two rows.
+func editDistances(a0, a1, b0, b1 int, equal func(i, j int) bool, reverse bool) []int {
+	m := b1 - b0
+	prev, curr := make([]int, m+1), make([]int, m+1)
+	for k := range prev {
+		if prev[k] = k; reverse {
+			prev[k] = m - k
+		}
+	}
+	for r := range a1 - a0 {
+		if !reverse {
+			i := a0 + r
+			curr[0] = prev[0] + 1
+			for k := 1; k <= m; k++ {
+				if equal(i, b0+k-1) {
+					curr[k] = prev[k-1]
+				} else {
+					curr[k] = 1 + min(prev[k-1], prev[k], curr[k-1])
+				}
+			}
+		} else {
+			i := a1 - 1 - r
+			curr[m] = prev[m] + 1
+			for k := m - 1; k >= 0; k-- {
+				if equal(i, b0+k) {
+					curr[k] = prev[k+1]
+				} else {
+					curr[k] = 1 + min(prev[k+1], prev[k], curr[k+1])
+				}
+			}
+		}
+		prev, curr = curr, prev
+	}
+	return prev
+}
@@ -0,0 +1,182 @@
+package fluffyjson_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"testing"
+
+	fluffyjson "github.com/hayas1/go-fluffy-json"
+)
+
+func ExampleDiff() {
+	var a, b fluffyjson.RootValue
+	if err := json.Unmarshal([]byte(`{"name": "alice", "tags": ["a", "c"], "age": 20}`), &a); err != nil {
+		panic(err)
+	}
+	if err := json.Unmarshal([]byte(`{"name": "alice", "tags": ["a", "b", "c"], "email": "alice@example.com"}`), &b); err != nil {
+		panic(err)
+	}
+
+	for _, op := range fluffyjson.Diff(&a, &b) {
+		b, err := json.Marshal(op)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(string(b))
+	}
+	// Output:
+	// {"op":"remove","path":"/age"}
+	// {"op":"add","path":"/tags/1","value":"b"}
+	// {"op":"add","path":"/email","value":"alice@example.com"}
+}
+
+func TestDiff(t *testing.T) {
+	testcases := map[string]struct {
+		a        string
+		b        string
+		expected string
+	}{
+		"equal":              {a: `{"a": [1, {"b": null}]}`, b: `{"a": [1, {"b": null}]}`, expected: `[]`},
+		"replace root":       {a: `{"a": 1}`, b: `[1]`, expected: `[{"op": "replace", "path": "", "value": [1]}]`},
+		"replace scalar":     {a: `1`, b: `2`, expected: `[{"op": "replace", "path": "", "value": 2}]`},
+		"replace member":     {a: `{"a": 1, "b": 2}`, b: `{"a": 1, "b": "2"}`, expected: `[{"op": "replace", "path": "/b", "value": "2"}]`},
+		"add member":         {a: `{"a": 1}`, b: `{"a": 1, "b": {"c": 2}}`, expected: `[{"op": "add", "path": "/b", "value": {"c": 2}}]`},
+		"remove member":      {a: `{"a": 1, "b": 2}`, b: `{"b": 2}`, expected: `[{"op": "remove", "path": "/a"}]`},
+		"nested member":      {a: `{"a": {"b": {"c": 1}}}`, b: `{"a": {"b": {"c": 2}}}`, expected: `[{"op": "replace", "path": "/a/b/c", "value": 2}]`},
+		"escaped key":        {a: `{"a/b": 1, "": 1}`, b: `{"a/b": 2, "": 2}`, expected: `[{"op": "replace", "path": "/", "value": 2}, {"op": "replace", "path": "/a~1b", "value": 2}]`},
+		"insert element":     {a: `[1, 2, 3, 4]`, b: `[1, 2, 0, 3, 4]`, expected: `[{"op": "add", "path": "/2", "value": 0}]`},
+		"insert at head":     {a: `[1, 2, 3]`, b: `[0, 1, 2, 3]`, expected: `[{"op": "add", "path": "/0", "value": 0}]`},
+		"append elements":    {a: `[1]`, b: `[1, 2, 3]`, expected: `[{"op": "add", "path": "/1", "value": 2}, {"op": "add", "path": "/2", "value": 3}]`},
+		"remove element":     {a: `[1, 2, 3, 4]`, b: `[1, 3, 4]`, expected: `[{"op": "remove", "path": "/1"}]`},
+		"remove elements":    {a: `[1, 2, 3, 4]`, b: `[1, 4]`, expected: `[{"op": "remove", "path": "/1"}, {"op": "remove", "path": "/1"}]`},
+		"replace element":    {a: `[1, 2, 3]`, b: `[1, 5, 3]`, expected: `[{"op": "replace", "path": "/1", "value": 5}]`},
+		"nested element":     {a: `[{"a": 1}, {"a": 2}]`, b: `[{"a": 1}, {"a": 3}]`, expected: `[{"op": "replace", "path": "/1/a", "value": 3}]`},
+		"replace and add":    {a: `[1, 2, 3]`, b: `[1, 5, 6, 3]`, expected: `[{"op": "replace", "path": "/1", "value": 5}, {"op": "add", "path": "/2", "value": 6}]`},
+		"replace and remove": {a: `[1, 2, 3, 4]`, b: `[1, 5, 4]`, expected: `[{"op": "replace", "path": "/1", "value": 5}, {"op": "remove", "path": "/2"}]`},
+		"reverse":            {a: `[1, 2, 3]`, b: `[3, 2, 1]`, expected: `[{"op": "replace", "path": "/0", "value": 3}, {"op": "replace", "path": "/2", "value": 1}]`},
+		"empty array":        {a: `[1, 2]`, b: `[]`, expected: `[{"op": "remove", "path": "/0"}, {"op": "remove", "path": "/0"}]`},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			a, b := HelperUnmarshalValue(t, tc.a), HelperUnmarshalValue(t, tc.b)
+			patch := fluffyjson.Diff(&a, &b)
+
+			var expected fluffyjson.Patch
+			if err := json.Unmarshal([]byte(tc.expected), &expected); err != nil {
+				t.Fatal(err)
+			}
+			HelperFatalEvaluate(t, HelperMarshalPatch(t, expected), HelperMarshalPatch(t, patch))
+
+			if err := patch.Apply(&a); err != nil {
+				t.Fatal(err)
+			}
+			HelperFatalEvaluate(t, b, a)
+		})
+	}
+}
+
+func TestDiffApply(t *testing.T) {
+	targets := []string{
+		`null`, `1`, `"a"`, `[]`, `{}`,
+		`[1, 2, 3, 4, 5]`, `[5, 4, 3, 2, 1]`, `[1, [2, 3], {"a": 4}]`, `[[2, 4], {"a": 5}, 1, 1]`,
+		`{"a": 1, "b": [1, 2], "c": {"d": null}}`, `{"a": "1", "b": [2, 1, 2], "c": {"d": null, "e": []}}`,
+		`{"b": [{"x": 1}, {"y": 2}], "c": 3}`, `{"b": [{"y": 2}, {"x": 2}], "f": true}`,
+	}
+	for _, a := range targets {
+		for _, b := range targets {
+			t.Run(a+" "+b, func(t *testing.T) {
+				av, bv := HelperUnmarshalValue(t, a), HelperUnmarshalValue(t, b)
+				patch := fluffyjson.Diff(&av, &bv)
+				if err := patch.Apply(&av); err != nil {
+					t.Fatal(err)
+				}
+				HelperFatalEvaluate(t, bv, av)
+				HelperFatalEvaluate(t, HelperUnmarshalValue(t, b), bv)
+			})
+		}
+	}
+}
+
+func TestDiffArray(t *testing.T) {
+	// distance is the edit distance with replacement by the full table, which the diff of flat arrays should achieve
+	distance := func(a, b []int) int {
+		table := make([][]int, len(a)+1)
+		for i := range table {
+			table[i] = make([]int, len(b)+1)
+			table[i][0] = i
+		}
+		for j := range b {
+			table[0][j+1] = j + 1
+		}
+		for i := range a {
+			for j := range b {
+				if a[i] == b[j] {
+					table[i+1][j+1] = table[i][j]
+				} else {
+					table[i+1][j+1] = 1 + min(table[i][j], table[i][j+1], table[i+1][j])
+				}
+			}
+		}
+		return table[len(a)][len(b)]
+	}
+	array := func(elements []int) fluffyjson.RootValue {
+		b, err := json.Marshal(elements)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return HelperUnmarshalValue(t, string(b))
+	}
+
+	t.Run("minimum", func(t *testing.T) {
+		random := rand.New(rand.NewPCG(1, 2))
+		for range 200 {
+			a, b := make([]int, random.IntN(12)), make([]int, random.IntN(12))
+			for i := range a {
+				a[i] = random.IntN(4)
+			}
+			for i := range b {
+				b[i] = random.IntN(4)
+			}
+			av, bv := array(a), array(b)
+			patch := fluffyjson.Diff(&av, &bv)
+			HelperFatalEvaluate(t, distance(a, b), len(patch))
+			if err := patch.Apply(&av); err != nil {
+				t.Fatal(err)
+			}
+			HelperFatalEvaluate(t, bv, av)
+		}
+	})
+
+	t.Run("large", func(t *testing.T) {
+		a, b := make([]int, 0, 100000), make([]int, 0, 100000)
+		for i := range 100000 {
+			a = append(a, i)
+			if i != 50000 {
+				b = append(b, i)
+			}
+		}
+		av, bv := array(a), array(b)
+		patch := fluffyjson.Diff(&av, &bv)
+		HelperFatalEvaluate(t, `[{"op":"remove","path":"/50000"}]`, HelperMarshalPatch(t, patch))
+
+		// the changes at both ends leave too large part to align, which is replaced element by element
+		b = append(b[1:], -1)
+		bv = array(b)
+		patch = fluffyjson.Diff(&av, &bv)
+		if err := patch.Apply(&av); err != nil {
+			t.Fatal(err)
+		}
+		HelperFatalEvaluate(t, bv, av)
+	})
+}
+
+func HelperMarshalPatch(t *testing.T, patch fluffyjson.Patch) string {
+	t.Helper()
+	b, err := json.Marshal(patch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
@@ -0,0 +1,244 @@
+package fluffyjson
+
+import (
+	"cmp"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+)
+
+type (
+	EqualOption  func(*equalOptions)
+	equalOptions struct {
+		epsilon   float64
+		ignored   []Pointer
+		unordered bool
+	}
+)
+
+// Epsilon regards the numbers whose difference is within the epsilon as equal.
+func Epsilon(epsilon float64) EqualOption {
+	return func(o *equalOptions) { o.epsilon = epsilon }
+}
+
+// IgnorePointers ignores the values matched by the pointers in both sides, which may contain [MultiAccessor].
+// The ignored values are regarded as absent, so the member or element may exist in only one side.
+func IgnorePointers(ptrs ...Pointer) EqualOption {
+	return func(o *equalOptions) { o.ignored = append(o.ignored, ptrs...) }
+}
+
+// UnorderedArrays regards the arrays that have the same elements in any order as equal.
+func UnorderedArrays() EqualOption {
+	return func(o *equalOptions) { o.unordered = true }
+}
+
+// Equal reports whether a and b are structurally equal JSON values.
+func Equal(a, b JsonValue, opts ...EqualOption) bool {
+	var options equalOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if len(options.ignored) > 0 {
+		a, b = prune(a, options.ignored), prune(b, options.ignored)
+	}
+	return options.equal(a, b)
+}
+
+// equal reports whether a and b are structurally equal JSON values without any option.
+func equal(a, b JsonValue) bool {
+	return equalOptions{}.equal(a, b)
+}
+
+func (o equalOptions) equal(a, b JsonValue) bool {
+	a, b = unwrapRoot(a), unwrapRoot(b)
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	switch at := a.(type) {
+	case *Object:
+		bt, ok := b.(*Object)
+		if !ok || len(*at) != len(*bt) {
+			return false
+		}
+		for k, av := range *at {
+			if bv, ok := (*bt)[k]; !ok || !o.equal(av, bv) {
+				return false
+			}
+		}
+		return true
+	case *Array:
+		bt, ok := b.(*Array)
+		if !ok || len(*at) != len(*bt) {
+			return false
+		} else if o.unordered {
+			return o.equalUnordered(*at, *bt)
+		}
+		for i := range *at {
+			if !o.equal((*at)[i], (*bt)[i]) {
+				return false
+			}
+		}
+		return true
+	case *String:
+		bt, ok := b.(*String)
+		return ok && *at == *bt
+	case *Number:
+		bt, ok := b.(*Number)
+		return ok && math.Abs(float64(*at-*bt)) <= o.epsilon
+	case *Bool:
+		bt, ok := b.(*Bool)
+		return ok && *at == *bt
+	case *Null:
+		_, ok := b.(*Null)
+		return ok
+	default:
+		return false
+	}
+}
+
+// equalUnordered finds the perfect matching between the elements by augmenting paths,
+// because the equality within the epsilon is not transitive and the greedy matching may miss it.
+func (o equalOptions) equalUnordered(a, b Array) bool {
+	equals := make([][]bool, len(a))
+	for i := range a {
+		equals[i] = make([]bool, len(b))
+		for j := range b {
+			equals[i][j] = o.equal(a[i], b[j])
+		}
+	}
+
+	// matched[j] is the index of the element of a that b[j] is matched with
+	matched := make([]int, len(b))
+	for j := range matched {
+		matched[j] = -1
+	}
+	var augment func(i int, visited []bool) bool
+	augment = func(i int, visited []bool) bool {
+		for j := range b {
+			if equals[i][j] && !visited[j] {
+				visited[j] = true
+				if matched[j] < 0 || augment(matched[j], visited) {
+					matched[j] = i
+					return true
+				}
+			}
+		}
+		return false
+	}
+	for i := range a {
+		if !augment(i, make([]bool, len(b))) {
+			return false
+		}
+	}
+	return true
+}
+
+// prune returns the copy of the value without the values matched by the pointers, which is nil if the whole value is matched.
+func prune(v JsonValue, ptrs []Pointer) JsonValue {
+	matched := make([]Pointer, 0)
+	for _, ptr := range ptrs {
+		for p := range ptr.Matching(v) {
+			if len(p) == 0 {
+				return nil
+			}
+			matched = append(matched, p)
+		}
+	}
+	// delete from the last in document order, so that removed array element does not shift the others
+	slices.SortFunc(matched, func(a, b Pointer) int { return -comparePointer(a, b) })
+	// the same value may be matched by several pointers, and deleting an array element twice removes its next one
+	matched = slices.CompactFunc(matched, func(a, b Pointer) bool { return comparePointer(a, b) == 0 })
+
+	pruned := RootValue{deepCopy(v)}
+	for _, p := range matched {
+		// the value may be already deleted with its ancestor
+		_ = pruned.Delete(p)
+	}
+	return pruned.JsonValue
+}
+
+// comparePointer compares the pointers that consist of [KeyAccess] and [IndexAccess] in document order.
+func comparePointer(a, b Pointer) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		ai, aok := a[i].(IndexAccess)
+		bi, bok := b[i].(IndexAccess)
+		ak, akok := a[i].(KeyAccess)
+		bk, bkok := b[i].(KeyAccess)
+		c := 0
+		switch {
+		case aok && bok:
+			c = cmp.Compare(ai, bi)
+		case akok && bkok:
+			c = cmp.Compare(ak, bk)
+		default:
+			// other accessors are not expected, but compare them by their representation rather than panic
+			as, _ := Pointer{a[i]}.PointerString()
+			bs, _ := Pointer{b[i]}.PointerString()
+			c = strings.Compare(as, bs)
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(a), len(b))
+}
+
+// Compare compares the values in the total order across kinds, which is the order of jq:
+// null < false < true < numbers < strings < arrays < objects, and nil, which is not a JSON value, is less than null.
+// Arrays are compared element by element, and objects are compared by their sorted keys and then by the values of each key.
+// It returns 0 if and only if the values are [Equal] without any option.
+func Compare(a, b JsonValue) int {
+	a, b = unwrapRoot(a), unwrapRoot(b)
+	rank := func(v JsonValue) int {
+		switch t := v.(type) {
+		case *Bool:
+			if *t {
+				return 2
+			}
+			return 1
+		case *Number:
+			return 3
+		case *String:
+			return 4
+		case *Array:
+			return 5
+		case *Object:
+			return 6
+		case *Null:
+			return 0
+		default:
+			return -1
+		}
+	}
+	if ra, rb := rank(a), rank(b); ra != rb {
+		return cmp.Compare(ra, rb)
+	}
+	switch at := a.(type) {
+	case *Number:
+		return cmp.Compare(*at, *b.(*Number))
+	case *String:
+		return strings.Compare(string(*at), string(*b.(*String)))
+	case *Array:
+		bt := *b.(*Array)
+		for i := 0; i < len(*at) && i < len(bt); i++ {
+			if c := Compare((*at)[i], bt[i]); c != 0 {
+				return c
+			}
+		}
+		return cmp.Compare(len(*at), len(bt))
+	case *Object:
+		bt := *b.(*Object)
+		ak, bk := slices.Sorted(maps.Keys(*at)), slices.Sorted(maps.Keys(bt))
+		if c := slices.Compare(ak, bk); c != 0 {
+			return c
+		}
+		for _, k := range ak {
+			if c := Compare((*at)[k], bt[k]); c != 0 {
+				return c
+			}
+		}
+	}
+	return 0
+}
@@ -0,0 +1,164 @@
+package fluffyjson_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"testing"
+
+	fluffyjson "github.com/hayas1/go-fluffy-json"
+)
+
+func ExampleEqual() {
+	var a, b fluffyjson.RootValue
+	if err := json.Unmarshal([]byte(`{"id": 1, "tags": ["a", "b"], "score": 0.1, "updated": "2024-01-01"}`), &a); err != nil {
+		panic(err)
+	}
+	if err := json.Unmarshal([]byte(`{"id": 1, "tags": ["b", "a"], "score": 0.1000001, "updated": "2024-12-31"}`), &b); err != nil {
+		panic(err)
+	}
+
+	fmt.Println(fluffyjson.Equal(&a, &b))
+	fmt.Println(fluffyjson.Equal(&a, &b,
+		fluffyjson.Epsilon(1e-6),
+		fluffyjson.IgnorePointers(fluffyjson.Pointer{fluffyjson.KeyAccess("updated")}),
+		fluffyjson.UnorderedArrays(),
+	))
+	// Output:
+	// false
+	// true
+}
+
+func TestEqual(t *testing.T) {
+	testcases := map[string]struct {
+		a, b     string
+		opts     []fluffyjson.EqualOption
+		expected bool
+	}{
+		"same":                {a: `{"a": [1, "x", true, null]}`, b: `{"a": [1, "x", true, null]}`, expected: true},
+		"different value":     {a: `{"a": 1}`, b: `{"a": 2}`, expected: false},
+		"different kind":      {a: `{"a": 1}`, b: `{"a": "1"}`, expected: false},
+		"missing member":      {a: `{"a": 1}`, b: `{"a": 1, "b": 2}`, expected: false},
+		"null and missing":    {a: `{"a": null}`, b: `{}`, expected: false},
+		"array order":         {a: `[1, 2]`, b: `[2, 1]`, expected: false},
+		"epsilon":             {a: `{"a": 0.1}`, b: `{"a": 0.10001}`, opts: []fluffyjson.EqualOption{fluffyjson.Epsilon(0.001)}, expected: true},
+		"over epsilon":        {a: `{"a": 0.1}`, b: `{"a": 0.2}`, opts: []fluffyjson.EqualOption{fluffyjson.Epsilon(0.001)}, expected: false},
+		"unordered":           {a: `[1, [2, 3], {"a": 4}]`, b: `[{"a": 4}, [3, 2], 1]`, opts: []fluffyjson.EqualOption{fluffyjson.UnorderedArrays()}, expected: true},
+		"unordered duplicate": {a: `[1, 1, 2]`, b: `[1, 2, 2]`, opts: []fluffyjson.EqualOption{fluffyjson.UnorderedArrays()}, expected: false},
+		"unordered epsilon": {
+			// greedy matching pairs 1.0 with 1.1 and leaves 0.9 unmatched
+			a:        `[1.0, 1.2]`,
+			b:        `[1.1, 0.9]`,
+			opts:     []fluffyjson.EqualOption{fluffyjson.UnorderedArrays(), fluffyjson.Epsilon(0.15)},
+			expected: true,
+		},
+		"ignore": {
+			a:        `{"a": 1, "b": {"c": 2, "d": 3}}`,
+			b:        `{"a": 1, "b": {"c": 4, "d": 3}}`,
+			opts:     []fluffyjson.EqualOption{fluffyjson.IgnorePointers(HelperFatalParsePointer(t, "/b/c"))},
+			expected: true,
+		},
+		"ignore absent": {
+			a:        `{"a": 1, "b": 2}`,
+			b:        `{"a": 1}`,
+			opts:     []fluffyjson.EqualOption{fluffyjson.IgnorePointers(HelperFatalParsePointer(t, "/b"))},
+			expected: true,
+		},
+		"ignore other": {
+			a:        `{"a": 1, "b": 2}`,
+			b:        `{"a": 2, "b": 3}`,
+			opts:     []fluffyjson.EqualOption{fluffyjson.IgnorePointers(HelperFatalParsePointer(t, "/b"))},
+			expected: false,
+		},
+		"ignore wildcard": {
+			a:        `[{"id": 1, "at": 10}, {"id": 2, "at": 20}]`,
+			b:        `[{"id": 1, "at": 11}, {"id": 2, "at": 21}]`,
+			opts:     []fluffyjson.EqualOption{fluffyjson.IgnorePointers(fluffyjson.Pointer{fluffyjson.WildcardAccess{}, fluffyjson.KeyAccess("at")})},
+			expected: true,
+		},
+		"ignore elements": {
+			a: `[0, 1, 2, 3]`,
+			b: `[5, 1, 6, 3]`,
+			opts: []fluffyjson.EqualOption{
+				fluffyjson.IgnorePointers(HelperFatalParsePointer(t, "/0")),
+				fluffyjson.IgnorePointers(HelperFatalParsePointer(t, "/2")),
+			},
+			expected: true,
+		},
+		"ignore duplicated": {
+			a:        `{"a": [0, 1, 2]}`,
+			b:        `{"a": [5, 1, 2]}`,
+			opts:     []fluffyjson.EqualOption{fluffyjson.IgnorePointers(HelperFatalParsePointer(t, "/a/0"), HelperFatalParsePointer(t, "/a/0"))},
+			expected: true,
+		},
+		"ignore overlapped": {
+			a:        `{"a": [0, 1], "b": [2]}`,
+			b:        `{"a": [3], "b": [2]}`,
+			opts:     []fluffyjson.EqualOption{fluffyjson.IgnorePointers(fluffyjson.Pointer{fluffyjson.KeyAccess("a"), fluffyjson.WildcardAccess{}}, HelperFatalParsePointer(t, "/a/0"))},
+			expected: true,
+		},
+		"ignore duplicated keeps others": {
+			a:        `{"a": [0, 1, 2]}`,
+			b:        `{"a": [5, 6, 2]}`,
+			opts:     []fluffyjson.EqualOption{fluffyjson.IgnorePointers(HelperFatalParsePointer(t, "/a/0"), HelperFatalParsePointer(t, "/a/0"))},
+			expected: false,
+		},
+		"ignore root": {
+			a:        `{"a": 1}`,
+			b:        `[1]`,
+			opts:     []fluffyjson.EqualOption{fluffyjson.IgnorePointers(fluffyjson.Pointer{})},
+			expected: true,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			a, b := HelperUnmarshalValue(t, tc.a), HelperUnmarshalValue(t, tc.b)
+			HelperFatalEvaluate(t, tc.expected, fluffyjson.Equal(&a, &b, tc.opts...))
+			HelperFatalEvaluate(t, tc.expected, fluffyjson.Equal(&b, &a, tc.opts...))
+			HelperFatalEvaluate(t, HelperUnmarshalValue(t, tc.a), a)
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	sorted := []string{
+		`null`, `false`, `true`, `-1`, `0`, `1.5`, `""`, `"a"`, `"b"`,
+		`[]`, `[1]`, `[1, 2]`, `[2]`, `{}`, `{"a": 2}`, `{"a": 1, "b": 1}`, `{"b": 0}`,
+	}
+
+	values := make([]fluffyjson.JsonValue, 0, len(sorted))
+	for _, s := range sorted {
+		v := HelperUnmarshalValue(t, s)
+		values = append(values, &v)
+	}
+	for i := range values {
+		for j := range values {
+			expected := 0
+			if i < j {
+				expected = -1
+			} else if i > j {
+				expected = 1
+			}
+			if actual := fluffyjson.Compare(values[i], values[j]); actual != expected {
+				t.Errorf("Compare(%s, %s) = %d, expected %d", sorted[i], sorted[j], actual, expected)
+			}
+		}
+	}
+
+	null := HelperUnmarshalValue(t, `null`)
+	HelperFatalEvaluate(t, -1, fluffyjson.Compare(nil, &null))
+	HelperFatalEvaluate(t, 1, fluffyjson.Compare(&null, nil))
+	HelperFatalEvaluate(t, 0, fluffyjson.Compare(nil, nil))
+
+	shuffled := slices.Clone(values)
+	slices.Reverse(shuffled)
+	slices.SortFunc(shuffled, fluffyjson.Compare)
+	for i := range values {
+		HelperFatalEvaluate(t, true, fluffyjson.Equal(values[i], shuffled[i]))
+	}
+
+	duplicated := append(slices.Clone(values), values...)
+	slices.SortFunc(duplicated, fluffyjson.Compare)
+	HelperFatalEvaluate(t, len(values), len(slices.CompactFunc(duplicated, func(a, b fluffyjson.JsonValue) bool { return fluffyjson.Equal(a, b) })))
+}
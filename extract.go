@@ -0,0 +1,346 @@
+package fluffyjson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type (
+	// Options of `fluffy:"..."` struct tag.
+	//   - `fluffy:"/pointer"` is required: missing value is reported as [ErrNotFound] or [ErrOutOfRange].
+	//   - `fluffy:"/pointer,optional"` leaves zero value if the value is missing.
+	//   - `fluffy:"/pointer,default=value"` assigns the default if the value is missing.
+	//     The default is parsed as JSON, or used as a string if it is not valid JSON.
+	//   - `fluffy:"/pointer,fuzzy"` resolves the pointer as [FuzzyPointer].
+	fluffyTag struct {
+		pointer    Pointer
+		optional   bool
+		fuzzy      bool
+		defaultVal JsonValue
+	}
+
+	ErrExtract struct {
+		Fields []ErrExtractField
+	}
+	ErrExtractField struct {
+		Field   string
+		Pointer string
+		Err     error
+	}
+	ErrExtractTarget struct {
+		Target any
+	}
+	ErrTag struct {
+		Tag    string
+		Reason string
+	}
+)
+
+const fluffyTagName = "fluffy"
+
+var (
+	jsonValueType = reflect.TypeFor[JsonValue]()
+	rootValueType = reflect.TypeFor[RootValue]()
+)
+
+func (e ErrExtract) Error() string {
+	msgs := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		msgs = append(msgs, f.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+func (e ErrExtract) Unwrap() []error {
+	errs := make([]error, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		errs = append(errs, f)
+	}
+	return errs
+}
+func (e ErrExtractField) Error() string {
+	return fmt.Sprintf("field %s (%s): %s", e.Field, e.Pointer, e.Err)
+}
+func (e ErrExtractField) Unwrap() error {
+	return e.Err
+}
+func (e ErrExtractTarget) Error() string {
+	return fmt.Sprintf("extract target must be non-nil pointer to struct, got %T", e.Target)
+}
+func (e ErrTag) Error() string {
+	return fmt.Sprintf("invalid tag %q: %s", e.Tag, e.Reason)
+}
+
+// Extract fills the fields of struct pointed by dst from v according to `fluffy:"/json/pointer"` tags.
+// Every failed field is reported in [ErrExtract].
+func Extract(v JsonValue, dst any) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return ErrExtractTarget{Target: dst}
+	}
+	if fields := extractStruct(v, rv.Elem(), ""); len(fields) > 0 {
+		return ErrExtract{Fields: fields}
+	}
+	return nil
+}
+
+func extractStruct(v JsonValue, rv reflect.Value, prefix string) []ErrExtractField {
+	var failed []ErrExtractField
+	rt := rv.Type()
+	for i := range rt.NumField() {
+		field := rt.Field(i)
+		raw, ok := field.Tag.Lookup(fluffyTagName)
+		if !ok || raw == "-" || !field.IsExported() {
+			continue
+		}
+		name := prefix + field.Name
+		pointer, _, _ := strings.Cut(raw, ",")
+
+		tag, err := parseFluffyTag(raw)
+		if err != nil {
+			failed = append(failed, ErrExtractField{Field: name, Pointer: pointer, Err: err})
+			continue
+		}
+
+		var accessor Accessor = tag.pointer
+		if tag.fuzzy {
+			accessor = FuzzyPointer(tag.pointer)
+		}
+		value, err := accessor.Accessing(v)
+		if missing := errors.Is(err, ErrNotFound{}) || errors.Is(err, ErrOutOfRange{}); missing && tag.defaultVal != nil {
+			value = tag.defaultVal
+		} else if missing && tag.optional {
+			continue
+		} else if err != nil {
+			failed = append(failed, ErrExtractField{Field: name, Pointer: pointer, Err: err})
+			continue
+		}
+
+		if inner := indirectStruct(rv.Field(i)); inner.IsValid() {
+			failed = append(failed, extractStruct(value, inner, name+".")...)
+		} else if err := assign(rv.Field(i), value); err != nil {
+			failed = append(failed, ErrExtractField{Field: name, Pointer: pointer, Err: err})
+		}
+	}
+	return failed
+}
+
+func parseFluffyTag(raw string) (fluffyTag, error) {
+	var tag fluffyTag
+	p, opts, _ := strings.Cut(raw, ",")
+	pointer, err := ParsePointer(p)
+	if err != nil {
+		return tag, ErrTag{Tag: raw, Reason: err.Error()}
+	}
+	tag.pointer = pointer
+
+	for opts != "" {
+		var opt string
+		if strings.HasPrefix(opts, "default=") {
+			// default value may contain comma, so it consumes the rest of the tag
+			opt, opts = opts, ""
+		} else {
+			opt, opts, _ = strings.Cut(opts, ",")
+		}
+
+		switch {
+		case opt == "optional":
+			tag.optional = true
+		case opt == "required":
+			tag.optional = false
+		case opt == "fuzzy":
+			tag.fuzzy = true
+		case strings.HasPrefix(opt, "default="):
+			def := strings.TrimPrefix(opt, "default=")
+			var value RootValue
+			if json.Valid([]byte(def)) {
+				if err := json.Unmarshal([]byte(def), &value); err != nil {
+					return tag, ErrTag{Tag: raw, Reason: err.Error()}
+				}
+			} else {
+				s := String(def)
+				value.JsonValue = &s
+			}
+			tag.defaultVal = value.JsonValue
+		default:
+			return tag, ErrTag{Tag: raw, Reason: fmt.Sprintf("unknown option %s", opt)}
+		}
+	}
+	return tag, nil
+}
+
+// indirectStruct returns the struct that has fluffy tags, allocating the pointer if needed.
+func indirectStruct(rv reflect.Value) reflect.Value {
+	rt := rv.Type()
+	for rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+	if rt.Kind() != reflect.Struct || !hasFluffyTag(rt) {
+		return reflect.Value{}
+	}
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		rv = rv.Elem()
+	}
+	return rv
+}
+func hasFluffyTag(rt reflect.Type) bool {
+	for i := range rt.NumField() {
+		if _, ok := rt.Field(i).Tag.Lookup(fluffyTagName); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// assign decodes v into rv, converting numbers into integer types with range checks
+// and falling back to JSON roundtrip for the types that implement [json.Unmarshaler] or cannot be decoded directly.
+func assign(rv reflect.Value, v JsonValue) error {
+	v = unwrapRoot(v)
+	switch rv.Type() {
+	case jsonValueType:
+		rv.Set(reflect.ValueOf(v))
+		return nil
+	case rootValueType:
+		rv.Set(reflect.ValueOf(RootValue{v}))
+		return nil
+	}
+
+	switch p := rv.Addr().Interface().(type) {
+	case *Object:
+		o, err := v.AsObject()
+		*p = o
+		return err
+	case *Array:
+		a, err := v.AsArray()
+		*p = a
+		return err
+	case *String:
+		s, err := v.AsString()
+		*p = s
+		return err
+	case *Number:
+		n, err := v.AsNumber()
+		*p = n
+		return err
+	case *Bool:
+		b, err := v.AsBool()
+		*p = b
+		return err
+	case *Null:
+		n, err := v.AsNull()
+		*p = n
+		return err
+	case json.Unmarshaler:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		return p.UnmarshalJSON(b)
+	}
+
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+		if v.IsNull() {
+			rv.SetZero()
+			return nil
+		}
+	}
+
+	switch rv.Kind() {
+	case reflect.Pointer:
+		elem := reflect.New(rv.Type().Elem())
+		if err := assign(elem.Elem(), v); err != nil {
+			return err
+		}
+		rv.Set(elem)
+		return nil
+	case reflect.Bool:
+		b, err := v.AsBool()
+		rv.SetBool(bool(b))
+		return err
+	case reflect.String:
+		s, err := v.AsString()
+		rv.SetString(string(s))
+		return err
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := asInt64(v.AsNumber())
+		if err != nil {
+			return err
+		} else if rv.OverflowInt(i) {
+			return ErrOverflow{Number: Number(i), Type: rv.Type().String()}
+		}
+		rv.SetInt(i)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := asUint64(v.AsNumber())
+		if err != nil {
+			return err
+		} else if rv.OverflowUint(u) {
+			return ErrOverflow{Number: Number(u), Type: rv.Type().String()}
+		}
+		rv.SetUint(u)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		n, err := v.AsNumber()
+		if err != nil {
+			return err
+		} else if rv.OverflowFloat(float64(n)) {
+			return ErrOverflow{Number: n, Type: rv.Type().String()}
+		}
+		rv.SetFloat(float64(n))
+		return nil
+	case reflect.Slice, reflect.Array:
+		a, err := v.AsArray()
+		if err != nil {
+			return err
+		}
+		if rv.Kind() == reflect.Slice {
+			rv.Set(reflect.MakeSlice(rv.Type(), len(a), len(a)))
+		} else {
+			// like encoding/json, the extra elements are dropped and the missing elements are left zero
+			rv.SetZero()
+			a = a[:min(len(a), rv.Len())]
+		}
+		for i, elem := range a {
+			if err := assign(rv.Index(i), elem); err != nil {
+				return prefixed(Pointer{IndexAccess(i)}, err)
+			}
+		}
+		return nil
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			break
+		}
+		o, err := v.AsObject()
+		if err != nil {
+			return err
+		}
+		rv.Set(reflect.MakeMapWithSize(rv.Type(), len(o)))
+		for k, elem := range o {
+			value := reflect.New(rv.Type().Elem()).Elem()
+			if err := assign(value, elem); err != nil {
+				return prefixed(Pointer{KeyAccess(k)}, err)
+			}
+			rv.SetMapIndex(reflect.ValueOf(k).Convert(rv.Type().Key()), value)
+		}
+		return nil
+	case reflect.Struct:
+		if hasFluffyTag(rv.Type()) {
+			if fields := extractStruct(v, rv, ""); len(fields) > 0 {
+				return ErrExtract{Fields: fields}
+			}
+			return nil
+		}
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, rv.Addr().Interface())
+}
@@ -0,0 +1,124 @@
+package fluffyjson_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	fluffyjson "github.com/hayas1/go-fluffy-json"
+)
+
+func ExampleExtract() {
+	target := `{"data":{"items":[{"id":"one","count":1},{"id":"two","count":2}]}}`
+	var value fluffyjson.RootValue
+	if err := json.Unmarshal([]byte(target), &value); err != nil {
+		panic(err)
+	}
+
+	var dst struct {
+		Id    string  `fluffy:"/data/items/0/id"`
+		Count float64 `fluffy:"/data/items/1/count"`
+		Limit int     `fluffy:"/data/limit,default=10"`
+	}
+	if err := fluffyjson.Extract(&value, &dst); err != nil {
+		panic(err)
+	}
+	fmt.Println(dst.Id, dst.Count, dst.Limit) // Output: one 2 10
+}
+
+type TestExtractInner struct {
+	Name fluffyjson.String `fluffy:"/name"`
+	Tags []string          `fluffy:"/tags,optional"`
+}
+type TestExtract struct {
+	Id       string               `fluffy:"/id"`
+	Number   fluffyjson.Number    `fluffy:"/number"`
+	Value    fluffyjson.JsonValue `fluffy:"/value,optional"`
+	Enabled  *bool                `fluffy:"/enabled,default=true"`
+	Label    string               `fluffy:"/label,default=hello, world"`
+	Inner    TestExtractInner     `fluffy:"/inner"`
+	Untagged string
+}
+
+func TestExtractStruct(t *testing.T) {
+	t.Run("extract", func(t *testing.T) {
+		testcases := map[string]struct {
+			target   string
+			expected TestExtract
+		}{
+			"all fields": {
+				target: `{"id":"a","number":1,"value":[true],"enabled":false,"label":"x","inner":{"name":"b","tags":["c"]}}`,
+				expected: TestExtract{
+					Id:      "a",
+					Number:  1,
+					Value:   &fluffyjson.Array{HelperCastBool(t, true)},
+					Enabled: &[]bool{false}[0],
+					Label:   "x",
+					Inner:   TestExtractInner{Name: "b", Tags: []string{"c"}},
+				},
+			},
+			"defaults and optional": {
+				target: `{"id":"a","number":1,"inner":{"name":"b"}}`,
+				expected: TestExtract{
+					Id:      "a",
+					Number:  1,
+					Enabled: &[]bool{true}[0],
+					Label:   "hello, world",
+					Inner:   TestExtractInner{Name: "b"},
+				},
+			},
+		}
+
+		for name, tc := range testcases {
+			t.Run(name, func(t *testing.T) {
+				value := HelperUnmarshalValue(t, tc.target)
+				var actual TestExtract
+				err := fluffyjson.Extract(&value, &actual)
+				HelperFatalEvaluateError(t, tc.expected, actual, nil, err)
+			})
+		}
+	})
+
+	t.Run("every failed field", func(t *testing.T) {
+		value := HelperUnmarshalValue(t, `{"number":"one","inner":{}}`)
+		var actual TestExtract
+		err := fluffyjson.Extract(&value, &actual)
+
+		var extractErr fluffyjson.ErrExtract
+		if !errors.As(err, &extractErr) {
+			t.Fatal(err)
+		}
+		fields := make([]string, 0, len(extractErr.Fields))
+		for _, f := range extractErr.Fields {
+			fields = append(fields, f.Field)
+		}
+		HelperFatalEvaluate(t, []string{"Id", "Number", "Inner.Name"}, fields)
+		if !errors.Is(err, fluffyjson.ErrNotFound{}) {
+			t.Fatal(err)
+		}
+		if !errors.Is(err, fluffyjson.ErrAsValue{Expected: fluffyjson.NUMBER, Actual: fluffyjson.STRING}) {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("fuzzy", func(t *testing.T) {
+		value := HelperUnmarshalValue(t, `{"user_info": {"UserID": 1}}`)
+		var actual struct {
+			UserId int `fluffy:"/userInfo/userId,fuzzy"`
+		}
+		err := fluffyjson.Extract(&value, &actual)
+		HelperFatalEvaluateError(t, 1, actual.UserId, nil, err)
+	})
+
+	t.Run("invalid target", func(t *testing.T) {
+		value := HelperUnmarshalValue(t, `{}`)
+		var actual TestExtract
+		err := fluffyjson.Extract(&value, actual)
+
+		var targetErr fluffyjson.ErrExtractTarget
+		if !errors.As(err, &targetErr) {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,372 @@
+package fluffyjson
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type (
+	// AsFormat parses the string in the specific format. Each conversion parses strictly:
+	//   - AsTime: RFC 3339 timestamp, such as "2006-01-02T15:04:05Z".
+	//   - AsDuration: Go duration by [time.ParseDuration], such as "1h30m".
+	//   - AsBytes: standard base64 with padding.
+	//   - AsUUID: hyphenated hex UUID, such as "123e4567-e89b-12d3-a456-426614174000".
+	//   - AsURL: absolute URL with scheme.
+	AsFormat interface {
+		AsTime() (time.Time, error)
+		AsDuration() (time.Duration, error)
+		AsBytes() ([]byte, error)
+		AsUUID() (UUID, error)
+		AsURL() (*url.URL, error)
+	}
+	AccessAsFormat interface {
+		AccessAsTime(...Accessor) (time.Time, error)
+		SliceAsTime(SliceAccessor) ([]time.Time, error)
+		AccessAsDuration(...Accessor) (time.Duration, error)
+		SliceAsDuration(SliceAccessor) ([]time.Duration, error)
+		AccessAsBytes(...Accessor) ([]byte, error)
+		SliceAsBytes(SliceAccessor) ([][]byte, error)
+		AccessAsUUID(...Accessor) (UUID, error)
+		SliceAsUUID(SliceAccessor) ([]UUID, error)
+		AccessAsURL(...Accessor) (*url.URL, error)
+		SliceAsURL(SliceAccessor) ([]*url.URL, error)
+	}
+
+	UUID [16]byte
+
+	// ErrFormat is returned when the string is not in the expected format.
+	// Pointer is the path to the string from the accessed value.
+	ErrFormat struct {
+		Pointer Pointer
+		Format  string
+		Value   string
+		Err     error
+	}
+)
+
+func (e ErrFormat) Error() string {
+	return fmt.Sprintf("invalid %s %q at %s: %s", e.Format, e.Value, e.Pointer.location(), e.Err)
+}
+func (e ErrFormat) Is(target error) bool {
+	_, ok := target.(ErrFormat)
+	return ok
+}
+func (e ErrFormat) Unwrap() error {
+	return e.Err
+}
+
+func (u UUID) String() string {
+	h := hex.EncodeToString(u[:])
+	return h[:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:]
+}
+
+func CastTime(t time.Time) (String, error)         { return String(t.Format(time.RFC3339Nano)), nil }
+func CastDuration(d time.Duration) (String, error) { return String(d.String()), nil }
+func CastBytes(b []byte) (String, error)           { return String(base64.StdEncoding.EncodeToString(b)), nil }
+func CastUUID(u UUID) (String, error)              { return String(u.String()), nil }
+func CastURL(u *url.URL) (String, error) {
+	if u == nil {
+		return "", fmt.Errorf("nil URL")
+	}
+	return String(u.String()), nil
+}
+
+func asTime(s String, err error) (time.Time, error) {
+	return asFormat(s, err, "date-time", func(s string) (time.Time, error) { return time.Parse(time.RFC3339Nano, s) })
+}
+func asDuration(s String, err error) (time.Duration, error) {
+	return asFormat(s, err, "duration", time.ParseDuration)
+}
+func asBytes(s String, err error) ([]byte, error) {
+	return asFormat(s, err, "base64", base64.StdEncoding.Strict().DecodeString)
+}
+func asUUID(s String, err error) (UUID, error) {
+	return asFormat(s, err, "uuid", func(s string) (UUID, error) {
+		var u UUID
+		if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+			return u, errors.New("not in 8-4-4-4-12 form")
+		}
+		if n, err := hex.Decode(u[:], []byte(strings.ReplaceAll(s, "-", ""))); err != nil {
+			return u, err
+		} else if n != len(u) {
+			return u, errors.New("not in 8-4-4-4-12 form")
+		}
+		return u, nil
+	})
+}
+func asURL(s String, err error) (*url.URL, error) {
+	return asFormat(s, err, "uri", func(s string) (*url.URL, error) {
+		u, err := url.Parse(s)
+		if err == nil && !u.IsAbs() {
+			return nil, errors.New("not absolute")
+		}
+		return u, err
+	})
+}
+func asFormat[T any](s String, err error, format string, parse func(string) (T, error)) (T, error) {
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	t, err := parse(string(s))
+	if err != nil {
+		var zero T
+		return zero, ErrFormat{Format: format, Value: string(s), Err: err}
+	}
+	return t, nil
+}
+
+func accessAsFormat[T any](v JsonValue, ptr []Accessor, as func(String, error) (T, error)) (T, error) {
+	s, err := accessAsString(v, ptr...)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	t, err := as(s, nil)
+	return t, prefixed(ptr, err)
+}
+func sliceAsFormat[T any](v JsonValue, acc SliceAccessor, as func(String, error) (T, error)) ([]T, error) {
+	slice := make([]T, 0)
+	if m, ok := acc.(MultiAccessor); ok {
+		// the pointer of each value is known, so it is reported in the error
+		for p, v := range m.Matching(v) {
+			t, err := as(v.AsString())
+			if err != nil {
+				return nil, prefixed(p, err)
+			}
+			slice = append(slice, t)
+		}
+		return slice, nil
+	}
+
+	vs, err := acc.Slicing(v)
+	if err != nil {
+		return nil, err
+	}
+	for i, v := range vs {
+		t, err := as(v.AsString())
+		if err != nil {
+			// the pointer of each value is unknown, so its index in the sliced values is reported instead
+			return nil, prefixed(Pointer{IndexAccess(i)}, err)
+		}
+		slice = append(slice, t)
+	}
+	return slice, nil
+}
+
+func (o Object) AsTime() (time.Time, error)         { return asTime(o.AsString()) }
+func (o Object) AsDuration() (time.Duration, error) { return asDuration(o.AsString()) }
+func (o Object) AsBytes() ([]byte, error)           { return asBytes(o.AsString()) }
+func (o Object) AsUUID() (UUID, error)              { return asUUID(o.AsString()) }
+func (o Object) AsURL() (*url.URL, error)           { return asURL(o.AsString()) }
+func (a Array) AsTime() (time.Time, error)          { return asTime(a.AsString()) }
+func (a Array) AsDuration() (time.Duration, error)  { return asDuration(a.AsString()) }
+func (a Array) AsBytes() ([]byte, error)            { return asBytes(a.AsString()) }
+func (a Array) AsUUID() (UUID, error)               { return asUUID(a.AsString()) }
+func (a Array) AsURL() (*url.URL, error)            { return asURL(a.AsString()) }
+func (s String) AsTime() (time.Time, error)         { return asTime(s.AsString()) }
+func (s String) AsDuration() (time.Duration, error) { return asDuration(s.AsString()) }
+func (s String) AsBytes() ([]byte, error)           { return asBytes(s.AsString()) }
+func (s String) AsUUID() (UUID, error)              { return asUUID(s.AsString()) }
+func (s String) AsURL() (*url.URL, error)           { return asURL(s.AsString()) }
+func (n Number) AsTime() (time.Time, error)         { return asTime(n.AsString()) }
+func (n Number) AsDuration() (time.Duration, error) { return asDuration(n.AsString()) }
+func (n Number) AsBytes() ([]byte, error)           { return asBytes(n.AsString()) }
+func (n Number) AsUUID() (UUID, error)              { return asUUID(n.AsString()) }
+func (n Number) AsURL() (*url.URL, error)           { return asURL(n.AsString()) }
+func (b Bool) AsTime() (time.Time, error)           { return asTime(b.AsString()) }
+func (b Bool) AsDuration() (time.Duration, error)   { return asDuration(b.AsString()) }
+func (b Bool) AsBytes() ([]byte, error)             { return asBytes(b.AsString()) }
+func (b Bool) AsUUID() (UUID, error)                { return asUUID(b.AsString()) }
+func (b Bool) AsURL() (*url.URL, error)             { return asURL(b.AsString()) }
+func (n Null) AsTime() (time.Time, error)           { return asTime(n.AsString()) }
+func (n Null) AsDuration() (time.Duration, error)   { return asDuration(n.AsString()) }
+func (n Null) AsBytes() ([]byte, error)             { return asBytes(n.AsString()) }
+func (n Null) AsUUID() (UUID, error)                { return asUUID(n.AsString()) }
+func (n Null) AsURL() (*url.URL, error)             { return asURL(n.AsString()) }
+
+func (o Object) AccessAsTime(ptr ...Accessor) (time.Time, error) {
+	return accessAsFormat(&o, ptr, asTime)
+}
+func (o Object) SliceAsTime(acc SliceAccessor) ([]time.Time, error) {
+	return sliceAsFormat(&o, acc, asTime)
+}
+func (o Object) AccessAsDuration(ptr ...Accessor) (time.Duration, error) {
+	return accessAsFormat(&o, ptr, asDuration)
+}
+func (o Object) SliceAsDuration(acc SliceAccessor) ([]time.Duration, error) {
+	return sliceAsFormat(&o, acc, asDuration)
+}
+func (o Object) AccessAsBytes(ptr ...Accessor) ([]byte, error) {
+	return accessAsFormat(&o, ptr, asBytes)
+}
+func (o Object) SliceAsBytes(acc SliceAccessor) ([][]byte, error) {
+	return sliceAsFormat(&o, acc, asBytes)
+}
+func (o Object) AccessAsUUID(ptr ...Accessor) (UUID, error) {
+	return accessAsFormat(&o, ptr, asUUID)
+}
+func (o Object) SliceAsUUID(acc SliceAccessor) ([]UUID, error) {
+	return sliceAsFormat(&o, acc, asUUID)
+}
+func (o Object) AccessAsURL(ptr ...Accessor) (*url.URL, error) {
+	return accessAsFormat(&o, ptr, asURL)
+}
+func (o Object) SliceAsURL(acc SliceAccessor) ([]*url.URL, error) {
+	return sliceAsFormat(&o, acc, asURL)
+}
+func (a Array) AccessAsTime(ptr ...Accessor) (time.Time, error) {
+	return accessAsFormat(&a, ptr, asTime)
+}
+func (a Array) SliceAsTime(acc SliceAccessor) ([]time.Time, error) {
+	return sliceAsFormat(&a, acc, asTime)
+}
+func (a Array) AccessAsDuration(ptr ...Accessor) (time.Duration, error) {
+	return accessAsFormat(&a, ptr, asDuration)
+}
+func (a Array) SliceAsDuration(acc SliceAccessor) ([]time.Duration, error) {
+	return sliceAsFormat(&a, acc, asDuration)
+}
+func (a Array) AccessAsBytes(ptr ...Accessor) ([]byte, error) {
+	return accessAsFormat(&a, ptr, asBytes)
+}
+func (a Array) SliceAsBytes(acc SliceAccessor) ([][]byte, error) {
+	return sliceAsFormat(&a, acc, asBytes)
+}
+func (a Array) AccessAsUUID(ptr ...Accessor) (UUID, error) {
+	return accessAsFormat(&a, ptr, asUUID)
+}
+func (a Array) SliceAsUUID(acc SliceAccessor) ([]UUID, error) {
+	return sliceAsFormat(&a, acc, asUUID)
+}
+func (a Array) AccessAsURL(ptr ...Accessor) (*url.URL, error) {
+	return accessAsFormat(&a, ptr, asURL)
+}
+func (a Array) SliceAsURL(acc SliceAccessor) ([]*url.URL, error) {
+	return sliceAsFormat(&a, acc, asURL)
+}
+func (s String) AccessAsTime(ptr ...Accessor) (time.Time, error) {
+	return accessAsFormat(&s, ptr, asTime)
+}
+func (s String) SliceAsTime(acc SliceAccessor) ([]time.Time, error) {
+	return sliceAsFormat(&s, acc, asTime)
+}
+func (s String) AccessAsDuration(ptr ...Accessor) (time.Duration, error) {
+	return accessAsFormat(&s, ptr, asDuration)
+}
+func (s String) SliceAsDuration(acc SliceAccessor) ([]time.Duration, error) {
+	return sliceAsFormat(&s, acc, asDuration)
+}
+func (s String) AccessAsBytes(ptr ...Accessor) ([]byte, error) {
+	return accessAsFormat(&s, ptr, asBytes)
+}
+func (s String) SliceAsBytes(acc SliceAccessor) ([][]byte, error) {
+	return sliceAsFormat(&s, acc, asBytes)
+}
+func (s String) AccessAsUUID(ptr ...Accessor) (UUID, error) {
+	return accessAsFormat(&s, ptr, asUUID)
+}
+func (s String) SliceAsUUID(acc SliceAccessor) ([]UUID, error) {
+	return sliceAsFormat(&s, acc, asUUID)
+}
+func (s String) AccessAsURL(ptr ...Accessor) (*url.URL, error) {
+	return accessAsFormat(&s, ptr, asURL)
+}
+func (s String) SliceAsURL(acc SliceAccessor) ([]*url.URL, error) {
+	return sliceAsFormat(&s, acc, asURL)
+}
+func (n Number) AccessAsTime(ptr ...Accessor) (time.Time, error) {
+	return accessAsFormat(&n, ptr, asTime)
+}
+func (n Number) SliceAsTime(acc SliceAccessor) ([]time.Time, error) {
+	return sliceAsFormat(&n, acc, asTime)
+}
+func (n Number) AccessAsDuration(ptr ...Accessor) (time.Duration, error) {
+	return accessAsFormat(&n, ptr, asDuration)
+}
+func (n Number) SliceAsDuration(acc SliceAccessor) ([]time.Duration, error) {
+	return sliceAsFormat(&n, acc, asDuration)
+}
+func (n Number) AccessAsBytes(ptr ...Accessor) ([]byte, error) {
+	return accessAsFormat(&n, ptr, asBytes)
+}
+func (n Number) SliceAsBytes(acc SliceAccessor) ([][]byte, error) {
+	return sliceAsFormat(&n, acc, asBytes)
+}
+func (n Number) AccessAsUUID(ptr ...Accessor) (UUID, error) {
+	return accessAsFormat(&n, ptr, asUUID)
+}
+func (n Number) SliceAsUUID(acc SliceAccessor) ([]UUID, error) {
+	return sliceAsFormat(&n, acc, asUUID)
+}
+func (n Number) AccessAsURL(ptr ...Accessor) (*url.URL, error) {
+	return accessAsFormat(&n, ptr, asURL)
+}
+func (n Number) SliceAsURL(acc SliceAccessor) ([]*url.URL, error) {
+	return sliceAsFormat(&n, acc, asURL)
+}
+func (b Bool) AccessAsTime(ptr ...Accessor) (time.Time, error) {
+	return accessAsFormat(&b, ptr, asTime)
+}
+func (b Bool) SliceAsTime(acc SliceAccessor) ([]time.Time, error) {
+	return sliceAsFormat(&b, acc, asTime)
+}
+func (b Bool) AccessAsDuration(ptr ...Accessor) (time.Duration, error) {
+	return accessAsFormat(&b, ptr, asDuration)
+}
+func (b Bool) SliceAsDuration(acc SliceAccessor) ([]time.Duration, error) {
+	return sliceAsFormat(&b, acc, asDuration)
+}
+func (b Bool) AccessAsBytes(ptr ...Accessor) ([]byte, error) {
+	return accessAsFormat(&b, ptr, asBytes)
+}
+func (b Bool) SliceAsBytes(acc SliceAccessor) ([][]byte, error) {
+	return sliceAsFormat(&b, acc, asBytes)
+}
+func (b Bool) AccessAsUUID(ptr ...Accessor) (UUID, error) {
+	return accessAsFormat(&b, ptr, asUUID)
+}
+func (b Bool) SliceAsUUID(acc SliceAccessor) ([]UUID, error) {
+	return sliceAsFormat(&b, acc, asUUID)
+}
+func (b Bool) AccessAsURL(ptr ...Accessor) (*url.URL, error) {
+	return accessAsFormat(&b, ptr, asURL)
+}
+func (b Bool) SliceAsURL(acc SliceAccessor) ([]*url.URL, error) {
+	return sliceAsFormat(&b, acc, asURL)
+}
+func (n Null) AccessAsTime(ptr ...Accessor) (time.Time, error) {
+	return accessAsFormat(&n, ptr, asTime)
+}
+func (n Null) SliceAsTime(acc SliceAccessor) ([]time.Time, error) {
+	return sliceAsFormat(&n, acc, asTime)
+}
+func (n Null) AccessAsDuration(ptr ...Accessor) (time.Duration, error) {
+	return accessAsFormat(&n, ptr, asDuration)
+}
+func (n Null) SliceAsDuration(acc SliceAccessor) ([]time.Duration, error) {
+	return sliceAsFormat(&n, acc, asDuration)
+}
+func (n Null) AccessAsBytes(ptr ...Accessor) ([]byte, error) {
+	return accessAsFormat(&n, ptr, asBytes)
+}
+func (n Null) SliceAsBytes(acc SliceAccessor) ([][]byte, error) {
+	return sliceAsFormat(&n, acc, asBytes)
+}
+func (n Null) AccessAsUUID(ptr ...Accessor) (UUID, error) {
+	return accessAsFormat(&n, ptr, asUUID)
+}
+func (n Null) SliceAsUUID(acc SliceAccessor) ([]UUID, error) {
+	return sliceAsFormat(&n, acc, asUUID)
+}
+func (n Null) AccessAsURL(ptr ...Accessor) (*url.URL, error) {
+	return accessAsFormat(&n, ptr, asURL)
+}
+func (n Null) SliceAsURL(acc SliceAccessor) ([]*url.URL, error) {
+	return sliceAsFormat(&n, acc, asURL)
+}
@@ -0,0 +1,167 @@
+package fluffyjson_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	fluffyjson "github.com/hayas1/go-fluffy-json"
+)
+
+func ExampleString_AsTime() {
+	var value fluffyjson.RootValue
+	if err := json.Unmarshal([]byte(`{"created": "2024-01-02T03:04:05Z", "ttl": "1h30m", "updated": "yesterday"}`), &value); err != nil {
+		panic(err)
+	}
+
+	created, err := value.AccessAsTime(fluffyjson.KeyAccess("created"))
+	fmt.Println(created.Year(), err)
+	ttl, err := value.AccessAsDuration(fluffyjson.KeyAccess("ttl"))
+	fmt.Println(ttl.Minutes(), err)
+	_, err = value.AccessAsTime(fluffyjson.KeyAccess("updated"))
+	fmt.Println(err)
+	// Output:
+	// 2024 <nil>
+	// 90 <nil>
+	// invalid date-time "yesterday" at /updated: parsing time "yesterday" as "2006-01-02T15:04:05.999999999Z07:00": cannot parse "yesterday" as "2006"
+}
+
+func TestAsFormat(t *testing.T) {
+	uuid := fluffyjson.UUID{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00}
+	testcases := map[string]struct {
+		target   string
+		as       func(v fluffyjson.JsonValue) (any, error)
+		expected any
+		err      error
+	}{
+		"time": {
+			target:   `"2024-01-02T03:04:05.5+09:00"`,
+			as:       func(v fluffyjson.JsonValue) (any, error) { return v.AsTime() },
+			expected: time.Date(2024, 1, 2, 3, 4, 5, 5e8, time.FixedZone("", 9*60*60)),
+		},
+		"time without zone": {
+			target:   `"2024-01-02T03:04:05"`,
+			as:       func(v fluffyjson.JsonValue) (any, error) { return v.AsTime() },
+			expected: time.Time{},
+			err:      fluffyjson.ErrFormat{},
+		},
+		"time from number": {
+			target:   `1704164645`,
+			as:       func(v fluffyjson.JsonValue) (any, error) { return v.AsTime() },
+			expected: time.Time{},
+			err:      fluffyjson.ErrAsValue{Expected: fluffyjson.STRING, Actual: fluffyjson.NUMBER},
+		},
+		"duration": {
+			target:   `"1m30s"`,
+			as:       func(v fluffyjson.JsonValue) (any, error) { return v.AsDuration() },
+			expected: 90 * time.Second,
+		},
+		"invalid duration": {
+			target:   `"90"`,
+			as:       func(v fluffyjson.JsonValue) (any, error) { return v.AsDuration() },
+			expected: time.Duration(0),
+			err:      fluffyjson.ErrFormat{},
+		},
+		"bytes": {
+			target:   `"aGVsbG8="`,
+			as:       func(v fluffyjson.JsonValue) (any, error) { return v.AsBytes() },
+			expected: []byte("hello"),
+		},
+		"bytes without padding": {
+			target:   `"aGVsbG8"`,
+			as:       func(v fluffyjson.JsonValue) (any, error) { return v.AsBytes() },
+			expected: []byte(nil),
+			err:      fluffyjson.ErrFormat{},
+		},
+		"uuid": {
+			target:   `"123E4567-e89b-12d3-a456-426614174000"`,
+			as:       func(v fluffyjson.JsonValue) (any, error) { return v.AsUUID() },
+			expected: uuid,
+		},
+		"uuid without hyphen": {
+			target:   `"123e4567e89b12d3a456426614174000"`,
+			as:       func(v fluffyjson.JsonValue) (any, error) { return v.AsUUID() },
+			expected: fluffyjson.UUID{},
+			err:      fluffyjson.ErrFormat{},
+		},
+		"uuid misplaced hyphen": {
+			target:   `"123e4567-e89b-12d3-a456-4266141740-0"`,
+			as:       func(v fluffyjson.JsonValue) (any, error) { return v.AsUUID() },
+			expected: fluffyjson.UUID{},
+			err:      fluffyjson.ErrFormat{},
+		},
+		"url": {
+			target:   `"https://example.com/a?b=c"`,
+			as:       func(v fluffyjson.JsonValue) (any, error) { return v.AsURL() },
+			expected: &url.URL{Scheme: "https", Host: "example.com", Path: "/a", RawQuery: "b=c"},
+		},
+		"relative url": {
+			target:   `"/a?b=c"`,
+			as:       func(v fluffyjson.JsonValue) (any, error) { return v.AsURL() },
+			expected: (*url.URL)(nil),
+			err:      fluffyjson.ErrFormat{},
+		},
+		"access": {
+			target: `{"a": ["1s", "2s"]}`,
+			as: func(v fluffyjson.JsonValue) (any, error) {
+				return v.AccessAsDuration(fluffyjson.KeyAccess("a"), fluffyjson.IndexAccess(1))
+			},
+			expected: 2 * time.Second,
+		},
+		"slice": {
+			target: `{"a": ["1s", "2s"]}`,
+			as: func(v fluffyjson.JsonValue) (any, error) {
+				return v.SliceAsDuration(fluffyjson.Pointer{fluffyjson.KeyAccess("a"), fluffyjson.WildcardAccess{}})
+			},
+			expected: []time.Duration{time.Second, 2 * time.Second},
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			value := HelperUnmarshalValue(t, tc.target)
+			actual, err := tc.as(&value)
+			HelperFatalEvaluateError(t, tc.expected, actual, tc.err, err)
+		})
+	}
+
+	t.Run("error pointer", func(t *testing.T) {
+		value := HelperUnmarshalValue(t, `{"a": [{"id": "123e4567-e89b-12d3-a456-426614174000"}, {"id": "x"}]}`)
+		var errFormat fluffyjson.ErrFormat
+
+		_, err := value.AccessAsUUID(fluffyjson.KeyAccess("a"), fluffyjson.IndexAccess(-1), fluffyjson.KeyAccess("id"))
+		if !errors.As(err, &errFormat) {
+			t.Fatal(err)
+		}
+		HelperFatalEvaluate(t, fluffyjson.Pointer{fluffyjson.KeyAccess("a"), fluffyjson.IndexAccess(-1), fluffyjson.KeyAccess("id")}, errFormat.Pointer)
+
+		_, err = value.SliceAsUUID(fluffyjson.Pointer{fluffyjson.KeyAccess("a"), fluffyjson.WildcardAccess{}, fluffyjson.KeyAccess("id")})
+		if !errors.As(err, &errFormat) {
+			t.Fatal(err)
+		}
+		HelperFatalEvaluate(t, "/a/1/id", HelperFatalPointerString(t, errFormat.Pointer))
+
+		ids := fluffyjson.Array{HelperCastString(t, "123e4567-e89b-12d3-a456-426614174000"), HelperCastString(t, "x")}
+		_, err = ids.SliceAsUUID(fluffyjson.SliceAccess{Start: -1, End: -3, Step: -1})
+		if !errors.As(err, &errFormat) {
+			t.Fatal(err)
+		}
+		HelperFatalEvaluate(t, "/0", HelperFatalPointerString(t, errFormat.Pointer))
+	})
+
+	t.Run("cast", func(t *testing.T) {
+		tm, err := fluffyjson.CastTime(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+		HelperFatalEvaluateError(t, "2024-01-02T03:04:05Z", tm, nil, err)
+		d, err := fluffyjson.CastDuration(90 * time.Minute)
+		HelperFatalEvaluateError(t, "1h30m0s", d, nil, err)
+		b, err := fluffyjson.CastBytes([]byte("hello"))
+		HelperFatalEvaluateError(t, "aGVsbG8=", b, nil, err)
+		u, err := fluffyjson.CastUUID(uuid)
+		HelperFatalEvaluateError(t, "123e4567-e89b-12d3-a456-426614174000", u, nil, err)
+		l, err := fluffyjson.CastURL(&url.URL{Scheme: "https", Host: "example.com", Path: "/a b"})
+		HelperFatalEvaluateError(t, "https://example.com/a%20b", l, nil, err)
+	})
+}
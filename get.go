@@ -0,0 +1,62 @@
+package fluffyjson
+
+import (
+	"fmt"
+	"reflect"
+)
+
+type (
+	// ErrNotIntegral is returned when the number with fractional part is converted into integer type.
+	// Pointer is the path to the number from the value where the access or conversion started.
+	ErrNotIntegral struct {
+		Pointer Pointer
+		Number  Number
+	}
+	// ErrOverflow is returned when the number does not fit the converted type.
+	// Pointer is the path to the number from the value where the access or conversion started.
+	ErrOverflow struct {
+		Pointer Pointer
+		Number  Number
+		Type    string
+	}
+)
+
+func (e ErrNotIntegral) Error() string {
+	return fmt.Sprintf("%v is not integral at %s", float64(e.Number), e.Pointer.location())
+}
+func (e ErrNotIntegral) Is(target error) bool {
+	_, ok := target.(ErrNotIntegral)
+	return ok
+}
+func (e ErrOverflow) Error() string {
+	return fmt.Sprintf("%v overflows %s at %s", float64(e.Number), e.Type, e.Pointer.location())
+}
+func (e ErrOverflow) Is(target error) bool {
+	_, ok := target.(ErrOverflow)
+	return ok
+}
+
+// Get accesses the value by the pointer and converts it into T.
+// T may be any JSON kind, Go primitive, slice, map with string key, struct with fluffy tags, or type implementing [json.Unmarshaler].
+//   - Integer types fail with [ErrNotIntegral] or [ErrOverflow] instead of truncating.
+//   - Null is converted into nil pointer, slice, map and interface, but fails for other types.
+func Get[T any](v JsonValue, ptr ...Accessor) (T, error) {
+	var t T
+	value, err := Pointer(ptr).Accessing(v)
+	if err != nil {
+		return t, err
+	}
+	if err := assign(reflect.ValueOf(&t).Elem(), value); err != nil {
+		var zero T
+		return zero, prefixed(Pointer(ptr), err)
+	}
+	return t, nil
+}
+
+// GetOr is like [Get], but returns def if the value is missing or cannot be converted into T.
+func GetOr[T any](v JsonValue, def T, ptr ...Accessor) T {
+	if t, err := Get[T](v, ptr...); err == nil {
+		return t
+	}
+	return def
+}
@@ -0,0 +1,201 @@
+package fluffyjson_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	fluffyjson "github.com/hayas1/go-fluffy-json"
+)
+
+func ExampleGet() {
+	var value fluffyjson.RootValue
+	if err := json.Unmarshal([]byte(`{"port": 8080, "hosts": ["a", "b"], "ratio": 0.5}`), &value); err != nil {
+		panic(err)
+	}
+
+	port, err := fluffyjson.Get[int](&value, fluffyjson.KeyAccess("port"))
+	if err != nil {
+		panic(err)
+	}
+	hosts, err := fluffyjson.Get[[]string](&value, fluffyjson.KeyAccess("hosts"))
+	if err != nil {
+		panic(err)
+	}
+	_, err = fluffyjson.Get[int](&value, fluffyjson.KeyAccess("ratio"))
+	timeout := fluffyjson.GetOr(&value, 30, fluffyjson.KeyAccess("timeout"))
+	fmt.Println(port, hosts, timeout)
+	fmt.Println(err)
+	// Output:
+	// 8080 [a b] 30
+	// 0.5 is not integral at /ratio
+}
+
+func TestGet(t *testing.T) {
+	const target = `{
+		"int": 42, "float": 1.5, "negative": -1, "large": 1e20, "string": "hello", "bool": true, "null": null,
+		"time": "2024-01-02T03:04:05Z", "strings": ["a", "b"], "numbers": [1, 2.5], "nested": {"a": {"x": 1}, "b": {"x": 2}}
+	}`
+
+	testcases := map[string]struct {
+		get      func(v fluffyjson.JsonValue) (any, error)
+		expected any
+		err      error
+	}{
+		"int": {
+			get:      func(v fluffyjson.JsonValue) (any, error) { return fluffyjson.Get[int](v, fluffyjson.KeyAccess("int")) },
+			expected: 42,
+		},
+		"int64": {
+			get: func(v fluffyjson.JsonValue) (any, error) {
+				return fluffyjson.Get[int64](v, fluffyjson.KeyAccess("negative"))
+			},
+			expected: int64(-1),
+		},
+		"not integral": {
+			get: func(v fluffyjson.JsonValue) (any, error) {
+				return fluffyjson.Get[int](v, fluffyjson.KeyAccess("float"))
+			},
+			expected: 0,
+			err:      fluffyjson.ErrNotIntegral{},
+		},
+		"int8": {
+			get:      func(v fluffyjson.JsonValue) (any, error) { return fluffyjson.Get[int8](v, fluffyjson.KeyAccess("int")) },
+			expected: int8(42),
+		},
+		"uint8 overflow": {
+			get: func(v fluffyjson.JsonValue) (any, error) {
+				return fluffyjson.Get[uint8](v, fluffyjson.KeyAccess("large"))
+			},
+			expected: uint8(0),
+			err:      fluffyjson.ErrOverflow{},
+		},
+		"int64 overflow": {
+			get: func(v fluffyjson.JsonValue) (any, error) {
+				return fluffyjson.Get[int64](v, fluffyjson.KeyAccess("large"))
+			},
+			expected: int64(0),
+			err:      fluffyjson.ErrOverflow{},
+		},
+		"negative uint": {
+			get: func(v fluffyjson.JsonValue) (any, error) {
+				return fluffyjson.Get[uint](v, fluffyjson.KeyAccess("negative"))
+			},
+			expected: uint(0),
+			err:      fluffyjson.ErrOverflow{},
+		},
+		"float32": {
+			get: func(v fluffyjson.JsonValue) (any, error) {
+				return fluffyjson.Get[float32](v, fluffyjson.KeyAccess("float"))
+			},
+			expected: float32(1.5),
+		},
+		"string": {
+			get: func(v fluffyjson.JsonValue) (any, error) {
+				return fluffyjson.Get[string](v, fluffyjson.KeyAccess("string"))
+			},
+			expected: "hello",
+		},
+		"string mismatch": {
+			get: func(v fluffyjson.JsonValue) (any, error) {
+				return fluffyjson.Get[string](v, fluffyjson.KeyAccess("int"))
+			},
+			expected: "",
+			err:      fluffyjson.ErrAsValue{Expected: fluffyjson.STRING, Actual: fluffyjson.NUMBER},
+		},
+		"bool": {
+			get: func(v fluffyjson.JsonValue) (any, error) {
+				return fluffyjson.Get[bool](v, fluffyjson.KeyAccess("bool"))
+			},
+			expected: true,
+		},
+		"json kind": {
+			get: func(v fluffyjson.JsonValue) (any, error) {
+				return fluffyjson.Get[fluffyjson.String](v, fluffyjson.KeyAccess("string"))
+			},
+			expected: fluffyjson.String("hello"),
+		},
+		"time": {
+			get: func(v fluffyjson.JsonValue) (any, error) {
+				return fluffyjson.Get[time.Time](v, fluffyjson.KeyAccess("time"))
+			},
+			expected: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+		"strings": {
+			get: func(v fluffyjson.JsonValue) (any, error) {
+				return fluffyjson.Get[[]string](v, fluffyjson.KeyAccess("strings"))
+			},
+			expected: []string{"a", "b"},
+		},
+		"array": {
+			get: func(v fluffyjson.JsonValue) (any, error) {
+				return fluffyjson.Get[[3]string](v, fluffyjson.KeyAccess("strings"))
+			},
+			expected: [3]string{"a", "b", ""},
+		},
+		"element not integral": {
+			get: func(v fluffyjson.JsonValue) (any, error) {
+				return fluffyjson.Get[[]int](v, fluffyjson.KeyAccess("numbers"))
+			},
+			expected: []int(nil),
+			err:      fluffyjson.ErrNotIntegral{Pointer: fluffyjson.Pointer{fluffyjson.IndexAccess(1)}, Number: 2.5},
+		},
+		"map": {
+			get: func(v fluffyjson.JsonValue) (any, error) {
+				return fluffyjson.Get[map[string]map[string]int](v, fluffyjson.KeyAccess("nested"))
+			},
+			expected: map[string]map[string]int{"a": {"x": 1}, "b": {"x": 2}},
+		},
+		"null pointer": {
+			get: func(v fluffyjson.JsonValue) (any, error) {
+				return fluffyjson.Get[*int](v, fluffyjson.KeyAccess("null"))
+			},
+			expected: (*int)(nil),
+		},
+		"null int": {
+			get:      func(v fluffyjson.JsonValue) (any, error) { return fluffyjson.Get[int](v, fluffyjson.KeyAccess("null")) },
+			expected: 0,
+			err:      fluffyjson.ErrAsValue{Expected: fluffyjson.NUMBER, Actual: fluffyjson.NULL},
+		},
+		"any": {
+			get: func(v fluffyjson.JsonValue) (any, error) {
+				return fluffyjson.Get[any](v, fluffyjson.KeyAccess("numbers"))
+			},
+			expected: []any{1.0, 2.5},
+		},
+		"missing": {
+			get: func(v fluffyjson.JsonValue) (any, error) {
+				return fluffyjson.Get[int](v, fluffyjson.KeyAccess("missing"))
+			},
+			expected: 0,
+			err:      fluffyjson.ErrNotFound{},
+		},
+		"or default": {
+			get: func(v fluffyjson.JsonValue) (any, error) {
+				return fluffyjson.GetOr(v, "def", fluffyjson.KeyAccess("int")), nil
+			},
+			expected: "def",
+		},
+		"or value": {
+			get: func(v fluffyjson.JsonValue) (any, error) {
+				return fluffyjson.GetOr(v, -1, fluffyjson.KeyAccess("int")), nil
+			},
+			expected: 42,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			value := HelperUnmarshalValue(t, target)
+			actual, err := tc.get(&value)
+			HelperFatalEvaluateError(t, tc.expected, actual, tc.err, err)
+		})
+	}
+
+	t.Run("error pointer", func(t *testing.T) {
+		value := HelperUnmarshalValue(t, target)
+		_, err := fluffyjson.Get[[]int](&value, fluffyjson.KeyAccess("numbers"))
+		HelperFatalEvaluate(t, "2.5 is not integral at /numbers/1", err.Error())
+	})
+}
@@ -0,0 +1,115 @@
+package fluffyjson
+
+import "math"
+
+type (
+	// AsInteger converts the number into integer type, failing with [ErrNotIntegral] or [ErrOverflow] instead of truncating.
+	AsInteger interface {
+		AsInt() (int, error)
+		AsInt64() (int64, error)
+		AsInt32() (int32, error)
+		AsUint64() (uint64, error)
+	}
+	AccessAsInteger interface {
+		AccessAsInt(...Accessor) (int, error)
+		AccessAsInt64(...Accessor) (int64, error)
+		AccessAsInt32(...Accessor) (int32, error)
+		AccessAsUint64(...Accessor) (uint64, error)
+	}
+)
+
+func asInt(n Number, err error) (int, error) {
+	return asIntegral[int](n, err, math.MinInt, -math.MinInt, "int")
+}
+func asInt64(n Number, err error) (int64, error) {
+	return asIntegral[int64](n, err, math.MinInt64, -math.MinInt64, "int64")
+}
+func asInt32(n Number, err error) (int32, error) {
+	return asIntegral[int32](n, err, math.MinInt32, -math.MinInt32, "int32")
+}
+func asUint64(n Number, err error) (uint64, error) {
+	return asIntegral[uint64](n, err, 0, -2*math.MinInt64, "uint64")
+}
+
+// asIntegral converts the number into T if it is integral and within [lower, upper).
+func asIntegral[T int | int64 | int32 | uint64](n Number, err error, lower, upper float64, typ string) (T, error) {
+	if err != nil {
+		return 0, err
+	} else if n != Number(math.Trunc(float64(n))) {
+		return 0, ErrNotIntegral{Number: n}
+	} else if float64(n) < lower || float64(n) >= upper {
+		return 0, ErrOverflow{Number: n, Type: typ}
+	}
+	return T(n), nil
+}
+
+func (o Object) AsInt() (int, error)       { return asInt(o.AsNumber()) }
+func (o Object) AsInt64() (int64, error)   { return asInt64(o.AsNumber()) }
+func (o Object) AsInt32() (int32, error)   { return asInt32(o.AsNumber()) }
+func (o Object) AsUint64() (uint64, error) { return asUint64(o.AsNumber()) }
+func (a Array) AsInt() (int, error)        { return asInt(a.AsNumber()) }
+func (a Array) AsInt64() (int64, error)    { return asInt64(a.AsNumber()) }
+func (a Array) AsInt32() (int32, error)    { return asInt32(a.AsNumber()) }
+func (a Array) AsUint64() (uint64, error)  { return asUint64(a.AsNumber()) }
+func (s String) AsInt() (int, error)       { return asInt(s.AsNumber()) }
+func (s String) AsInt64() (int64, error)   { return asInt64(s.AsNumber()) }
+func (s String) AsInt32() (int32, error)   { return asInt32(s.AsNumber()) }
+func (s String) AsUint64() (uint64, error) { return asUint64(s.AsNumber()) }
+func (n Number) AsInt() (int, error)       { return asInt(n.AsNumber()) }
+func (n Number) AsInt64() (int64, error)   { return asInt64(n.AsNumber()) }
+func (n Number) AsInt32() (int32, error)   { return asInt32(n.AsNumber()) }
+func (n Number) AsUint64() (uint64, error) { return asUint64(n.AsNumber()) }
+func (b Bool) AsInt() (int, error)         { return asInt(b.AsNumber()) }
+func (b Bool) AsInt64() (int64, error)     { return asInt64(b.AsNumber()) }
+func (b Bool) AsInt32() (int32, error)     { return asInt32(b.AsNumber()) }
+func (b Bool) AsUint64() (uint64, error)   { return asUint64(b.AsNumber()) }
+func (n Null) AsInt() (int, error)         { return asInt(n.AsNumber()) }
+func (n Null) AsInt64() (int64, error)     { return asInt64(n.AsNumber()) }
+func (n Null) AsInt32() (int32, error)     { return asInt32(n.AsNumber()) }
+func (n Null) AsUint64() (uint64, error)   { return asUint64(n.AsNumber()) }
+
+func accessAsInt(v JsonValue, ptr ...Accessor) (int, error) {
+	return accessAsIntegral(v, ptr, asInt)
+}
+func accessAsInt64(v JsonValue, ptr ...Accessor) (int64, error) {
+	return accessAsIntegral(v, ptr, asInt64)
+}
+func accessAsInt32(v JsonValue, ptr ...Accessor) (int32, error) {
+	return accessAsIntegral(v, ptr, asInt32)
+}
+func accessAsUint64(v JsonValue, ptr ...Accessor) (uint64, error) {
+	return accessAsIntegral(v, ptr, asUint64)
+}
+func accessAsIntegral[T int | int64 | int32 | uint64](v JsonValue, ptr []Accessor, as func(Number, error) (T, error)) (T, error) {
+	n, err := accessAsNumber(v, ptr...)
+	if err != nil {
+		return 0, err
+	}
+	t, err := as(n, nil)
+	return t, prefixed(ptr, err)
+}
+
+func (o Object) AccessAsInt(ptr ...Accessor) (int, error)       { return accessAsInt(&o, ptr...) }
+func (o Object) AccessAsInt64(ptr ...Accessor) (int64, error)   { return accessAsInt64(&o, ptr...) }
+func (o Object) AccessAsInt32(ptr ...Accessor) (int32, error)   { return accessAsInt32(&o, ptr...) }
+func (o Object) AccessAsUint64(ptr ...Accessor) (uint64, error) { return accessAsUint64(&o, ptr...) }
+func (a Array) AccessAsInt(ptr ...Accessor) (int, error)        { return accessAsInt(&a, ptr...) }
+func (a Array) AccessAsInt64(ptr ...Accessor) (int64, error)    { return accessAsInt64(&a, ptr...) }
+func (a Array) AccessAsInt32(ptr ...Accessor) (int32, error)    { return accessAsInt32(&a, ptr...) }
+func (a Array) AccessAsUint64(ptr ...Accessor) (uint64, error)  { return accessAsUint64(&a, ptr...) }
+func (s String) AccessAsInt(ptr ...Accessor) (int, error)       { return accessAsInt(&s, ptr...) }
+func (s String) AccessAsInt64(ptr ...Accessor) (int64, error)   { return accessAsInt64(&s, ptr...) }
+func (s String) AccessAsInt32(ptr ...Accessor) (int32, error)   { return accessAsInt32(&s, ptr...) }
+func (s String) AccessAsUint64(ptr ...Accessor) (uint64, error) { return accessAsUint64(&s, ptr...) }
+func (n Number) AccessAsInt(ptr ...Accessor) (int, error)       { return accessAsInt(&n, ptr...) }
+func (n Number) AccessAsInt64(ptr ...Accessor) (int64, error)   { return accessAsInt64(&n, ptr...) }
+func (n Number) AccessAsInt32(ptr ...Accessor) (int32, error)   { return accessAsInt32(&n, ptr...) }
+func (n Number) AccessAsUint64(ptr ...Accessor) (uint64, error) { return accessAsUint64(&n, ptr...) }
+func (b Bool) AccessAsInt(ptr ...Accessor) (int, error)         { return accessAsInt(&b, ptr...) }
+func (b Bool) AccessAsInt64(ptr ...Accessor) (int64, error)     { return accessAsInt64(&b, ptr...) }
+func (b Bool) AccessAsInt32(ptr ...Accessor) (int32, error)     { return accessAsInt32(&b, ptr...) }
+func (b Bool) AccessAsUint64(ptr ...Accessor) (uint64, error)   { return accessAsUint64(&b, ptr...) }
+func (n Null) AccessAsInt(ptr ...Accessor) (int, error)         { return accessAsInt(&n, ptr...) }
+func (n Null) AccessAsInt64(ptr ...Accessor) (int64, error)     { return accessAsInt64(&n, ptr...) }
+func (n Null) AccessAsInt32(ptr ...Accessor) (int32, error)     { return accessAsInt32(&n, ptr...) }
+func (n Null) AccessAsUint64(ptr ...Accessor) (uint64, error)   { return accessAsUint64(&n, ptr...) }
@@ -0,0 +1,143 @@
+package fluffyjson_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"testing"
+
+	fluffyjson "github.com/hayas1/go-fluffy-json"
+)
+
+func ExampleNumber_AsInt() {
+	var value fluffyjson.RootValue
+	if err := json.Unmarshal([]byte(`{"count": 3, "ratio": 0.5}`), &value); err != nil {
+		panic(err)
+	}
+
+	count, err := value.AccessAsInt(fluffyjson.KeyAccess("count"))
+	fmt.Println(count, err)
+	ratio, err := value.AccessAsInt(fluffyjson.KeyAccess("ratio"))
+	fmt.Println(ratio, err)
+	// Output:
+	// 3 <nil>
+	// 0 0.5 is not integral at /ratio
+}
+
+func TestAsInteger(t *testing.T) {
+	testcases := map[string]struct {
+		target   string
+		as       func(v fluffyjson.JsonValue) (any, error)
+		expected any
+		err      error
+	}{
+		"int": {
+			target:   `-42`,
+			as:       func(v fluffyjson.JsonValue) (any, error) { return v.AsInt() },
+			expected: -42,
+		},
+		"int not integral": {
+			target:   `1.5`,
+			as:       func(v fluffyjson.JsonValue) (any, error) { return v.AsInt() },
+			expected: 0,
+			err:      fluffyjson.ErrNotIntegral{},
+		},
+		"int not number": {
+			target:   `"1"`,
+			as:       func(v fluffyjson.JsonValue) (any, error) { return v.AsInt() },
+			expected: 0,
+			err:      fluffyjson.ErrAsValue{Expected: fluffyjson.NUMBER, Actual: fluffyjson.STRING},
+		},
+		"int64 max safe": {
+			target:   `9007199254740992`,
+			as:       func(v fluffyjson.JsonValue) (any, error) { return v.AsInt64() },
+			expected: int64(1 << 53),
+		},
+		"int64 min": {
+			target:   `-9223372036854775808`,
+			as:       func(v fluffyjson.JsonValue) (any, error) { return v.AsInt64() },
+			expected: int64(math.MinInt64),
+		},
+		"int64 overflow": {
+			target:   `9223372036854775808`,
+			as:       func(v fluffyjson.JsonValue) (any, error) { return v.AsInt64() },
+			expected: int64(0),
+			err:      fluffyjson.ErrOverflow{},
+		},
+		"int32 max": {
+			target:   `2147483647`,
+			as:       func(v fluffyjson.JsonValue) (any, error) { return v.AsInt32() },
+			expected: int32(math.MaxInt32),
+		},
+		"int32 overflow": {
+			target:   `2147483648`,
+			as:       func(v fluffyjson.JsonValue) (any, error) { return v.AsInt32() },
+			expected: int32(0),
+			err:      fluffyjson.ErrOverflow{},
+		},
+		"int32 underflow": {
+			target:   `-2147483649`,
+			as:       func(v fluffyjson.JsonValue) (any, error) { return v.AsInt32() },
+			expected: int32(0),
+			err:      fluffyjson.ErrOverflow{},
+		},
+		"uint64": {
+			target:   `1e19`,
+			as:       func(v fluffyjson.JsonValue) (any, error) { return v.AsUint64() },
+			expected: uint64(1e19),
+		},
+		"uint64 negative": {
+			target:   `-1`,
+			as:       func(v fluffyjson.JsonValue) (any, error) { return v.AsUint64() },
+			expected: uint64(0),
+			err:      fluffyjson.ErrOverflow{},
+		},
+		"uint64 overflow": {
+			target:   `18446744073709551616`,
+			as:       func(v fluffyjson.JsonValue) (any, error) { return v.AsUint64() },
+			expected: uint64(0),
+			err:      fluffyjson.ErrOverflow{},
+		},
+		"access": {
+			target: `{"a": [1, 2]}`,
+			as: func(v fluffyjson.JsonValue) (any, error) {
+				return v.AccessAsInt32(fluffyjson.KeyAccess("a"), fluffyjson.IndexAccess(1))
+			},
+			expected: int32(2),
+		},
+		"access missing": {
+			target:   `{"a": [1, 2]}`,
+			as:       func(v fluffyjson.JsonValue) (any, error) { return v.AccessAsInt(fluffyjson.KeyAccess("b")) },
+			expected: 0,
+			err:      fluffyjson.ErrNotFound{},
+		},
+		"lenient": {
+			target: `{"a": "12"}`,
+			as: func(v fluffyjson.JsonValue) (any, error) {
+				return fluffyjson.Lenient{Value: v}.AccessAsInt64(fluffyjson.KeyAccess("a"))
+			},
+			expected: int64(12),
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			value := HelperUnmarshalValue(t, tc.target)
+			actual, err := tc.as(&value)
+			HelperFatalEvaluateError(t, tc.expected, actual, tc.err, err)
+		})
+	}
+
+	t.Run("error pointer", func(t *testing.T) {
+		value := HelperUnmarshalValue(t, `{"a": {"b": [1, 2.5, 1e100]}}`)
+
+		_, err := value.AccessAsInt(fluffyjson.KeyAccess("a"), fluffyjson.KeyAccess("b"), fluffyjson.IndexAccess(1))
+		HelperFatalEvaluate(t, "2.5 is not integral at /a/b/1", err.Error())
+
+		_, err = value.AccessAsInt32(fluffyjson.KeyAccess("a"), fluffyjson.KeyAccess("b"), fluffyjson.IndexAccess(2))
+		HelperFatalEvaluate(t, "1e+100 overflows int32 at /a/b/2", err.Error())
+
+		_, err = fluffyjson.Lenient{Value: &value}.AccessAsUint64(fluffyjson.KeyAccess("a"), fluffyjson.KeyAccess("b"), fluffyjson.IndexAccess(1))
+		HelperFatalEvaluate(t, "2.5 is not integral at /a/b/1", err.Error())
+	})
+}
@@ -0,0 +1,1326 @@
+package fluffyjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type (
+	// JmesPath is the expression of JMESPath(https://jmespath.org/specification.html).
+	JmesPath struct {
+		expression string
+		ast        jmNode
+	}
+
+	// ErrJmesPath is the error of JMESPath, Kind is one of the error kinds of JMESPath specification,
+	// such as syntax, invalid-type, invalid-value, invalid-arity, and unknown-function.
+	ErrJmesPath struct {
+		Kind       string
+		Expression string
+		Offset     int
+		Reason     string
+	}
+
+	jmNode interface {
+		evaluate(JsonValue) (JsonValue, error)
+	}
+	jmField            string
+	jmIdentity         struct{}
+	jmLiteral          struct{ literal JsonValue }
+	jmIndex            int
+	jmSlice            struct{ start, end, step *int }
+	jmFlatten          struct{ expr jmNode }
+	jmNot              struct{ expr jmNode }
+	jmExpref           struct{ expr jmNode }
+	jmSubexpression    struct{ left, right jmNode }
+	jmPipe             struct{ left, right jmNode }
+	jmOr               struct{ left, right jmNode }
+	jmAnd              struct{ left, right jmNode }
+	jmProjection       struct{ left, right jmNode }
+	jmValueProjection  struct{ left, right jmNode }
+	jmFilterProjection struct {
+		left, right, condition jmNode
+	}
+	jmComparator struct {
+		op          jmTokenKind
+		left, right jmNode
+	}
+	jmMultiSelectList []jmNode
+	jmMultiSelectHash struct {
+		keys  []string
+		exprs []jmNode
+	}
+	jmCall struct {
+		name     string
+		function jmFunction
+		args     []jmNode
+	}
+	jmFunction struct {
+		arity    int
+		variadic bool
+		call     func(args []any) (JsonValue, error)
+	}
+
+	jmTokenKind string
+	jmToken     struct {
+		kind  jmTokenKind
+		value string
+		json  JsonValue
+		pos   int
+	}
+	jmParser struct {
+		expression string
+		tokens     []jmToken
+		index      int
+	}
+)
+
+const (
+	jmEOF               jmTokenKind = "eof"
+	jmUnquotedIdentifer jmTokenKind = "unquoted_identifier"
+	jmQuotedIdentifier  jmTokenKind = "quoted_identifier"
+	jmLiteralToken      jmTokenKind = "literal"
+	jmNumberToken       jmTokenKind = "number"
+	jmDot               jmTokenKind = "dot"
+	jmStar              jmTokenKind = "star"
+	jmComma             jmTokenKind = "comma"
+	jmColon             jmTokenKind = "colon"
+	jmCurrent           jmTokenKind = "current"
+	jmExprefToken       jmTokenKind = "expref"
+	jmPipeToken         jmTokenKind = "pipe"
+	jmOrToken           jmTokenKind = "or"
+	jmAndToken          jmTokenKind = "and"
+	jmNotToken          jmTokenKind = "not"
+	jmEQ                jmTokenKind = "eq"
+	jmNE                jmTokenKind = "ne"
+	jmLT                jmTokenKind = "lt"
+	jmLTE               jmTokenKind = "lte"
+	jmGT                jmTokenKind = "gt"
+	jmGTE               jmTokenKind = "gte"
+	jmFlattenToken      jmTokenKind = "flatten"
+	jmFilter            jmTokenKind = "filter"
+	jmLBracket          jmTokenKind = "lbracket"
+	jmRBracket          jmTokenKind = "rbracket"
+	jmLBrace            jmTokenKind = "lbrace"
+	jmRBrace            jmTokenKind = "rbrace"
+	jmLParen            jmTokenKind = "lparen"
+	jmRParen            jmTokenKind = "rparen"
+)
+
+var (
+	jmBindingPower = map[jmTokenKind]int{
+		jmPipeToken:    1,
+		jmOrToken:      2,
+		jmAndToken:     3,
+		jmEQ:           5,
+		jmNE:           5,
+		jmLT:           5,
+		jmLTE:          5,
+		jmGT:           5,
+		jmGTE:          5,
+		jmFlattenToken: 9,
+		jmStar:         20,
+		jmFilter:       21,
+		jmDot:          40,
+		jmNotToken:     45,
+		jmLBrace:       50,
+		jmLBracket:     55,
+		jmLParen:       60,
+	}
+	jmFunctions = map[string]jmFunction{
+		"abs":         {arity: 1, call: jmAbs},
+		"avg":         {arity: 1, call: jmAvg},
+		"ceil":        {arity: 1, call: jmCeil},
+		"contains":    {arity: 2, call: jmContains},
+		"ends_with":   {arity: 2, call: jmEndsWith},
+		"floor":       {arity: 1, call: jmFloor},
+		"join":        {arity: 2, call: jmJoin},
+		"keys":        {arity: 1, call: jmKeys},
+		"length":      {arity: 1, call: jmLength},
+		"map":         {arity: 2, call: jmMap},
+		"max":         {arity: 1, call: jmMax},
+		"max_by":      {arity: 2, call: jmMaxBy},
+		"merge":       {arity: 1, variadic: true, call: jmMerge},
+		"min":         {arity: 1, call: jmMin},
+		"min_by":      {arity: 2, call: jmMinBy},
+		"not_null":    {arity: 1, variadic: true, call: jmNotNull},
+		"reverse":     {arity: 1, call: jmReverse},
+		"sort":        {arity: 1, call: jmSort},
+		"sort_by":     {arity: 2, call: jmSortBy},
+		"starts_with": {arity: 2, call: jmStartsWith},
+		"sum":         {arity: 1, call: jmSum},
+		"to_array":    {arity: 1, call: jmToArray},
+		"to_number":   {arity: 1, call: jmToNumber},
+		"to_string":   {arity: 1, call: jmToString},
+		"type":        {arity: 1, call: jmType},
+		"values":      {arity: 1, call: jmValues},
+	}
+)
+
+func (e ErrJmesPath) Error() string {
+	return fmt.Sprintf("%s error in JMESPath %q at %d: %s", e.Kind, e.Expression, e.Offset, e.Reason)
+}
+
+// ParseJmesPath parses the JMESPath expression, such as `locations[?state == 'WA'].name | sort(@)`.
+func ParseJmesPath(expression string) (JmesPath, error) {
+	tokens, err := jmTokenize(expression)
+	if err != nil {
+		return JmesPath{}, err
+	}
+	p := &jmParser{expression: expression, tokens: tokens}
+	ast, err := p.parseExpression(0)
+	if err != nil {
+		return JmesPath{}, err
+	}
+	if p.current().kind != jmEOF {
+		return JmesPath{}, p.errorf("unexpected token %s", p.current().value)
+	}
+	return JmesPath{expression: expression, ast: ast}, nil
+}
+
+func (jm JmesPath) String() string {
+	return jm.expression
+}
+
+// Evaluate evaluates the JMESPath expression against v, and returns the result as new JSON value.
+func (jm JmesPath) Evaluate(v JsonValue) (JsonValue, error) {
+	v = unwrapRoot(v)
+	result, err := jm.ast.evaluate(v)
+	if e, ok := err.(ErrJmesPath); ok {
+		e.Expression = jm.expression
+		return nil, e
+	}
+	return result, err
+}
+
+func jmNull() JsonValue            { return new(Null) }
+func jmBool(b bool) JsonValue      { v := Bool(b); return &v }
+func jmNumber(f float64) JsonValue { v := Number(f); return &v }
+func jmString(s string) JsonValue  { v := String(s); return &v }
+func jmInvalidType(function string, arg JsonValue) error {
+	return ErrJmesPath{Kind: "invalid-type", Reason: fmt.Sprintf("invalid type %s for function %s", arg.representation(), function)}
+}
+
+// jmTruthy reports whether the value is truthy: false, null, and empty string, array, and object are falsy.
+func jmTruthy(v JsonValue) bool {
+	switch t := v.(type) {
+	case *Object:
+		return len(*t) > 0
+	case *Array:
+		return len(*t) > 0
+	case *String:
+		return len(*t) > 0
+	case *Bool:
+		return bool(*t)
+	case *Number:
+		return true
+	default:
+		return false
+	}
+}
+
+func (n jmField) evaluate(v JsonValue) (JsonValue, error) {
+	if o, ok := v.(*Object); ok {
+		if child, ok := (*o)[string(n)]; ok {
+			return child, nil
+		}
+	}
+	return jmNull(), nil
+}
+func (n jmIdentity) evaluate(v JsonValue) (JsonValue, error) {
+	return v, nil
+}
+func (n jmLiteral) evaluate(JsonValue) (JsonValue, error) {
+	return n.literal, nil
+}
+func (n jmIndex) evaluate(v JsonValue) (JsonValue, error) {
+	if a, ok := v.(*Array); ok {
+		if child, err := IndexAccess(n).Accessing(a); err == nil {
+			return child, nil
+		}
+	}
+	return jmNull(), nil
+}
+func (n jmSlice) evaluate(v JsonValue) (JsonValue, error) {
+	a, ok := v.(*Array)
+	if !ok {
+		return jmNull(), nil
+	}
+	slice := SliceAccess{Step: 1}
+	if n.step != nil {
+		slice.Step = *n.step
+	}
+	if slice.Step == 0 {
+		return nil, ErrJmesPath{Kind: "invalid-value", Reason: "slice step cannot be 0"}
+	}
+	if slice.Start, slice.End = 0, math.MaxInt; slice.Step < 0 {
+		slice.Start, slice.End = math.MaxInt, math.MinInt
+	}
+	if n.start != nil {
+		slice.Start = *n.start
+	}
+	if n.end != nil {
+		slice.End = *n.end
+	}
+	sliced, err := slice.Slicing(a)
+	if err != nil {
+		return nil, err
+	}
+	result := Array(sliced)
+	return &result, nil
+}
+func (n jmFlatten) evaluate(v JsonValue) (JsonValue, error) {
+	base, err := n.expr.evaluate(v)
+	if err != nil {
+		return nil, err
+	}
+	a, ok := base.(*Array)
+	if !ok {
+		return jmNull(), nil
+	}
+	flattened := make(Array, 0, len(*a))
+	for _, elem := range *a {
+		if inner, ok := elem.(*Array); ok {
+			flattened = append(flattened, *inner...)
+		} else {
+			flattened = append(flattened, elem)
+		}
+	}
+	return &flattened, nil
+}
+func (n jmNot) evaluate(v JsonValue) (JsonValue, error) {
+	result, err := n.expr.evaluate(v)
+	if err != nil {
+		return nil, err
+	}
+	return jmBool(!jmTruthy(result)), nil
+}
+func (n jmExpref) evaluate(JsonValue) (JsonValue, error) {
+	return nil, ErrJmesPath{Kind: "invalid-type", Reason: "expression reference can be used only as function argument"}
+}
+func (n jmSubexpression) evaluate(v JsonValue) (JsonValue, error) {
+	left, err := n.left.evaluate(v)
+	if err != nil {
+		return nil, err
+	}
+	return n.right.evaluate(left)
+}
+func (n jmPipe) evaluate(v JsonValue) (JsonValue, error) {
+	left, err := n.left.evaluate(v)
+	if err != nil {
+		return nil, err
+	}
+	return n.right.evaluate(left)
+}
+func (n jmOr) evaluate(v JsonValue) (JsonValue, error) {
+	left, err := n.left.evaluate(v)
+	if err != nil || jmTruthy(left) {
+		return left, err
+	}
+	return n.right.evaluate(v)
+}
+func (n jmAnd) evaluate(v JsonValue) (JsonValue, error) {
+	left, err := n.left.evaluate(v)
+	if err != nil || !jmTruthy(left) {
+		return left, err
+	}
+	return n.right.evaluate(v)
+}
+func (n jmProjection) evaluate(v JsonValue) (JsonValue, error) {
+	base, err := n.left.evaluate(v)
+	if err != nil {
+		return nil, err
+	}
+	a, ok := base.(*Array)
+	if !ok {
+		return jmNull(), nil
+	}
+	return jmProject(*a, n.right)
+}
+func (n jmValueProjection) evaluate(v JsonValue) (JsonValue, error) {
+	base, err := n.left.evaluate(v)
+	if err != nil {
+		return nil, err
+	}
+	o, ok := base.(*Object)
+	if !ok {
+		return jmNull(), nil
+	}
+	values := make(Array, 0, len(*o))
+	for _, k := range slices.Sorted(maps.Keys(*o)) {
+		values = append(values, (*o)[k])
+	}
+	return jmProject(values, n.right)
+}
+func (n jmFilterProjection) evaluate(v JsonValue) (JsonValue, error) {
+	base, err := n.left.evaluate(v)
+	if err != nil {
+		return nil, err
+	}
+	a, ok := base.(*Array)
+	if !ok {
+		return jmNull(), nil
+	}
+	filtered := make(Array, 0, len(*a))
+	for _, elem := range *a {
+		condition, err := n.condition.evaluate(elem)
+		if err != nil {
+			return nil, err
+		}
+		if jmTruthy(condition) {
+			filtered = append(filtered, elem)
+		}
+	}
+	return jmProject(filtered, n.right)
+}
+func jmProject(elems Array, right jmNode) (JsonValue, error) {
+	projected := make(Array, 0, len(elems))
+	for _, elem := range elems {
+		result, err := right.evaluate(elem)
+		if err != nil {
+			return nil, err
+		}
+		if !result.IsNull() {
+			projected = append(projected, result)
+		}
+	}
+	return &projected, nil
+}
+func (n jmComparator) evaluate(v JsonValue) (JsonValue, error) {
+	left, err := n.left.evaluate(v)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.evaluate(v)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case jmEQ:
+		return jmBool(equal(left, right)), nil
+	case jmNE:
+		return jmBool(!equal(left, right)), nil
+	}
+
+	l, lok := left.(*Number)
+	r, rok := right.(*Number)
+	if !lok || !rok {
+		return jmNull(), nil
+	}
+	switch n.op {
+	case jmLT:
+		return jmBool(*l < *r), nil
+	case jmLTE:
+		return jmBool(*l <= *r), nil
+	case jmGT:
+		return jmBool(*l > *r), nil
+	default:
+		return jmBool(*l >= *r), nil
+	}
+}
+func (n jmMultiSelectList) evaluate(v JsonValue) (JsonValue, error) {
+	if v.IsNull() {
+		return v, nil
+	}
+	selected := make(Array, 0, len(n))
+	for _, expr := range n {
+		result, err := expr.evaluate(v)
+		if err != nil {
+			return nil, err
+		}
+		selected = append(selected, result)
+	}
+	return &selected, nil
+}
+func (n jmMultiSelectHash) evaluate(v JsonValue) (JsonValue, error) {
+	if v.IsNull() {
+		return v, nil
+	}
+	selected := make(Object, len(n.keys))
+	for i, k := range n.keys {
+		result, err := n.exprs[i].evaluate(v)
+		if err != nil {
+			return nil, err
+		}
+		selected[k] = result
+	}
+	return &selected, nil
+}
+func (n jmCall) evaluate(v JsonValue) (JsonValue, error) {
+	args := make([]any, 0, len(n.args))
+	for _, arg := range n.args {
+		if expref, ok := arg.(jmExpref); ok {
+			args = append(args, expref)
+			continue
+		}
+		result, err := arg.evaluate(v)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, result)
+	}
+	return n.function.call(args)
+}
+
+func jmValueArg(function string, arg any) (JsonValue, error) {
+	if v, ok := arg.(JsonValue); ok {
+		return v, nil
+	}
+	return nil, ErrJmesPath{Kind: "invalid-type", Reason: fmt.Sprintf("function %s does not accept expression reference", function)}
+}
+func jmNumberArg(function string, arg any) (float64, error) {
+	v, err := jmValueArg(function, arg)
+	if err != nil {
+		return 0, err
+	}
+	if n, ok := v.(*Number); ok {
+		return float64(*n), nil
+	}
+	return 0, jmInvalidType(function, v)
+}
+func jmStringArg(function string, arg any) (string, error) {
+	v, err := jmValueArg(function, arg)
+	if err != nil {
+		return "", err
+	}
+	if s, ok := v.(*String); ok {
+		return string(*s), nil
+	}
+	return "", jmInvalidType(function, v)
+}
+func jmArrayArg(function string, arg any) (Array, error) {
+	v, err := jmValueArg(function, arg)
+	if err != nil {
+		return nil, err
+	}
+	if a, ok := v.(*Array); ok {
+		return *a, nil
+	}
+	return nil, jmInvalidType(function, v)
+}
+func jmObjectArg(function string, arg any) (Object, error) {
+	v, err := jmValueArg(function, arg)
+	if err != nil {
+		return nil, err
+	}
+	if o, ok := v.(*Object); ok {
+		return *o, nil
+	}
+	return nil, jmInvalidType(function, v)
+}
+func jmExprefArg(function string, arg any) (jmNode, error) {
+	if e, ok := arg.(jmExpref); ok {
+		return e.expr, nil
+	}
+	return nil, ErrJmesPath{Kind: "invalid-type", Reason: fmt.Sprintf("function %s requires expression reference", function)}
+}
+func jmNumbersArg(function string, arg any) ([]float64, error) {
+	a, err := jmArrayArg(function, arg)
+	if err != nil {
+		return nil, err
+	}
+	numbers := make([]float64, 0, len(a))
+	for _, v := range a {
+		n, ok := v.(*Number)
+		if !ok {
+			return nil, jmInvalidType(function, v)
+		}
+		numbers = append(numbers, float64(*n))
+	}
+	return numbers, nil
+}
+
+// jmSortKeys checks the keys are all numbers or all strings, and returns the comparison function of them.
+func jmSortKeys(function string, keys []JsonValue) (func(i, j int) int, error) {
+	if len(keys) == 0 {
+		return func(int, int) int { return 0 }, nil
+	}
+	switch keys[0].(type) {
+	case *Number:
+		for _, k := range keys {
+			if _, ok := k.(*Number); !ok {
+				return nil, jmInvalidType(function, k)
+			}
+		}
+		return func(i, j int) int {
+			a, b := *keys[i].(*Number), *keys[j].(*Number)
+			return cmpOrdered(a, b)
+		}, nil
+	case *String:
+		for _, k := range keys {
+			if _, ok := k.(*String); !ok {
+				return nil, jmInvalidType(function, k)
+			}
+		}
+		return func(i, j int) int {
+			return strings.Compare(string(*keys[i].(*String)), string(*keys[j].(*String)))
+		}, nil
+	default:
+		return nil, jmInvalidType(function, keys[0])
+	}
+}
+func cmpOrdered[T float64 | Number | int](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+func jmKeysBy(function string, a Array, expr jmNode) ([]JsonValue, error) {
+	keys := make([]JsonValue, 0, len(a))
+	for _, v := range a {
+		k, err := expr.evaluate(v)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+func jmExtremum(function string, a Array, keys []JsonValue, sign int) (JsonValue, error) {
+	if len(a) == 0 {
+		return jmNull(), nil
+	}
+	compare, err := jmSortKeys(function, keys)
+	if err != nil {
+		return nil, err
+	}
+	best := 0
+	for i := 1; i < len(a); i++ {
+		if compare(i, best)*sign > 0 {
+			best = i
+		}
+	}
+	return a[best], nil
+}
+
+func jmAbs(args []any) (JsonValue, error) {
+	n, err := jmNumberArg("abs", args[0])
+	return jmNumber(math.Abs(n)), err
+}
+func jmAvg(args []any) (JsonValue, error) {
+	numbers, err := jmNumbersArg("avg", args[0])
+	if err != nil {
+		return nil, err
+	} else if len(numbers) == 0 {
+		return jmNull(), nil
+	}
+	var sum float64
+	for _, n := range numbers {
+		sum += n
+	}
+	return jmNumber(sum / float64(len(numbers))), nil
+}
+func jmCeil(args []any) (JsonValue, error) {
+	n, err := jmNumberArg("ceil", args[0])
+	return jmNumber(math.Ceil(n)), err
+}
+func jmContains(args []any) (JsonValue, error) {
+	subject, err := jmValueArg("contains", args[0])
+	if err != nil {
+		return nil, err
+	}
+	search, err := jmValueArg("contains", args[1])
+	if err != nil {
+		return nil, err
+	}
+	switch t := subject.(type) {
+	case *Array:
+		return jmBool(slices.ContainsFunc(*t, func(v JsonValue) bool { return equal(v, search) })), nil
+	case *String:
+		s, ok := search.(*String)
+		return jmBool(ok && strings.Contains(string(*t), string(*s))), nil
+	default:
+		return nil, jmInvalidType("contains", subject)
+	}
+}
+func jmEndsWith(args []any) (JsonValue, error) {
+	subject, err := jmStringArg("ends_with", args[0])
+	if err != nil {
+		return nil, err
+	}
+	suffix, err := jmStringArg("ends_with", args[1])
+	return jmBool(strings.HasSuffix(subject, suffix)), err
+}
+func jmFloor(args []any) (JsonValue, error) {
+	n, err := jmNumberArg("floor", args[0])
+	return jmNumber(math.Floor(n)), err
+}
+func jmJoin(args []any) (JsonValue, error) {
+	glue, err := jmStringArg("join", args[0])
+	if err != nil {
+		return nil, err
+	}
+	a, err := jmArrayArg("join", args[1])
+	if err != nil {
+		return nil, err
+	}
+	strs := make([]string, 0, len(a))
+	for _, v := range a {
+		s, ok := v.(*String)
+		if !ok {
+			return nil, jmInvalidType("join", v)
+		}
+		strs = append(strs, string(*s))
+	}
+	return jmString(strings.Join(strs, glue)), nil
+}
+func jmKeys(args []any) (JsonValue, error) {
+	o, err := jmObjectArg("keys", args[0])
+	if err != nil {
+		return nil, err
+	}
+	keys := make(Array, 0, len(o))
+	for _, k := range slices.Sorted(maps.Keys(o)) {
+		keys = append(keys, jmString(k))
+	}
+	return &keys, nil
+}
+func jmLength(args []any) (JsonValue, error) {
+	v, err := jmValueArg("length", args[0])
+	if err != nil {
+		return nil, err
+	}
+	switch t := v.(type) {
+	case *String:
+		return jmNumber(float64(utf8.RuneCountInString(string(*t)))), nil
+	case *Array:
+		return jmNumber(float64(len(*t))), nil
+	case *Object:
+		return jmNumber(float64(len(*t))), nil
+	default:
+		return nil, jmInvalidType("length", v)
+	}
+}
+func jmMap(args []any) (JsonValue, error) {
+	expr, err := jmExprefArg("map", args[0])
+	if err != nil {
+		return nil, err
+	}
+	a, err := jmArrayArg("map", args[1])
+	if err != nil {
+		return nil, err
+	}
+	mapped, err := jmKeysBy("map", a, expr)
+	if err != nil {
+		return nil, err
+	}
+	result := Array(mapped)
+	return &result, nil
+}
+func jmMax(args []any) (JsonValue, error) {
+	a, err := jmArrayArg("max", args[0])
+	if err != nil {
+		return nil, err
+	}
+	return jmExtremum("max", a, a, 1)
+}
+func jmMaxBy(args []any) (JsonValue, error) {
+	a, err := jmArrayArg("max_by", args[0])
+	if err != nil {
+		return nil, err
+	}
+	expr, err := jmExprefArg("max_by", args[1])
+	if err != nil {
+		return nil, err
+	}
+	keys, err := jmKeysBy("max_by", a, expr)
+	if err != nil {
+		return nil, err
+	}
+	return jmExtremum("max_by", a, keys, 1)
+}
+func jmMerge(args []any) (JsonValue, error) {
+	merged := make(Object)
+	for _, arg := range args {
+		o, err := jmObjectArg("merge", arg)
+		if err != nil {
+			return nil, err
+		}
+		maps.Copy(merged, o)
+	}
+	return &merged, nil
+}
+func jmMin(args []any) (JsonValue, error) {
+	a, err := jmArrayArg("min", args[0])
+	if err != nil {
+		return nil, err
+	}
+	return jmExtremum("min", a, a, -1)
+}
+func jmMinBy(args []any) (JsonValue, error) {
+	a, err := jmArrayArg("min_by", args[0])
+	if err != nil {
+		return nil, err
+	}
+	expr, err := jmExprefArg("min_by", args[1])
+	if err != nil {
+		return nil, err
+	}
+	keys, err := jmKeysBy("min_by", a, expr)
+	if err != nil {
+		return nil, err
+	}
+	return jmExtremum("min_by", a, keys, -1)
+}
+func jmNotNull(args []any) (JsonValue, error) {
+	for _, arg := range args {
+		v, err := jmValueArg("not_null", arg)
+		if err != nil {
+			return nil, err
+		}
+		if !v.IsNull() {
+			return v, nil
+		}
+	}
+	return jmNull(), nil
+}
+func jmReverse(args []any) (JsonValue, error) {
+	v, err := jmValueArg("reverse", args[0])
+	if err != nil {
+		return nil, err
+	}
+	switch t := v.(type) {
+	case *String:
+		runes := []rune(string(*t))
+		slices.Reverse(runes)
+		return jmString(string(runes)), nil
+	case *Array:
+		reversed := slices.Clone(*t)
+		slices.Reverse(reversed)
+		return &reversed, nil
+	default:
+		return nil, jmInvalidType("reverse", v)
+	}
+}
+func jmSort(args []any) (JsonValue, error) {
+	a, err := jmArrayArg("sort", args[0])
+	if err != nil {
+		return nil, err
+	}
+	sorted := slices.Clone(a)
+	compare, err := jmSortKeys("sort", sorted)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return compare(i, j) < 0 })
+	return &sorted, nil
+}
+func jmSortBy(args []any) (JsonValue, error) {
+	a, err := jmArrayArg("sort_by", args[0])
+	if err != nil {
+		return nil, err
+	}
+	expr, err := jmExprefArg("sort_by", args[1])
+	if err != nil {
+		return nil, err
+	}
+	keys, err := jmKeysBy("sort_by", a, expr)
+	if err != nil {
+		return nil, err
+	}
+	compare, err := jmSortKeys("sort_by", keys)
+	if err != nil {
+		return nil, err
+	}
+	indices := make([]int, len(a))
+	for i := range indices {
+		indices[i] = i
+	}
+	slices.SortStableFunc(indices, compare)
+	sorted := make(Array, 0, len(a))
+	for _, i := range indices {
+		sorted = append(sorted, a[i])
+	}
+	return &sorted, nil
+}
+func jmStartsWith(args []any) (JsonValue, error) {
+	subject, err := jmStringArg("starts_with", args[0])
+	if err != nil {
+		return nil, err
+	}
+	prefix, err := jmStringArg("starts_with", args[1])
+	return jmBool(strings.HasPrefix(subject, prefix)), err
+}
+func jmSum(args []any) (JsonValue, error) {
+	numbers, err := jmNumbersArg("sum", args[0])
+	if err != nil {
+		return nil, err
+	}
+	var sum float64
+	for _, n := range numbers {
+		sum += n
+	}
+	return jmNumber(sum), nil
+}
+func jmToArray(args []any) (JsonValue, error) {
+	v, err := jmValueArg("to_array", args[0])
+	if err != nil {
+		return nil, err
+	}
+	if v.IsArray() {
+		return v, nil
+	}
+	return &Array{v}, nil
+}
+func jmToNumber(args []any) (JsonValue, error) {
+	v, err := jmValueArg("to_number", args[0])
+	if err != nil {
+		return nil, err
+	}
+	switch t := v.(type) {
+	case *Number:
+		return t, nil
+	case *String:
+		var n Number
+		if err := json.Unmarshal([]byte(*t), &n); err == nil {
+			return &n, nil
+		}
+	}
+	return jmNull(), nil
+}
+func jmToString(args []any) (JsonValue, error) {
+	v, err := jmValueArg("to_string", args[0])
+	if err != nil {
+		return nil, err
+	}
+	if v.IsString() {
+		return v, nil
+	}
+	b, err := json.Marshal(v)
+	return jmString(string(b)), err
+}
+func jmType(args []any) (JsonValue, error) {
+	v, err := jmValueArg("type", args[0])
+	if err != nil {
+		return nil, err
+	}
+	if v.IsBool() {
+		return jmString("boolean"), nil
+	}
+	return jmString(string(v.representation())), nil
+}
+func jmValues(args []any) (JsonValue, error) {
+	o, err := jmObjectArg("values", args[0])
+	if err != nil {
+		return nil, err
+	}
+	values := make(Array, 0, len(o))
+	for _, k := range slices.Sorted(maps.Keys(o)) {
+		values = append(values, o[k])
+	}
+	return &values, nil
+}
+
+func jmTokenize(expression string) ([]jmToken, error) {
+	tokens := make([]jmToken, 0)
+	syntaxError := func(pos int, format string, args ...any) error {
+		return ErrJmesPath{Kind: "syntax", Expression: expression, Offset: pos, Reason: fmt.Sprintf(format, args...)}
+	}
+	simple := map[string]jmTokenKind{
+		"[?": jmFilter, "[]": jmFlattenToken, "||": jmOrToken, "&&": jmAndToken,
+		"==": jmEQ, "!=": jmNE, "<=": jmLTE, ">=": jmGTE,
+		".": jmDot, "*": jmStar, ",": jmComma, ":": jmColon, "@": jmCurrent, "&": jmExprefToken,
+		"|": jmPipeToken, "!": jmNotToken, "<": jmLT, ">": jmGT, "[": jmLBracket, "]": jmRBracket,
+		"{": jmLBrace, "}": jmRBrace, "(": jmLParen, ")": jmRParen,
+	}
+
+	for pos := 0; pos < len(expression); {
+		c := expression[pos]
+		switch {
+		case strings.IndexByte(" \t\n\r", c) >= 0:
+			pos++
+		case c == '_' || jpIsAlpha(c):
+			start := pos
+			for pos < len(expression) && (expression[pos] == '_' || jpIsAlpha(expression[pos]) || jpIsDigit(expression[pos])) {
+				pos++
+			}
+			tokens = append(tokens, jmToken{kind: jmUnquotedIdentifer, value: expression[start:pos], pos: start})
+		case c == '-' || jpIsDigit(c):
+			start := pos
+			pos++
+			for pos < len(expression) && jpIsDigit(expression[pos]) {
+				pos++
+			}
+			if expression[start:pos] == "-" {
+				return nil, syntaxError(start, "unknown token -")
+			}
+			tokens = append(tokens, jmToken{kind: jmNumberToken, value: expression[start:pos], pos: start})
+		case c == '"':
+			end, err := jmScanQuoted(expression, pos, '"')
+			if err != nil {
+				return nil, syntaxError(pos, "unterminated quoted identifier")
+			}
+			var identifier string
+			if err := json.Unmarshal([]byte(expression[pos:end]), &identifier); err != nil {
+				return nil, syntaxError(pos, "invalid quoted identifier: %s", err)
+			}
+			tokens = append(tokens, jmToken{kind: jmQuotedIdentifier, value: identifier, pos: pos})
+			pos = end
+		case c == '\'':
+			end, err := jmScanQuoted(expression, pos, '\'')
+			if err != nil {
+				return nil, syntaxError(pos, "unterminated raw string literal")
+			}
+			raw := strings.ReplaceAll(expression[pos+1:end-1], `\'`, `'`)
+			tokens = append(tokens, jmToken{kind: jmLiteralToken, value: raw, json: jmString(raw), pos: pos})
+			pos = end
+		case c == '`':
+			end, err := jmScanQuoted(expression, pos, '`')
+			if err != nil {
+				return nil, syntaxError(pos, "unterminated JSON literal")
+			}
+			raw := strings.TrimSpace(strings.ReplaceAll(expression[pos+1:end-1], "\\`", "`"))
+			var literal RootValue
+			if err := json.Unmarshal([]byte(raw), &literal); err != nil {
+				// legacy literal, which is not valid JSON, is regarded as string
+				var quoted string
+				if err := json.Unmarshal([]byte(`"`+raw+`"`), &quoted); err != nil {
+					return nil, syntaxError(pos, "invalid JSON literal %s", raw)
+				}
+				literal.JsonValue = jmString(quoted)
+			}
+			tokens = append(tokens, jmToken{kind: jmLiteralToken, value: raw, json: literal.JsonValue, pos: pos})
+			pos = end
+		default:
+			matched := false
+			for _, width := range []int{2, 1} {
+				if pos+width <= len(expression) {
+					if kind, ok := simple[expression[pos:pos+width]]; ok {
+						tokens = append(tokens, jmToken{kind: kind, value: expression[pos : pos+width], pos: pos})
+						pos += width
+						matched = true
+						break
+					}
+				}
+			}
+			if !matched {
+				return nil, syntaxError(pos, "unknown token %q", c)
+			}
+		}
+	}
+	return append(tokens, jmToken{kind: jmEOF, pos: len(expression)}), nil
+}
+func jmScanQuoted(expression string, start int, quote byte) (int, error) {
+	for pos := start + 1; pos < len(expression); pos++ {
+		switch expression[pos] {
+		case '\\':
+			pos++
+		case quote:
+			return pos + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("unterminated %c", quote)
+}
+
+func (p *jmParser) errorf(format string, args ...any) error {
+	return ErrJmesPath{Kind: "syntax", Expression: p.expression, Offset: p.current().pos, Reason: fmt.Sprintf(format, args...)}
+}
+func (p *jmParser) current() jmToken {
+	return p.tokens[p.index]
+}
+func (p *jmParser) lookahead(n int) jmTokenKind {
+	if p.index+n < len(p.tokens) {
+		return p.tokens[p.index+n].kind
+	}
+	return jmEOF
+}
+func (p *jmParser) advance() jmToken {
+	t := p.tokens[p.index]
+	if p.index < len(p.tokens)-1 {
+		p.index++
+	}
+	return t
+}
+func (p *jmParser) match(kind jmTokenKind) error {
+	if p.current().kind != kind {
+		return p.errorf("expected %s, got %s", kind, p.current().kind)
+	}
+	p.advance()
+	return nil
+}
+
+func (p *jmParser) parseExpression(bindingPower int) (jmNode, error) {
+	left, err := p.nud(p.advance())
+	if err != nil {
+		return nil, err
+	}
+	for bindingPower < jmBindingPower[p.current().kind] {
+		if left, err = p.led(p.advance(), left); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+func (p *jmParser) nud(token jmToken) (jmNode, error) {
+	switch token.kind {
+	case jmLiteralToken:
+		return jmLiteral{literal: token.json}, nil
+	case jmUnquotedIdentifer:
+		return jmField(token.value), nil
+	case jmQuotedIdentifier:
+		if p.current().kind == jmLParen {
+			return nil, p.errorf("quoted identifier cannot be function name")
+		}
+		return jmField(token.value), nil
+	case jmStar:
+		right, err := p.parseProjectionRHS(jmBindingPower[jmStar])
+		return jmValueProjection{left: jmIdentity{}, right: right}, err
+	case jmFilter:
+		return p.ledFilter(jmIdentity{})
+	case jmLBrace:
+		return p.parseMultiSelectHash()
+	case jmLParen:
+		expr, err := p.parseExpression(0)
+		if err != nil {
+			return nil, err
+		}
+		return expr, p.match(jmRParen)
+	case jmFlattenToken:
+		right, err := p.parseProjectionRHS(jmBindingPower[jmFlattenToken])
+		return jmProjection{left: jmFlatten{expr: jmIdentity{}}, right: right}, err
+	case jmNotToken:
+		expr, err := p.parseExpression(jmBindingPower[jmNotToken])
+		return jmNot{expr: expr}, err
+	case jmLBracket:
+		switch {
+		case p.current().kind == jmNumberToken || p.current().kind == jmColon:
+			right, err := p.parseIndexExpression()
+			if err != nil {
+				return nil, err
+			}
+			return p.projectIfSlice(jmIdentity{}, right)
+		case p.current().kind == jmStar && p.lookahead(1) == jmRBracket:
+			p.advance()
+			p.advance()
+			right, err := p.parseProjectionRHS(jmBindingPower[jmStar])
+			return jmProjection{left: jmIdentity{}, right: right}, err
+		default:
+			return p.parseMultiSelectList()
+		}
+	case jmCurrent:
+		return jmIdentity{}, nil
+	case jmExprefToken:
+		expr, err := p.parseExpression(0)
+		return jmExpref{expr: expr}, err
+	default:
+		return nil, ErrJmesPath{Kind: "syntax", Expression: p.expression, Offset: token.pos, Reason: fmt.Sprintf("unexpected token %s", token.kind)}
+	}
+}
+func (p *jmParser) led(token jmToken, left jmNode) (jmNode, error) {
+	switch token.kind {
+	case jmDot:
+		if p.current().kind != jmStar {
+			right, err := p.parseDotRHS(jmBindingPower[jmDot])
+			return jmSubexpression{left: left, right: right}, err
+		}
+		p.advance()
+		right, err := p.parseProjectionRHS(jmBindingPower[jmDot])
+		return jmValueProjection{left: left, right: right}, err
+	case jmPipeToken:
+		right, err := p.parseExpression(jmBindingPower[jmPipeToken])
+		return jmPipe{left: left, right: right}, err
+	case jmOrToken:
+		right, err := p.parseExpression(jmBindingPower[jmOrToken])
+		return jmOr{left: left, right: right}, err
+	case jmAndToken:
+		right, err := p.parseExpression(jmBindingPower[jmAndToken])
+		return jmAnd{left: left, right: right}, err
+	case jmLParen:
+		name, ok := left.(jmField)
+		if !ok {
+			return nil, p.errorf("invalid function name")
+		}
+		args := make([]jmNode, 0)
+		for p.current().kind != jmRParen {
+			arg, err := p.parseExpression(0)
+			if err != nil {
+				return nil, err
+			}
+			if p.current().kind == jmComma {
+				p.advance()
+				if p.current().kind == jmRParen {
+					return nil, p.errorf("unexpected )")
+				}
+			} else if p.current().kind != jmRParen {
+				return nil, p.errorf("expected , or )")
+			}
+			args = append(args, arg)
+		}
+		p.advance()
+		return p.newCall(string(name), args)
+	case jmFilter:
+		return p.ledFilter(left)
+	case jmEQ, jmNE, jmLT, jmLTE, jmGT, jmGTE:
+		right, err := p.parseExpression(jmBindingPower[token.kind])
+		return jmComparator{op: token.kind, left: left, right: right}, err
+	case jmFlattenToken:
+		right, err := p.parseProjectionRHS(jmBindingPower[jmFlattenToken])
+		return jmProjection{left: jmFlatten{expr: left}, right: right}, err
+	case jmLBracket:
+		if kind := p.current().kind; kind == jmNumberToken || kind == jmColon {
+			right, err := p.parseIndexExpression()
+			if err != nil {
+				return nil, err
+			}
+			return p.projectIfSlice(left, right)
+		}
+		if err := p.match(jmStar); err != nil {
+			return nil, err
+		}
+		if err := p.match(jmRBracket); err != nil {
+			return nil, err
+		}
+		right, err := p.parseProjectionRHS(jmBindingPower[jmStar])
+		return jmProjection{left: left, right: right}, err
+	default:
+		return nil, ErrJmesPath{Kind: "syntax", Expression: p.expression, Offset: token.pos, Reason: fmt.Sprintf("unexpected token %s", token.kind)}
+	}
+}
+func (p *jmParser) newCall(name string, args []jmNode) (jmNode, error) {
+	function, ok := jmFunctions[name]
+	if !ok {
+		return nil, ErrJmesPath{Kind: "unknown-function", Expression: p.expression, Offset: p.current().pos, Reason: fmt.Sprintf("unknown function %s", name)}
+	}
+	if len(args) < function.arity || (!function.variadic && len(args) > function.arity) {
+		return nil, ErrJmesPath{Kind: "invalid-arity", Expression: p.expression, Offset: p.current().pos, Reason: fmt.Sprintf("function %s takes %d arguments, got %d", name, function.arity, len(args))}
+	}
+	return jmCall{name: name, function: function, args: args}, nil
+}
+func (p *jmParser) ledFilter(left jmNode) (jmNode, error) {
+	condition, err := p.parseExpression(0)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.match(jmRBracket); err != nil {
+		return nil, err
+	}
+	var right jmNode = jmIdentity{}
+	if p.current().kind != jmFlattenToken {
+		if right, err = p.parseProjectionRHS(jmBindingPower[jmFilter]); err != nil {
+			return nil, err
+		}
+	}
+	return jmFilterProjection{left: left, right: right, condition: condition}, nil
+}
+func (p *jmParser) parseIndexExpression() (jmNode, error) {
+	if p.lookahead(0) == jmColon || p.lookahead(1) == jmColon {
+		return p.parseSliceExpression()
+	}
+	index, err := strconv.Atoi(p.advance().value)
+	if err != nil {
+		return nil, p.errorf("invalid index")
+	}
+	return jmIndex(index), p.match(jmRBracket)
+}
+func (p *jmParser) parseSliceExpression() (jmNode, error) {
+	parts, index := [3]*int{}, 0
+	for p.current().kind != jmRBracket && index < 3 {
+		switch p.current().kind {
+		case jmColon:
+			index++
+			if index == 3 {
+				return nil, p.errorf("too many colons in slice")
+			}
+			p.advance()
+		case jmNumberToken:
+			n, err := strconv.Atoi(p.advance().value)
+			if err != nil {
+				return nil, p.errorf("invalid slice")
+			}
+			parts[index] = &n
+		default:
+			return nil, p.errorf("unexpected token %s in slice", p.current().kind)
+		}
+	}
+	if err := p.match(jmRBracket); err != nil {
+		return nil, err
+	}
+	return jmSlice{start: parts[0], end: parts[1], step: parts[2]}, nil
+}
+func (p *jmParser) projectIfSlice(left, right jmNode) (jmNode, error) {
+	indexed := jmSubexpression{left: left, right: right}
+	if _, ok := right.(jmSlice); ok {
+		rhs, err := p.parseProjectionRHS(jmBindingPower[jmStar])
+		return jmProjection{left: indexed, right: rhs}, err
+	}
+	return indexed, nil
+}
+func (p *jmParser) parseProjectionRHS(bindingPower int) (jmNode, error) {
+	switch kind := p.current().kind; {
+	case jmBindingPower[kind] < 10:
+		return jmIdentity{}, nil
+	case kind == jmLBracket || kind == jmFilter:
+		return p.parseExpression(bindingPower)
+	case kind == jmDot:
+		p.advance()
+		return p.parseDotRHS(bindingPower)
+	default:
+		return nil, p.errorf("unexpected token %s after projection", kind)
+	}
+}
+func (p *jmParser) parseDotRHS(bindingPower int) (jmNode, error) {
+	switch p.current().kind {
+	case jmUnquotedIdentifer, jmQuotedIdentifier, jmStar:
+		return p.parseExpression(bindingPower)
+	case jmLBracket:
+		p.advance()
+		return p.parseMultiSelectList()
+	case jmLBrace:
+		p.advance()
+		return p.parseMultiSelectHash()
+	default:
+		return nil, p.errorf("unexpected token %s after dot", p.current().kind)
+	}
+}
+func (p *jmParser) parseMultiSelectList() (jmNode, error) {
+	exprs := make(jmMultiSelectList, 0)
+	for {
+		expr, err := p.parseExpression(0)
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+		if p.current().kind == jmRBracket {
+			p.advance()
+			return exprs, nil
+		}
+		if err := p.match(jmComma); err != nil {
+			return nil, err
+		}
+	}
+}
+func (p *jmParser) parseMultiSelectHash() (jmNode, error) {
+	hash := jmMultiSelectHash{}
+	for {
+		key := p.advance()
+		if key.kind != jmUnquotedIdentifer && key.kind != jmQuotedIdentifier {
+			return nil, ErrJmesPath{Kind: "syntax", Expression: p.expression, Offset: key.pos, Reason: "expected identifier as key"}
+		}
+		if err := p.match(jmColon); err != nil {
+			return nil, err
+		}
+		expr, err := p.parseExpression(0)
+		if err != nil {
+			return nil, err
+		}
+		hash.keys, hash.exprs = append(hash.keys, key.value), append(hash.exprs, expr)
+		if p.current().kind == jmRBrace {
+			p.advance()
+			return hash, nil
+		}
+		if err := p.match(jmComma); err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,296 @@
+package fluffyjson_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	fluffyjson "github.com/hayas1/go-fluffy-json"
+)
+
+func ExampleJmesPath_Evaluate() {
+	var value fluffyjson.RootValue
+	if err := json.Unmarshal([]byte(`{
+		"locations": [
+			{"name": "Seattle", "state": "WA"},
+			{"name": "New York", "state": "NY"},
+			{"name": "Bellevue", "state": "WA"},
+			{"name": "Olympia", "state": "WA"}
+		]
+	}`), &value); err != nil {
+		panic(err)
+	}
+
+	jm, err := fluffyjson.ParseJmesPath(`locations[?state == 'WA'].name | sort(@) | {WashingtonCities: join(', ', @)}`)
+	if err != nil {
+		panic(err)
+	}
+	result, err := jm.Evaluate(&value)
+	if err != nil {
+		panic(err)
+	}
+	b, err := json.Marshal(result)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(b))
+	// Output:
+	// {"WashingtonCities":"Bellevue, Olympia, Seattle"}
+}
+
+// The cases are the compliance tests of https://github.com/jmespath/jmespath.test (see testdata/jmespath-compliance/README.md).
+func TestJmesPathCompliance(t *testing.T) {
+	// skipped are the cases that this implementation does not follow, with the reasons, such as
+	// "wildcard.json/15/1": "the members of object are ordered by the keys, not by the document order"
+	skipped := map[string]string{}
+
+	files, err := filepath.Glob("testdata/jmespath-compliance/*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var suite []struct {
+			Given json.RawMessage `json:"given"`
+			Cases []struct {
+				Expression string          `json:"expression"`
+				Result     json.RawMessage `json:"result"`
+				Error      string          `json:"error"`
+			} `json:"cases"`
+		}
+		if err := json.Unmarshal(b, &suite); err != nil {
+			t.Fatal(err)
+		}
+
+		for i, group := range suite {
+			for j, tc := range group.Cases {
+				name := fmt.Sprintf("%s/%d/%d", filepath.Base(file), i, j)
+				t.Run(name, func(t *testing.T) {
+					if reason, ok := skipped[name]; ok {
+						t.Skip(reason)
+					}
+					given := HelperUnmarshalValue(t, string(group.Given))
+					jm, err := fluffyjson.ParseJmesPath(tc.Expression)
+					var result fluffyjson.JsonValue
+					if err == nil {
+						result, err = jm.Evaluate(&given)
+					}
+
+					var errJmesPath fluffyjson.ErrJmesPath
+					if errors.As(err, &errJmesPath) {
+						HelperFatalEvaluate(t, tc.Error, errJmesPath.Kind)
+						return
+					} else if err != nil {
+						t.Fatal(err)
+					} else if tc.Error != "" {
+						t.Fatalf("%s must be %s error, but got %s", tc.Expression, tc.Error, compact(t, result))
+					}
+					expected, actual := HelperUnmarshalValue(t, string(tc.Result)), fluffyjson.RootValue{JsonValue: result}
+					if !fluffyjson.Equal(&expected, &actual) {
+						t.Fatalf("%s: expected %s, but got %s", tc.Expression, tc.Result, compact(t, result))
+					}
+				})
+			}
+		}
+	}
+}
+
+func compact(t *testing.T, v fluffyjson.JsonValue) string {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestJmesPathEvaluate(t *testing.T) {
+	const people = `{
+		"people": [
+			{"age": 20, "age_str": "20", "bool": true, "name": "a", "extra": "foo"},
+			{"age": 40, "age_str": "40", "bool": false, "name": "b", "extra": "bar"},
+			{"age": 30, "age_str": "30", "bool": true, "name": "c"},
+			{"age": 50, "age_str": "50", "bool": false, "name": "d"},
+			{"age": 10, "age_str": "10", "bool": true, "name": 3}
+		]
+	}`
+	const functions = `{
+		"foo": -1,
+		"zero": 0,
+		"numbers": [-1, 3, 4, 5],
+		"array": [-1, 3, 4, 5, "a", "100"],
+		"strings": ["a", "b", "c"],
+		"decimals": [1.01, 1.2, -1.5],
+		"str": "Str",
+		"false": false,
+		"empty_list": [],
+		"empty_hash": {},
+		"objects": {"foo": "bar", "bar": "baz"},
+		"null_key": null
+	}`
+
+	testcases := map[string]struct {
+		target     string
+		expression string
+		expected   string
+	}{
+		// basic
+		"field":              {`{"foo": {"bar": {"baz": "correct"}}}`, `foo`, `{"bar": {"baz": "correct"}}`},
+		"subexpression":      {`{"foo": {"bar": {"baz": "correct"}}}`, `foo.bar.baz`, `"correct"`},
+		"missing field":      {`{"foo": {"bar": {"baz": "correct"}}}`, `foo.bar.baz.bad`, `null`},
+		"field of array":     {`{"foo": [1, 2]}`, `foo.bar`, `null`},
+		"quoted identifier":  {`{"foo.bar": 1, "with space": 2}`, `"with space"`, `2`},
+		"escaped identifier": {`{"\"": true, "\\": 1}`, `"\\"`, `1`},
+		"unicode identifier": {`{"☃": true}`, `"☃"`, `true`},
+		"current node":       {`{"foo": 1}`, `@.foo`, `1`},
+		"pipe current node":  {`{"foo": [0, 1]}`, `foo | @[1]`, `1`},
+		// indices and slices
+		"index":              {`{"foo": {"bar": ["zero", "one", "two"]}}`, `foo.bar[0]`, `"zero"`},
+		"negative index":     {`{"foo": {"bar": ["zero", "one", "two"]}}`, `foo.bar[-1]`, `"two"`},
+		"index out of range": {`{"foo": {"bar": ["zero", "one", "two"]}}`, `foo.bar[3]`, `null`},
+		"index of object":    {`{"foo": {"bar": 1}}`, `foo[0]`, `null`},
+		"slice":              {`{"foo": [0, 1, 2, 3, 4, 5, 6, 7, 8, 9]}`, `foo[0:5]`, `[0, 1, 2, 3, 4]`},
+		"slice with step":    {`{"foo": [0, 1, 2, 3, 4, 5, 6, 7, 8, 9]}`, `foo[::2]`, `[0, 2, 4, 6, 8]`},
+		"reversed slice":     {`{"foo": [0, 1, 2, 3, 4, 5, 6, 7, 8, 9]}`, `foo[::-1]`, `[9, 8, 7, 6, 5, 4, 3, 2, 1, 0]`},
+		"negative slice":     {`{"foo": [0, 1, 2, 3, 4, 5, 6, 7, 8, 9]}`, `foo[-3:]`, `[7, 8, 9]`},
+		"clamped slice":      {`{"foo": [0, 1, 2]}`, `foo[-10:10:1]`, `[0, 1, 2]`},
+		"slice projection":   {`{"foo": [{"a": 1}, {"a": 2}, {"a": 3}]}`, `foo[:2].a`, `[1, 2]`},
+		"slice of object":    {`{"foo": {"a": 1}}`, `foo[:2]`, `null`},
+		"zero step":          {`{"foo": [0, 1, 2]}`, `foo[::0]`, `invalid-value`},
+		// projections
+		"list projection":      {`{"foo": [{"bar": 1}, {"bar": 2}, {"baz": 3}]}`, `foo[*].bar`, `[1, 2]`},
+		"nested projection":    {`{"foo": [{"bar": [{"kind": "a"}, {"kind": "b"}]}, {"bar": [{"kind": "c"}]}]}`, `foo[*].bar[*].kind`, `[["a", "b"], ["c"]]`},
+		"projection of object": {`{"foo": {"bar": 1}}`, `foo[*].bar`, `null`},
+		"object projection":    {`{"foo": {"a": {"bar": 1}, "b": {"bar": 2}, "c": {"baz": 3}}}`, `foo.*.bar`, `[1, 2]`},
+		"root object wildcard": {`{"a": 1, "b": 2}`, `*`, `[1, 2]`},
+		"flatten":              {`{"foo": [[1, 2], [3], 4]}`, `foo[]`, `[1, 2, 3, 4]`},
+		"flatten projection":   {`{"foo": [{"bar": [{"kind": "a"}, {"kind": "b"}]}, {"bar": [{"kind": "c"}]}]}`, `foo[].bar[].kind`, `["a", "b", "c"]`},
+		"projection stops":     {`{"foo": [{"bar": 1}, {"bar": 2}]}`, `foo[*].bar | [0]`, `1`},
+		"projection index":     {`{"foo": [{"bar": [1, 2]}, {"bar": [3, 4]}]}`, `foo[*].bar[0]`, `[1, 3]`},
+		"wildcard index":       {`[{"a": 1}, {"a": 2}]`, `[*].a`, `[1, 2]`},
+		"flatten root":         {`[[1], [2, [3]]]`, `[]`, `[1, 2, [3]]`},
+		// filters
+		"filter":                {people, `people[?age > ` + "`30`" + `].name`, `["b", "d"]`},
+		"filter equal string":   {people, `people[?name == 'a'].age`, `[20]`},
+		"filter not equal":      {people, `people[?age != ` + "`20`" + `] | length(@)`, `4`},
+		"filter and":            {people, `people[?age >= ` + "`20`" + ` && bool].name`, `["a", "c"]`},
+		"filter or":             {people, `people[?age == ` + "`10`" + ` || age == ` + "`50`" + `].name`, `["d", 3]`},
+		"filter not":            {people, `people[?!bool].name`, `["b", "d"]`},
+		"filter existence":      {people, `people[?extra].name`, `["a", "b"]`},
+		"filter string compare": {people, `people[?age_str > '30'].name`, `[]`},
+		"filter current":        {`{"foo": ["a", "b", "a"]}`, `foo[?@ == 'a']`, `["a", "a"]`},
+		"filter literal object": {`{"foo": [{"a": {"b": 1}}, {"a": {"b": 2}}]}`, "foo[?a == `{\"b\": 1}`]", `[{"a": {"b": 1}}]`},
+		"filter then flatten":   {`{"foo": [[1, 2], [3], [4, 5]]}`, `foo[?length(@) > ` + "`1`" + `][]`, `[1, 2, 4, 5]`},
+		// multiselect
+		"multiselect list":    {`{"foo": {"bar": 1, "baz": 2, "qux": 3}}`, `foo.[bar, baz]`, `[1, 2]`},
+		"multiselect hash":    {`{"foo": {"bar": 1, "baz": 2, "qux": 3}}`, `foo.{a: bar, "b c": baz}`, `{"a": 1, "b c": 2}`},
+		"multiselect of null": {`{"foo": {"bar": 1}}`, `missing.{a: bar}`, `null`},
+		"multiselect nested":  {`{"foo": [{"a": 1, "b": 2}, {"a": 3, "b": 4}]}`, `foo[*].[a, b]`, `[[1, 2], [3, 4]]`},
+		"multiselect root":    {`{"a": 1, "b": {"c": 2}}`, `[a, b.c]`, `[1, 2]`},
+		// literals and booleans
+		"json literal":     {`{}`, "`{\"a\": [1, true, null]}`", `{"a": [1, true, null]}`},
+		"escaped backtick": {`{}`, "`\"a\\`b\"`", `"a` + "`" + `b"`},
+		"legacy literal":   {`{}`, "`foo`", `"foo"`},
+		"raw string":       {`{}`, `'foo\'s \n'`, `"foo's \\n"`},
+		"or":               {`{"a": null, "b": false, "c": "c"}`, `a || b || c`, `"c"`},
+		"and":              {`{"a": "a", "b": [], "c": "c"}`, `a && b`, `[]`},
+		"not":              {`{"a": 0, "b": ""}`, `[!a, !b]`, `[false, true]`},
+		"paren":            {`{"a": true, "b": false, "c": true}`, `!(a && b) && c`, `true`},
+		"compare numbers":  {`{"one": 1, "two": 2}`, `[one < two, one <= two, one > two, one >= two]`, `[true, true, false, false]`},
+		"compare strings":  {`{"a": "a", "b": "b"}`, `a < b`, `null`},
+		"deep equal":       {`{"a": [1, {"b": 2}], "c": [1, {"b": 2}]}`, `a == c`, `true`},
+		// functions
+		"abs":                 {functions, `abs(foo)`, `1`},
+		"avg":                 {functions, `avg(numbers)`, `2.75`},
+		"avg empty":           {functions, `avg(empty_list)`, `null`},
+		"avg invalid":         {functions, `avg(array)`, `invalid-type`},
+		"ceil":                {functions, `ceil(` + "`1.2`" + `)`, `2`},
+		"floor":               {functions, `floor(decimals[2])`, `-2`},
+		"contains array":      {functions, `contains(strings, 'a')`, `true`},
+		"contains string":     {functions, `contains(str, 'tr')`, `true`},
+		"contains invalid":    {functions, `contains(foo, 'a')`, `invalid-type`},
+		"starts_with":         {functions, `starts_with(str, 'S')`, `true`},
+		"ends_with":           {functions, `ends_with(str, 'r')`, `true`},
+		"join":                {functions, `join(', ', strings)`, `"a, b, c"`},
+		"join invalid":        {functions, `join(', ', numbers)`, `invalid-type`},
+		"keys":                {functions, `keys(objects)`, `["bar", "foo"]`},
+		"values":              {functions, `values(objects)`, `["baz", "bar"]`},
+		"length":              {functions, `[length(str), length(strings), length(objects), length('☃')]`, `[3, 3, 2, 1]`},
+		"length invalid":      {functions, `length(foo)`, `invalid-type`},
+		"map":                 {people, `map(&name, people)`, `["a", "b", "c", "d", 3]`},
+		"map missing":         {`[{"a": 1}, {"b": 2}]`, `map(&a, @)`, `[1, null]`},
+		"max":                 {functions, `[max(numbers), max(strings)]`, `[5, "c"]`},
+		"max empty":           {functions, `max(empty_list)`, `null`},
+		"max mixed":           {functions, `max(array)`, `invalid-type`},
+		"min":                 {functions, `[min(numbers), min(strings)]`, `[-1, "a"]`},
+		"max_by":              {people, `max_by(people, &age).name`, `"d"`},
+		"min_by":              {people, `min_by(people, &age_str).age`, `10`},
+		"max_by invalid":      {people, `max_by(people, &bool)`, `invalid-type`},
+		"merge":               {`{}`, "merge(`{\"a\": 1, \"b\": 2}`, `{\"b\": 3}`)", `{"a": 1, "b": 3}`},
+		"not_null":            {functions, `not_null(missing, null_key, str)`, `"Str"`},
+		"reverse":             {functions, `[reverse(str), reverse(numbers)]`, `["rtS", [5, 4, 3, -1]]`},
+		"sort":                {functions, `sort(decimals)`, `[-1.5, 1.01, 1.2]`},
+		"sort mixed":          {functions, `sort(array)`, `invalid-type`},
+		"sort_by":             {people, `sort_by(people, &age)[].name`, `[3, "a", "c", "b", "d"]`},
+		"sort_by invalid":     {people, `sort_by(people, &bool)[].name`, `invalid-type`},
+		"sum":                 {functions, `[sum(numbers), sum(empty_list)]`, `[11, 0]`},
+		"to_array":            {functions, `[to_array(str), to_array(strings)]`, `[["Str"], ["a", "b", "c"]]`},
+		"to_number":           {functions, `[to_number('1.5'), to_number(foo), to_number('abc'), to_number(false)]`, `[1.5, -1, null, null]`},
+		"to_string":           {functions, `[to_string(str), to_string(numbers), to_string(objects)]`, `["Str", "[-1,3,4,5]", "{\"bar\":\"baz\",\"foo\":\"bar\"}"]`},
+		"type":                {functions, `[type(objects), type(strings), type(str), type(foo), type(false), type(null_key)]`, `["object", "array", "string", "number", "boolean", "null"]`},
+		"function in filter":  {people, `people[?contains(age_str, '3')].age`, `[30]`},
+		"function of pipe":    {people, `people[*].age | sum(@)`, `150`},
+		"expref outside":      {people, `&age`, `invalid-type`},
+		"expref not expected": {functions, `length(&foo)`, `invalid-type`},
+		"unknown function":    {functions, `unknown(foo)`, `unknown-function`},
+		"too many arguments":  {functions, `abs(foo, foo)`, `invalid-arity`},
+		"too few arguments":   {functions, `merge()`, `invalid-arity`},
+		// syntax
+		"empty":                {`{}`, ``, `syntax`},
+		"trailing dot":         {`{}`, `foo.`, `syntax`},
+		"leading dot":          {`{}`, `.foo`, `syntax`},
+		"unclosed bracket":     {`{}`, `foo[0`, `syntax`},
+		"too many colons":      {`{}`, `foo[:::]`, `syntax`},
+		"empty multiselect":    {`{}`, `foo.[]`, `syntax`},
+		"hash without key":     {`{}`, `{: a}`, `syntax`},
+		"quoted function":      {`{}`, `"abs"(foo)`, `syntax`},
+		"single equal":         {`{}`, `a = b`, `syntax`},
+		"unterminated literal": {`{}`, "`foo", `syntax`},
+		"number as identifier": {`{}`, `foo.1`, `syntax`},
+		"trailing comma":       {`{}`, `abs(foo,)`, `syntax`},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			value := HelperUnmarshalValue(t, tc.target)
+			jm, err := fluffyjson.ParseJmesPath(tc.expression)
+			var result fluffyjson.JsonValue
+			if err == nil {
+				result, err = jm.Evaluate(&value)
+			}
+
+			var errJmesPath fluffyjson.ErrJmesPath
+			if errors.As(err, &errJmesPath) {
+				HelperFatalEvaluate(t, tc.expected, errJmesPath.Kind)
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			actual, err := json.Marshal(result)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected, err := json.Marshal(HelperUnmarshalValue(t, tc.expected))
+			if err != nil {
+				t.Fatal(err)
+			}
+			HelperFatalEvaluate(t, string(expected), string(actual))
+		})
+	}
+}
@@ -0,0 +1,3113 @@
+package fluffyjson
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"maps"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+type (
+	// Jq is the filter of the subset of jq(https://jqlang.github.io/jq/manual/).
+	// Supported are paths, pipes, comma, literals, string interpolation, arithmetic, comparison, and, or, alternative,
+	// array and object construction, if, try, optional, variable binding, reduce, foreach, formats, and the most builtins.
+	// Path expressions are supported only by path, paths, del and delpaths, whose paths are formed from `.`, `..`, indices,
+	// iterations, pipes, comma, if, optional, variable binding, select, recurse, getpath and empty.
+	// Function definitions, assignments, labels, and modules are not supported.
+	// Regular expressions are Go regexp (RE2) instead of Oniguruma.
+	// Repeating a string such as `"ab" * 3` fails when the result exceeds 16 MiB.
+	Jq struct {
+		filter string
+		ast    jqNode
+	}
+
+	// ErrJq is the syntax error of jq filter.
+	ErrJq struct {
+		Filter string
+		Offset int
+		Reason string
+	}
+	// ErrJqRuntime is the error raised while running jq filter, Value is the error value such as `error("message")`.
+	ErrJqRuntime struct {
+		Value JsonValue
+	}
+	// ErrJqStepLimit is returned when running jq filter exceeds the limit of [JqStepLimit].
+	ErrJqStepLimit struct {
+		Limit int
+	}
+
+	JqOption  func(*jqOptions)
+	jqOptions struct {
+		ctx   context.Context
+		limit int
+	}
+	// jqBudget counts the steps of a run, which is shared by all environments of the run.
+	jqBudget struct {
+		jqOptions
+		steps int
+	}
+
+	jqNode interface {
+		eval(env *jqEnv, v JsonValue) iter.Seq2[JsonValue, error]
+	}
+	jqEnv struct {
+		name   string
+		value  JsonValue
+		parent *jqEnv
+		budget *jqBudget
+	}
+	jqFunction func(env *jqEnv, v JsonValue, args []jqNode) iter.Seq2[JsonValue, error]
+
+	jqIdentity struct{}
+	jqRecurse  struct{}
+	jqLiteral  struct{ literal JsonValue }
+	jqVar      string
+	jqFormat   string
+	jqIndex    struct{ target, index jqNode }
+	jqSlice    struct{ target, from, to jqNode }
+	jqIterate  struct{ target jqNode }
+	jqOptional struct{ body jqNode }
+	jqTry      struct{ body, catch jqNode }
+	jqNeg      struct{ body jqNode }
+	jqPipe     struct{ left, right jqNode }
+	jqComma    struct{ left, right jqNode }
+	jqAlt      struct{ left, right jqNode }
+	jqAnd      struct{ left, right jqNode }
+	jqOr       struct{ left, right jqNode }
+	jqBinary   struct {
+		op          string
+		left, right jqNode
+	}
+	jqArray         struct{ body jqNode }
+	jqObject        []jqEntry
+	jqEntry         struct{ key, value jqNode }
+	jqInterpolation []jqPart
+	jqPart          struct {
+		literal string
+		expr    jqNode
+	}
+	jqIf   struct{ cond, then, els jqNode }
+	jqBind struct {
+		source jqNode
+		name   string
+		body   jqNode
+	}
+	jqReduce struct {
+		source       jqNode
+		name         string
+		init, update jqNode
+	}
+	jqForeach struct {
+		source                jqNode
+		name                  string
+		init, update, extract jqNode
+	}
+	jqCall struct {
+		name     string
+		function jqFunction
+		args     []jqNode
+	}
+	// jqPathValue is the output of path expression, which is the path from the input and the value there.
+	jqPathValue struct {
+		path  Array
+		value JsonValue
+	}
+
+	jqToken struct {
+		kind  string
+		value string
+		parts []any // string or []jqToken of interpolation
+		pos   int
+	}
+	jqLexer struct {
+		filter string
+		pos    int
+	}
+	jqParser struct {
+		filter  string
+		tokens  []jqToken
+		index   int
+		vars    []string
+		pending jqNode
+	}
+)
+
+var (
+	jqKeywords = []string{
+		"if", "then", "elif", "else", "end", "as", "reduce", "foreach", "try", "catch",
+		"label", "import", "include", "def", "and", "or", "__loc__",
+	}
+	jqRegexps sync.Map
+)
+
+func (e ErrJq) Error() string {
+	return fmt.Sprintf("syntax error in jq %q at %d: %s", e.Filter, e.Offset, e.Reason)
+}
+func (e ErrJqRuntime) Error() string {
+	if s, ok := e.Value.(*String); ok {
+		return string(*s)
+	}
+	return compactJson(e.Value) + " (not a string)"
+}
+func (e ErrJqStepLimit) Error() string {
+	return fmt.Sprintf("jq exceeded the limit of %d steps", e.Limit)
+}
+func (e ErrJqStepLimit) Is(target error) bool {
+	_, ok := target.(ErrJqStepLimit)
+	return ok
+}
+
+// JqContext stops running the filter with the error of the context when the context is done.
+func JqContext(ctx context.Context) JqOption {
+	return func(o *jqOptions) { o.ctx = ctx }
+}
+
+// JqStepLimit stops running the filter with [ErrJqStepLimit] when it takes more than n steps, such as
+// the outputs passed through pipes, the iterated elements, the function calls, and the iterations of reduce.
+// A string built by repetition such as `"ab" * 3` takes as many steps as its bytes.
+// It is unlimited by default, so the filter such as `recurse(. + 1)` never ends without limit.
+func JqStepLimit(n int) JqOption {
+	return func(o *jqOptions) { o.limit = n }
+}
+
+// ParseJq parses the jq filter, such as `.items[] | select(.price > 10) | {name, price}`.
+func ParseJq(filter string) (Jq, error) {
+	lexer := &jqLexer{filter: filter}
+	tokens, err := lexer.tokenize(false)
+	if err != nil {
+		return Jq{}, err
+	}
+	p := &jqParser{filter: filter, tokens: tokens}
+	ast, err := p.parsePipe(false)
+	if err != nil {
+		return Jq{}, err
+	}
+	if p.current().kind != "eof" {
+		return Jq{}, p.errorf("unexpected token %s", p.current().value)
+	}
+	return Jq{filter: filter, ast: ast}, nil
+}
+
+func (q Jq) String() string {
+	return q.filter
+}
+
+// Run runs the filter with the input v, and returns the stream of outputs.
+// The stream stops at the first error, which is returned by the second return value after the iteration.
+// The errors of [JqContext] and [JqStepLimit] cannot be caught by try, optional and alternative of the filter.
+func (q Jq) Run(v JsonValue, opts ...JqOption) (iter.Seq[JsonValue], func() error) {
+	v = unwrapRoot(v)
+	var options jqOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	var err error
+	outputs := func(yield func(JsonValue) bool) {
+		err = nil
+		env := &jqEnv{budget: &jqBudget{jqOptions: options}}
+		for output, e := range q.ast.eval(env, v) {
+			if e != nil {
+				err = e
+				return
+			}
+			if !yield(output) {
+				return
+			}
+		}
+	}
+	return outputs, func() error { return err }
+}
+
+func (env *jqEnv) bind(name string, value JsonValue) *jqEnv {
+	return &jqEnv{name: name, value: value, parent: env, budget: env.budget}
+}
+func (env *jqEnv) lookup(name string) JsonValue {
+	for e := env; e != nil; e = e.parent {
+		if e.name == name {
+			return e.value
+		}
+	}
+	return jqNull()
+}
+
+// step counts a step of the run, and returns the error if the run must stop.
+func (env *jqEnv) step() error {
+	return env.charge(1)
+}
+
+// charge counts n steps of the run, and returns the error if the run must stop.
+func (env *jqEnv) charge(n int) error {
+	b := env.budget
+	if b.ctx != nil {
+		if err := b.ctx.Err(); err != nil {
+			return err
+		}
+	}
+	if b.steps += n; b.limit > 0 && b.steps > b.limit {
+		return ErrJqStepLimit{Limit: b.limit}
+	}
+	return nil
+}
+
+// jqAborted reports whether the error stops the run, which cannot be caught.
+func jqAborted(err error) bool {
+	return errors.Is(err, ErrJqStepLimit{}) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+func jqNull() JsonValue            { return new(Null) }
+func jqBool(b bool) JsonValue      { v := Bool(b); return &v }
+func jqNumber(f float64) JsonValue { v := Number(f); return &v }
+func jqString(s string) JsonValue  { v := String(s); return &v }
+func jqErrorf(format string, args ...any) error {
+	return ErrJqRuntime{Value: jqString(fmt.Sprintf(format, args...))}
+}
+func jqOne(v JsonValue) iter.Seq2[JsonValue, error] {
+	return func(yield func(JsonValue, error) bool) { yield(v, nil) }
+}
+func jqFail(err error) iter.Seq2[JsonValue, error] {
+	return func(yield func(JsonValue, error) bool) { yield(nil, err) }
+}
+func jqTruthy(v JsonValue) bool {
+	if b, ok := v.(*Bool); ok {
+		return bool(*b)
+	}
+	return !v.IsNull()
+}
+func jqType(v JsonValue) string {
+	if v.IsBool() {
+		return "boolean"
+	}
+	return string(v.representation())
+}
+
+// jqToJson encodes the value as compact JSON without HTML escaping, as jq does.
+func jqDescribe(v JsonValue) string {
+	s := compactJson(v)
+	if len(s) > 30 {
+		s = s[:27] + "..."
+	}
+	return fmt.Sprintf("%s (%s)", jqType(v), s)
+}
+func jqCopy(v JsonValue) JsonValue {
+	switch t := v.(type) {
+	case *String:
+		return jqString(string(*t))
+	case *Number:
+		return jqNumber(float64(*t))
+	case *Bool:
+		return jqBool(bool(*t))
+	default:
+		return jqNull()
+	}
+}
+
+// jqCartesian evaluates each argument against v, and yields the cartesian product of the outputs.
+func jqCartesian(env *jqEnv, v JsonValue, args []jqNode) iter.Seq2[[]JsonValue, error] {
+	return func(yield func([]JsonValue, error) bool) {
+		var rec func(i int, acc []JsonValue) bool
+		rec = func(i int, acc []JsonValue) bool {
+			if i == len(args) {
+				return yield(slices.Clone(acc), nil)
+			}
+			for arg, err := range args[i].eval(env, v) {
+				if err != nil {
+					yield(nil, err)
+					return false
+				}
+				if !rec(i+1, append(acc, arg)) {
+					return false
+				}
+			}
+			return true
+		}
+		rec(0, make([]JsonValue, 0, len(args)))
+	}
+}
+
+// jqCollect collects all outputs of the node, until the first error.
+func jqCollect(node jqNode, env *jqEnv, v JsonValue) ([]JsonValue, error) {
+	outputs := make([]JsonValue, 0)
+	for output, err := range node.eval(env, v) {
+		if err != nil {
+			return nil, err
+		}
+		outputs = append(outputs, output)
+	}
+	return outputs, nil
+}
+
+// jqElements returns the elements of array or the values of object in key order.
+func jqElements(v JsonValue) ([]JsonValue, error) {
+	switch t := v.(type) {
+	case *Array:
+		return *t, nil
+	case *Object:
+		values := make([]JsonValue, 0, len(*t))
+		for _, k := range slices.Sorted(maps.Keys(*t)) {
+			values = append(values, (*t)[k])
+		}
+		return values, nil
+	default:
+		return nil, jqErrorf("Cannot iterate over %s", jqDescribe(v))
+	}
+}
+
+func (n jqIdentity) eval(_ *jqEnv, v JsonValue) iter.Seq2[JsonValue, error] {
+	return jqOne(v)
+}
+func (n jqRecurse) eval(env *jqEnv, v JsonValue) iter.Seq2[JsonValue, error] {
+	return func(yield func(JsonValue, error) bool) {
+		var rec func(v JsonValue) bool
+		rec = func(v JsonValue) bool {
+			if err := env.step(); err != nil {
+				yield(nil, err)
+				return false
+			}
+			if !yield(v, nil) {
+				return false
+			}
+			if v.IsArray() || v.IsObject() {
+				children, _ := jqElements(v)
+				for _, child := range children {
+					if !rec(child) {
+						return false
+					}
+				}
+			}
+			return true
+		}
+		rec(v)
+	}
+}
+func (n jqLiteral) eval(*jqEnv, JsonValue) iter.Seq2[JsonValue, error] {
+	return jqOne(jqCopy(n.literal))
+}
+func (n jqVar) eval(env *jqEnv, _ JsonValue) iter.Seq2[JsonValue, error] {
+	return jqOne(env.lookup(string(n)))
+}
+func (n jqFormat) eval(_ *jqEnv, v JsonValue) iter.Seq2[JsonValue, error] {
+	s, err := jqApplyFormat(string(n), v)
+	if err != nil {
+		return jqFail(err)
+	}
+	return jqOne(jqString(s))
+}
+func (n jqIndex) eval(env *jqEnv, v JsonValue) iter.Seq2[JsonValue, error] {
+	return func(yield func(JsonValue, error) bool) {
+		for target, err := range n.target.eval(env, v) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for index, err := range n.index.eval(env, v) {
+				if err != nil {
+					yield(nil, err)
+					return
+				}
+				result, err := jqIndexValue(target, index)
+				if err != nil {
+					yield(nil, err)
+					return
+				}
+				if !yield(result, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+func jqIndexValue(target, index JsonValue) (JsonValue, error) {
+	switch t := target.(type) {
+	case *Object:
+		if k, ok := index.(*String); ok {
+			if child, ok := (*t)[string(*k)]; ok {
+				return child, nil
+			}
+			return jqNull(), nil
+		}
+	case *Array:
+		if i, ok := index.(*Number); ok {
+			idx := int(math.Floor(float64(*i)))
+			if idx < 0 {
+				idx += len(*t)
+			}
+			if idx < 0 || idx >= len(*t) {
+				return jqNull(), nil
+			}
+			return (*t)[idx], nil
+		}
+	case *Null:
+		if index.IsString() || index.IsNumber() || index.IsNull() {
+			return jqNull(), nil
+		}
+	}
+	if index.IsString() {
+		return nil, jqErrorf("Cannot index %s with %s", jqType(target), compactJson(index))
+	}
+	return nil, jqErrorf("Cannot index %s with %s", jqType(target), jqType(index))
+}
+func (n jqSlice) eval(env *jqEnv, v JsonValue) iter.Seq2[JsonValue, error] {
+	bounds := []jqNode{jqLiteral{literal: jqNull()}, jqLiteral{literal: jqNull()}}
+	if n.from != nil {
+		bounds[0] = n.from
+	}
+	if n.to != nil {
+		bounds[1] = n.to
+	}
+	return func(yield func(JsonValue, error) bool) {
+		for target, err := range n.target.eval(env, v) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for bound, err := range jqCartesian(env, v, bounds) {
+				if err != nil {
+					yield(nil, err)
+					return
+				}
+				result, err := jqSliceValue(target, bound[0], bound[1])
+				if err != nil {
+					yield(nil, err)
+					return
+				}
+				if !yield(result, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+func jqSliceValue(target, from, to JsonValue) (JsonValue, error) {
+	var length int
+	switch t := target.(type) {
+	case *Null:
+		return jqNull(), nil
+	case *Array:
+		length = len(*t)
+	case *String:
+		length = utf8.RuneCountInString(string(*t))
+	default:
+		return nil, jqErrorf("Cannot index %s with object", jqType(target))
+	}
+	bound := func(b JsonValue, def int, round func(float64) float64) (int, error) {
+		switch t := b.(type) {
+		case *Null:
+			return def, nil
+		case *Number:
+			i := int(round(float64(*t)))
+			if i < 0 {
+				i += length
+			}
+			return min(max(i, 0), length), nil
+		default:
+			return 0, jqErrorf("Start and end indices of an array slice must be numbers")
+		}
+	}
+	start, err := bound(from, 0, math.Floor)
+	if err != nil {
+		return nil, err
+	}
+	end, err := bound(to, length, math.Ceil)
+	if err != nil {
+		return nil, err
+	}
+	end = max(start, end)
+	if s, ok := target.(*String); ok {
+		return jqString(string([]rune(string(*s))[start:end])), nil
+	}
+	sliced := slices.Clone((*target.(*Array))[start:end])
+	return &sliced, nil
+}
+func (n jqIterate) eval(env *jqEnv, v JsonValue) iter.Seq2[JsonValue, error] {
+	return func(yield func(JsonValue, error) bool) {
+		for target, err := range n.target.eval(env, v) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			elements, err := jqElements(target)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for _, elem := range elements {
+				if err := env.step(); err != nil {
+					yield(nil, err)
+					return
+				}
+				if !yield(elem, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+func (n jqOptional) eval(env *jqEnv, v JsonValue) iter.Seq2[JsonValue, error] {
+	return jqTry{body: n.body}.eval(env, v)
+}
+func (n jqTry) eval(env *jqEnv, v JsonValue) iter.Seq2[JsonValue, error] {
+	return func(yield func(JsonValue, error) bool) {
+		for output, err := range n.body.eval(env, v) {
+			if err != nil {
+				if jqAborted(err) {
+					yield(nil, err)
+					return
+				} else if n.catch == nil {
+					return
+				}
+				var runtime ErrJqRuntime
+				value := jqString(err.Error())
+				if errors.As(err, &runtime) {
+					value = runtime.Value
+				}
+				for caught, err := range n.catch.eval(env, value) {
+					if !yield(caught, err) || err != nil {
+						return
+					}
+				}
+				return
+			}
+			if !yield(output, nil) {
+				return
+			}
+		}
+	}
+}
+func (n jqNeg) eval(env *jqEnv, v JsonValue) iter.Seq2[JsonValue, error] {
+	return func(yield func(JsonValue, error) bool) {
+		for output, err := range n.body.eval(env, v) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			num, ok := output.(*Number)
+			if !ok {
+				yield(nil, jqErrorf("%s cannot be negated", jqDescribe(output)))
+				return
+			}
+			if !yield(jqNumber(-float64(*num)), nil) {
+				return
+			}
+		}
+	}
+}
+func (n jqPipe) eval(env *jqEnv, v JsonValue) iter.Seq2[JsonValue, error] {
+	return func(yield func(JsonValue, error) bool) {
+		for left, err := range n.left.eval(env, v) {
+			if err == nil {
+				err = env.step()
+			}
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for right, err := range n.right.eval(env, left) {
+				if !yield(right, err) || err != nil {
+					return
+				}
+			}
+		}
+	}
+}
+func (n jqComma) eval(env *jqEnv, v JsonValue) iter.Seq2[JsonValue, error] {
+	return func(yield func(JsonValue, error) bool) {
+		for _, node := range []jqNode{n.left, n.right} {
+			for output, err := range node.eval(env, v) {
+				if !yield(output, err) || err != nil {
+					return
+				}
+			}
+		}
+	}
+}
+func (n jqAlt) eval(env *jqEnv, v JsonValue) iter.Seq2[JsonValue, error] {
+	return func(yield func(JsonValue, error) bool) {
+		any := false
+		for left, err := range n.left.eval(env, v) {
+			if jqAborted(err) {
+				yield(nil, err)
+				return
+			} else if err != nil {
+				break
+			}
+			if jqTruthy(left) {
+				any = true
+				if !yield(left, nil) {
+					return
+				}
+			}
+		}
+		if !any {
+			for right, err := range n.right.eval(env, v) {
+				if !yield(right, err) || err != nil {
+					return
+				}
+			}
+		}
+	}
+}
+func (n jqAnd) eval(env *jqEnv, v JsonValue) iter.Seq2[JsonValue, error] {
+	return jqLogical(env, v, n.left, n.right, false)
+}
+func (n jqOr) eval(env *jqEnv, v JsonValue) iter.Seq2[JsonValue, error] {
+	return jqLogical(env, v, n.left, n.right, true)
+}
+func jqLogical(env *jqEnv, v JsonValue, left, right jqNode, short bool) iter.Seq2[JsonValue, error] {
+	return func(yield func(JsonValue, error) bool) {
+		for l, err := range left.eval(env, v) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if jqTruthy(l) == short {
+				if !yield(jqBool(short), nil) {
+					return
+				}
+				continue
+			}
+			for r, err := range right.eval(env, v) {
+				if err != nil {
+					yield(nil, err)
+					return
+				}
+				if !yield(jqBool(jqTruthy(r)), nil) {
+					return
+				}
+			}
+		}
+	}
+}
+func (n jqBinary) eval(env *jqEnv, v JsonValue) iter.Seq2[JsonValue, error] {
+	return func(yield func(JsonValue, error) bool) {
+		for r, err := range n.right.eval(env, v) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for l, err := range n.left.eval(env, v) {
+				if err != nil {
+					yield(nil, err)
+					return
+				}
+				result, err := jqOperate(env, n.op, l, r)
+				if err != nil {
+					yield(nil, err)
+					return
+				}
+				if !yield(result, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+func jqOperate(env *jqEnv, op string, l, r JsonValue) (JsonValue, error) {
+	switch op {
+	case "+":
+		return jqAdd(l, r)
+	case "-":
+		return jqSubtract(l, r)
+	case "*":
+		return jqMultiply(env, l, r)
+	case "/":
+		return jqDivide(l, r)
+	case "%":
+		return jqModulo(l, r)
+	case "==":
+		return jqBool(Compare(l, r) == 0), nil
+	case "!=":
+		return jqBool(Compare(l, r) != 0), nil
+	case "<":
+		return jqBool(Compare(l, r) < 0), nil
+	case "<=":
+		return jqBool(Compare(l, r) <= 0), nil
+	case ">":
+		return jqBool(Compare(l, r) > 0), nil
+	default:
+		return jqBool(Compare(l, r) >= 0), nil
+	}
+}
+func jqAdd(l, r JsonValue) (JsonValue, error) {
+	if l.IsNull() {
+		return r, nil
+	} else if r.IsNull() {
+		return l, nil
+	}
+	switch lt := l.(type) {
+	case *Number:
+		if rt, ok := r.(*Number); ok {
+			return jqNumber(float64(*lt + *rt)), nil
+		}
+	case *String:
+		if rt, ok := r.(*String); ok {
+			return jqString(string(*lt + *rt)), nil
+		}
+	case *Array:
+		if rt, ok := r.(*Array); ok {
+			added := slices.Concat(*lt, *rt)
+			return &added, nil
+		}
+	case *Object:
+		if rt, ok := r.(*Object); ok {
+			added := maps.Clone(*lt)
+			maps.Copy(added, *rt)
+			return &added, nil
+		}
+	}
+	return nil, jqErrorf("%s and %s cannot be added", jqDescribe(l), jqDescribe(r))
+}
+func jqSubtract(l, r JsonValue) (JsonValue, error) {
+	switch lt := l.(type) {
+	case *Number:
+		if rt, ok := r.(*Number); ok {
+			return jqNumber(float64(*lt - *rt)), nil
+		}
+	case *Array:
+		if rt, ok := r.(*Array); ok {
+			subtracted := make(Array, 0, len(*lt))
+			for _, elem := range *lt {
+				if !slices.ContainsFunc(*rt, func(v JsonValue) bool { return Compare(elem, v) == 0 }) {
+					subtracted = append(subtracted, elem)
+				}
+			}
+			return &subtracted, nil
+		}
+	}
+	return nil, jqErrorf("%s and %s cannot be subtracted", jqDescribe(l), jqDescribe(r))
+}
+func jqMultiply(env *jqEnv, l, r JsonValue) (JsonValue, error) {
+	switch lt := l.(type) {
+	case *Number:
+		switch rt := r.(type) {
+		case *Number:
+			return jqNumber(float64(*lt * *rt)), nil
+		case *String:
+			return jqRepeat(env, string(*rt), float64(*lt))
+		}
+	case *String:
+		if rt, ok := r.(*Number); ok {
+			return jqRepeat(env, string(*lt), float64(*rt))
+		}
+	case *Object:
+		if rt, ok := r.(*Object); ok {
+			return jqDeepMerge(*lt, *rt), nil
+		}
+	}
+	return nil, jqErrorf("%s and %s cannot be multiplied", jqDescribe(l), jqDescribe(r))
+}
+
+// jqRepeatLimit is the maximum bytes of the string built by repetition such as `"ab" * 3`.
+const jqRepeatLimit = 1 << 24
+
+// jqRepeat repeats the string n times, and charges the bytes of the result to the budget of the run.
+func jqRepeat(env *jqEnv, s string, n float64) (JsonValue, error) {
+	if math.IsNaN(n) || math.IsInf(n, 0) {
+		return nil, jqErrorf("%s cannot be repeated %v times", jqDescribe(jqString(s)), n)
+	} else if n <= 0 {
+		return jqNull(), nil
+	}
+	count := math.Ceil(n)
+	if count > float64(jqRepeatLimit/max(len(s), 1)) {
+		return nil, jqErrorf("%s repeated %v times exceeds the limit of %d bytes", jqDescribe(jqString(s)), count, jqRepeatLimit)
+	}
+	if err := env.charge(len(s) * int(count)); err != nil {
+		return nil, err
+	}
+	return jqString(strings.Repeat(s, int(count))), nil
+}
+func jqDeepMerge(l, r Object) JsonValue {
+	merged := maps.Clone(l)
+	for k, rv := range r {
+		lo, lok := merged[k].(*Object)
+		ro, rok := rv.(*Object)
+		if lok && rok {
+			merged[k] = jqDeepMerge(*lo, *ro)
+		} else {
+			merged[k] = rv
+		}
+	}
+	return &merged
+}
+func jqDivide(l, r JsonValue) (JsonValue, error) {
+	switch lt := l.(type) {
+	case *Number:
+		if rt, ok := r.(*Number); ok {
+			if *rt == 0 {
+				return nil, jqErrorf("%s and %s cannot be divided because the divisor is zero", jqDescribe(l), jqDescribe(r))
+			}
+			return jqNumber(float64(*lt / *rt)), nil
+		}
+	case *String:
+		if rt, ok := r.(*String); ok {
+			return jqSplit(string(*lt), string(*rt)), nil
+		}
+	}
+	return nil, jqErrorf("%s and %s cannot be divided", jqDescribe(l), jqDescribe(r))
+}
+func jqSplit(s, sep string) JsonValue {
+	split := make(Array, 0)
+	if s != "" {
+		for _, part := range strings.Split(s, sep) {
+			split = append(split, jqString(part))
+		}
+	}
+	return &split
+}
+func jqModulo(l, r JsonValue) (JsonValue, error) {
+	lt, lok := l.(*Number)
+	rt, rok := r.(*Number)
+	if !lok || !rok {
+		return nil, jqErrorf("%s and %s cannot be divided", jqDescribe(l), jqDescribe(r))
+	}
+	if int(*rt) == 0 {
+		return nil, jqErrorf("%s and %s cannot be divided because the divisor is zero", jqDescribe(l), jqDescribe(r))
+	}
+	return jqNumber(float64(int(*lt) % int(*rt))), nil
+}
+func (n jqArray) eval(env *jqEnv, v JsonValue) iter.Seq2[JsonValue, error] {
+	if n.body == nil {
+		return jqOne(&Array{})
+	}
+	outputs, err := jqCollect(n.body, env, v)
+	if err != nil {
+		return jqFail(err)
+	}
+	array := Array(outputs)
+	return jqOne(&array)
+}
+func (n jqObject) eval(env *jqEnv, v JsonValue) iter.Seq2[JsonValue, error] {
+	return func(yield func(JsonValue, error) bool) {
+		var rec func(i int, acc Object) bool
+		rec = func(i int, acc Object) bool {
+			if i == len(n) {
+				return yield(&acc, nil)
+			}
+			for key, err := range n[i].key.eval(env, v) {
+				if err != nil {
+					yield(nil, err)
+					return false
+				}
+				k, ok := key.(*String)
+				if !ok {
+					yield(nil, jqErrorf("Object keys must be strings"))
+					return false
+				}
+				for value, err := range n[i].value.eval(env, v) {
+					if err != nil {
+						yield(nil, err)
+						return false
+					}
+					next := maps.Clone(acc)
+					next[string(*k)] = value
+					if !rec(i+1, next) {
+						return false
+					}
+				}
+			}
+			return true
+		}
+		rec(0, Object{})
+	}
+}
+func (n jqInterpolation) eval(env *jqEnv, v JsonValue) iter.Seq2[JsonValue, error] {
+	return func(yield func(JsonValue, error) bool) {
+		var rec func(i int, acc string) bool
+		rec = func(i int, acc string) bool {
+			if i == len(n) {
+				return yield(jqString(acc), nil)
+			}
+			if n[i].expr == nil {
+				return rec(i+1, acc+n[i].literal)
+			}
+			for output, err := range n[i].expr.eval(env, v) {
+				if err != nil {
+					yield(nil, err)
+					return false
+				}
+				s, _ := jqApplyFormat("text", output)
+				if !rec(i+1, acc+s) {
+					return false
+				}
+			}
+			return true
+		}
+		rec(0, "")
+	}
+}
+func (n jqIf) eval(env *jqEnv, v JsonValue) iter.Seq2[JsonValue, error] {
+	return func(yield func(JsonValue, error) bool) {
+		for cond, err := range n.cond.eval(env, v) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			branch := n.els
+			if jqTruthy(cond) {
+				branch = n.then
+			} else if branch == nil {
+				branch = jqIdentity{}
+			}
+			for output, err := range branch.eval(env, v) {
+				if !yield(output, err) || err != nil {
+					return
+				}
+			}
+		}
+	}
+}
+func (n jqBind) eval(env *jqEnv, v JsonValue) iter.Seq2[JsonValue, error] {
+	return func(yield func(JsonValue, error) bool) {
+		for source, err := range n.source.eval(env, v) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for output, err := range n.body.eval(env.bind(n.name, source), v) {
+				if !yield(output, err) || err != nil {
+					return
+				}
+			}
+		}
+	}
+}
+func (n jqReduce) eval(env *jqEnv, v JsonValue) iter.Seq2[JsonValue, error] {
+	return func(yield func(JsonValue, error) bool) {
+		for acc, err := range n.init.eval(env, v) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for source, err := range n.source.eval(env, v) {
+				if err == nil {
+					err = env.step()
+				}
+				if err != nil {
+					yield(nil, err)
+					return
+				}
+				updated := jqNull()
+				for output, err := range n.update.eval(env.bind(n.name, source), acc) {
+					if err != nil {
+						yield(nil, err)
+						return
+					}
+					updated = output
+				}
+				acc = updated
+			}
+			if !yield(acc, nil) {
+				return
+			}
+		}
+	}
+}
+func (n jqForeach) eval(env *jqEnv, v JsonValue) iter.Seq2[JsonValue, error] {
+	return func(yield func(JsonValue, error) bool) {
+		for acc, err := range n.init.eval(env, v) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for source, err := range n.source.eval(env, v) {
+				if err == nil {
+					err = env.step()
+				}
+				if err != nil {
+					yield(nil, err)
+					return
+				}
+				local := env.bind(n.name, source)
+				for output, err := range n.update.eval(local, acc) {
+					if err != nil {
+						yield(nil, err)
+						return
+					}
+					acc = output
+					extract := jqNode(jqIdentity{})
+					if n.extract != nil {
+						extract = n.extract
+					}
+					for extracted, err := range extract.eval(local, acc) {
+						if !yield(extracted, err) || err != nil {
+							return
+						}
+					}
+				}
+			}
+		}
+	}
+}
+func (n jqCall) eval(env *jqEnv, v JsonValue) iter.Seq2[JsonValue, error] {
+	if err := env.step(); err != nil {
+		return jqFail(err)
+	}
+	return n.function(env, v, n.args)
+}
+
+func (l *jqLexer) errorf(format string, args ...any) error {
+	return ErrJq{Filter: l.filter, Offset: l.pos, Reason: fmt.Sprintf(format, args...)}
+}
+
+// tokenize tokenizes the filter, nested means inside of string interpolation, which ends at the unmatched ')'.
+func (l *jqLexer) tokenize(nested bool) ([]jqToken, error) {
+	tokens, depth := make([]jqToken, 0), 0
+	for {
+		for l.pos < len(l.filter) && (strings.IndexByte(" \t\n\r", l.filter[l.pos]) >= 0 || l.filter[l.pos] == '#') {
+			if l.filter[l.pos] == '#' {
+				for l.pos < len(l.filter) && l.filter[l.pos] != '\n' {
+					l.pos++
+				}
+			} else {
+				l.pos++
+			}
+		}
+		if l.pos >= len(l.filter) {
+			if nested {
+				return nil, l.errorf("unterminated string interpolation")
+			}
+			return append(tokens, jqToken{kind: "eof", pos: l.pos}), nil
+		}
+
+		start, c := l.pos, l.filter[l.pos]
+		switch {
+		case c == '"':
+			parts, err := l.lexString()
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, jqToken{kind: "string", parts: parts, pos: start})
+		case c == '.' && strings.HasPrefix(l.filter[l.pos:], ".."):
+			l.pos += 2
+			tokens = append(tokens, jqToken{kind: "..", value: "..", pos: start})
+		case c == '.' && l.pos+1 < len(l.filter) && jqIsIdentStart(l.filter[l.pos+1]):
+			l.pos++
+			tokens = append(tokens, jqToken{kind: "field", value: l.lexIdent(), pos: start})
+		case (c == '$' || c == '@') && l.pos+1 < len(l.filter) && jqIsIdentStart(l.filter[l.pos+1]):
+			l.pos++
+			kind := map[byte]string{'$': "var", '@': "format"}[c]
+			tokens = append(tokens, jqToken{kind: kind, value: l.lexIdent(), pos: start})
+		case jqIsIdentStart(c):
+			tokens = append(tokens, jqToken{kind: "ident", value: l.lexIdent(), pos: start})
+		case jpIsDigit(c):
+			for l.pos < len(l.filter) && (jpIsDigit(l.filter[l.pos]) || l.filter[l.pos] == '.') {
+				l.pos++
+			}
+			if l.pos < len(l.filter) && (l.filter[l.pos] == 'e' || l.filter[l.pos] == 'E') {
+				l.pos++
+				if l.pos < len(l.filter) && (l.filter[l.pos] == '+' || l.filter[l.pos] == '-') {
+					l.pos++
+				}
+				for l.pos < len(l.filter) && jpIsDigit(l.filter[l.pos]) {
+					l.pos++
+				}
+			}
+			if _, err := strconv.ParseFloat(l.filter[start:l.pos], 64); err != nil {
+				return nil, l.errorf("invalid number %s", l.filter[start:l.pos])
+			}
+			tokens = append(tokens, jqToken{kind: "number", value: l.filter[start:l.pos], pos: start})
+		case c == ')' && nested && depth == 0:
+			l.pos++
+			return append(tokens, jqToken{kind: "eof", pos: start}), nil
+		default:
+			op := ""
+			for _, candidate := range []string{"!=", "==", "<=", ">=", "//", "|", ",", ".", "[", "]", "{", "}", "(", ")", ":", ";", "?", "<", ">", "+", "-", "*", "/", "%"} {
+				if strings.HasPrefix(l.filter[l.pos:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" && c == '=' || strings.Contains("|+-*/%//", op) && strings.HasPrefix(l.filter[l.pos+len(op):], "=") {
+				return nil, l.errorf("assignment is not supported")
+			} else if op == "" {
+				return nil, l.errorf("unexpected character %q", c)
+			}
+			switch op {
+			case "(":
+				depth++
+			case ")":
+				depth--
+			}
+			l.pos += len(op)
+			tokens = append(tokens, jqToken{kind: op, value: op, pos: start})
+		}
+	}
+}
+func (l *jqLexer) lexIdent() string {
+	start := l.pos
+	for l.pos < len(l.filter) && (jqIsIdentStart(l.filter[l.pos]) || jpIsDigit(l.filter[l.pos])) {
+		l.pos++
+	}
+	return l.filter[start:l.pos]
+}
+func jqIsIdentStart(c byte) bool {
+	return c == '_' || jpIsAlpha(c)
+}
+
+// lexString lexes the string literal, which may contain string interpolation `\(...)`.
+func (l *jqLexer) lexString() ([]any, error) {
+	parts, literal := make([]any, 0), strings.Builder{}
+	for l.pos++; l.pos < len(l.filter); {
+		switch c := l.filter[l.pos]; c {
+		case '"':
+			l.pos++
+			return append(parts, literal.String()), nil
+		case '\\':
+			if l.pos+1 >= len(l.filter) {
+				return nil, l.errorf("unterminated string")
+			}
+			switch e := l.filter[l.pos+1]; e {
+			case '(':
+				l.pos += 2
+				tokens, err := l.tokenize(true)
+				if err != nil {
+					return nil, err
+				}
+				parts = append(parts, literal.String(), tokens)
+				literal.Reset()
+			case 'u':
+				r, width, err := l.lexUnicode()
+				if err != nil {
+					return nil, err
+				}
+				literal.WriteRune(r)
+				l.pos += width
+			default:
+				unescaped, ok := map[byte]byte{'"': '"', '\\': '\\', '/': '/', 'b': '\b', 'f': '\f', 'n': '\n', 'r': '\r', 't': '\t'}[e]
+				if !ok {
+					return nil, l.errorf("invalid escape \\%c", e)
+				}
+				literal.WriteByte(unescaped)
+				l.pos += 2
+			}
+		default:
+			literal.WriteByte(c)
+			l.pos++
+		}
+	}
+	return nil, l.errorf("unterminated string")
+}
+
+// lexUnicode lexes the \uXXXX escape, which may be followed by the low surrogate.
+func (l *jqLexer) lexUnicode() (rune, int, error) {
+	hex := func(at int) (rune, bool) {
+		if at+6 > len(l.filter) || l.filter[at:at+2] != `\u` {
+			return 0, false
+		}
+		code, err := strconv.ParseUint(l.filter[at+2:at+6], 16, 32)
+		return rune(code), err == nil
+	}
+	r, ok := hex(l.pos)
+	if !ok {
+		return 0, 0, l.errorf("invalid unicode escape")
+	}
+	if utf16.IsSurrogate(r) {
+		if low, ok := hex(l.pos + 6); ok {
+			return utf16.DecodeRune(r, low), 12, nil
+		}
+	}
+	return r, 6, nil
+}
+
+func (p *jqParser) errorf(format string, args ...any) error {
+	return ErrJq{Filter: p.filter, Offset: p.current().pos, Reason: fmt.Sprintf(format, args...)}
+}
+func (p *jqParser) current() jqToken {
+	return p.tokens[p.index]
+}
+func (p *jqParser) advance() jqToken {
+	t := p.tokens[p.index]
+	if p.index < len(p.tokens)-1 {
+		p.index++
+	}
+	return t
+}
+func (p *jqParser) is(kind string) bool {
+	return p.current().kind == kind
+}
+func (p *jqParser) isKeyword(keyword string) bool {
+	return p.current().kind == "ident" && p.current().value == keyword
+}
+func (p *jqParser) expect(kind string) error {
+	if !p.is(kind) && !p.isKeyword(kind) {
+		return p.errorf("expected %s, got %s", kind, p.current().value)
+	}
+	p.advance()
+	return nil
+}
+func (p *jqParser) expectVar() (string, error) {
+	if !p.is("var") {
+		return "", p.errorf("expected variable, got %s", p.current().value)
+	}
+	return p.advance().value, nil
+}
+
+// parsePipe parses the lowest precedence expression, noComma is for the value of object construction.
+func (p *jqParser) parsePipe(noComma bool) (jqNode, error) {
+	if !p.is("-") && !p.isKeyword("try") {
+		term, err := p.parsePostfix()
+		if err != nil {
+			return nil, err
+		}
+		if p.isKeyword("as") {
+			p.advance()
+			name, err := p.expectVar()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("|"); err != nil {
+				return nil, err
+			}
+			p.vars = append(p.vars, name)
+			body, err := p.parsePipe(noComma)
+			p.vars = p.vars[:len(p.vars)-1]
+			return jqBind{source: term, name: name, body: body}, err
+		}
+		p.pending = term
+	}
+
+	left, err := p.parseAlt()
+	for err == nil && !noComma && p.is(",") {
+		p.advance()
+		var right jqNode
+		right, err = p.parseAlt()
+		left = jqComma{left: left, right: right}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if p.is("|") {
+		p.advance()
+		right, err := p.parsePipe(noComma)
+		return jqPipe{left: left, right: right}, err
+	}
+	return left, nil
+}
+func (p *jqParser) parseAlt() (jqNode, error) {
+	left, err := p.parseOr()
+	if err != nil || !p.is("//") {
+		return left, err
+	}
+	p.advance()
+	right, err := p.parseAlt()
+	return jqAlt{left: left, right: right}, err
+}
+func (p *jqParser) parseOr() (jqNode, error) {
+	left, err := p.parseAnd()
+	for err == nil && p.isKeyword("or") {
+		p.advance()
+		var right jqNode
+		right, err = p.parseAnd()
+		left = jqOr{left: left, right: right}
+	}
+	return left, err
+}
+func (p *jqParser) parseAnd() (jqNode, error) {
+	left, err := p.parseCompare()
+	for err == nil && p.isKeyword("and") {
+		p.advance()
+		var right jqNode
+		right, err = p.parseCompare()
+		left = jqAnd{left: left, right: right}
+	}
+	return left, err
+}
+func (p *jqParser) parseCompare() (jqNode, error) {
+	isCompare := func() bool { return slices.Contains([]string{"==", "!=", "<", "<=", ">", ">="}, p.current().kind) }
+	left, err := p.parseBinary(1)
+	if err != nil || !isCompare() {
+		return left, err
+	}
+	op := p.advance().kind
+	right, err := p.parseBinary(1)
+	if err == nil && isCompare() {
+		return nil, p.errorf("comparison operators are non-associative")
+	}
+	return jqBinary{op: op, left: left, right: right}, err
+}
+
+// parseBinary parses additive (level 1) and multiplicative (level 2) operators.
+func (p *jqParser) parseBinary(level int) (jqNode, error) {
+	ops := map[int][]string{1: {"+", "-"}, 2: {"*", "/", "%"}}[level]
+	operand := func() (jqNode, error) {
+		if level == 1 {
+			return p.parseBinary(2)
+		}
+		return p.parseUnary()
+	}
+	left, err := operand()
+	for err == nil && slices.Contains(ops, p.current().kind) {
+		op := p.advance().kind
+		var right jqNode
+		right, err = operand()
+		left = jqBinary{op: op, left: left, right: right}
+	}
+	return left, err
+}
+func (p *jqParser) parseUnary() (jqNode, error) {
+	if p.pending != nil {
+		term := p.pending
+		p.pending = nil
+		return term, nil
+	}
+	switch {
+	case p.is("-"):
+		p.advance()
+		body, err := p.parseUnary()
+		return jqNeg{body: body}, err
+	case p.isKeyword("try"):
+		p.advance()
+		body, err := p.parsePostfix()
+		if err != nil {
+			return nil, err
+		}
+		var catch jqNode
+		if p.isKeyword("catch") {
+			p.advance()
+			if catch, err = p.parsePostfix(); err != nil {
+				return nil, err
+			}
+		}
+		return jqTry{body: body, catch: catch}, nil
+	default:
+		return p.parsePostfix()
+	}
+}
+func (p *jqParser) parsePostfix() (jqNode, error) {
+	term, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.is("field"):
+			term = jqIndex{target: term, index: jqLiteral{literal: jqString(p.advance().value)}}
+		case p.is(".") && p.tokens[p.index+1].kind == "string":
+			p.advance()
+			key, err := p.parseString(p.advance())
+			if err != nil {
+				return nil, err
+			}
+			term = jqIndex{target: term, index: key}
+		case p.is(".") && p.tokens[p.index+1].kind == "[":
+			p.advance()
+		case p.is("["):
+			if term, err = p.parseBracket(term); err != nil {
+				return nil, err
+			}
+		case p.is("?"):
+			p.advance()
+			term = jqOptional{body: term}
+		default:
+			return term, nil
+		}
+	}
+}
+func (p *jqParser) parseBracket(target jqNode) (jqNode, error) {
+	p.advance()
+	if p.is("]") {
+		p.advance()
+		return jqIterate{target: target}, nil
+	}
+	var from, to jqNode
+	var err error
+	if !p.is(":") {
+		if from, err = p.parsePipe(false); err != nil {
+			return nil, err
+		}
+		if p.is("]") {
+			p.advance()
+			return jqIndex{target: target, index: from}, nil
+		}
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	if !p.is("]") {
+		if to, err = p.parsePipe(false); err != nil {
+			return nil, err
+		}
+	}
+	return jqSlice{target: target, from: from, to: to}, p.expect("]")
+}
+func (p *jqParser) parseTerm() (jqNode, error) {
+	token := p.current()
+	switch token.kind {
+	case ".":
+		p.advance()
+		if p.is("string") {
+			key, err := p.parseString(p.advance())
+			return jqIndex{target: jqIdentity{}, index: key}, err
+		}
+		return jqIdentity{}, nil
+	case "..":
+		p.advance()
+		return jqRecurse{}, nil
+	case "field":
+		p.advance()
+		return jqIndex{target: jqIdentity{}, index: jqLiteral{literal: jqString(token.value)}}, nil
+	case "number":
+		p.advance()
+		f, _ := strconv.ParseFloat(token.value, 64)
+		return jqLiteral{literal: jqNumber(f)}, nil
+	case "string":
+		p.advance()
+		return p.parseString(token)
+	case "format":
+		if _, err := jqApplyFormat(token.value, jqNull()); errors.Is(err, errJqUnknownFormat) {
+			return nil, p.errorf("%s is not a valid format", token.value)
+		}
+		p.advance()
+		if p.is("string") {
+			return nil, p.errorf("format string is not supported")
+		}
+		return jqFormat(token.value), nil
+	case "var":
+		p.advance()
+		if !slices.Contains(p.vars, token.value) {
+			return nil, ErrJq{Filter: p.filter, Offset: token.pos, Reason: fmt.Sprintf("$%s is not defined", token.value)}
+		}
+		return jqVar(token.value), nil
+	case "(":
+		p.advance()
+		body, err := p.parsePipe(false)
+		if err != nil {
+			return nil, err
+		}
+		return body, p.expect(")")
+	case "[":
+		p.advance()
+		if p.is("]") {
+			p.advance()
+			return jqArray{}, nil
+		}
+		body, err := p.parsePipe(false)
+		if err != nil {
+			return nil, err
+		}
+		return jqArray{body: body}, p.expect("]")
+	case "{":
+		p.advance()
+		return p.parseObject()
+	case "ident":
+		switch token.value {
+		case "null", "true", "false":
+			p.advance()
+			literal := map[string]JsonValue{"null": jqNull(), "true": jqBool(true), "false": jqBool(false)}[token.value]
+			return jqLiteral{literal: literal}, nil
+		case "if":
+			p.advance()
+			return p.parseIf()
+		case "reduce", "foreach":
+			p.advance()
+			return p.parseReduce(token.value == "foreach")
+		case "def", "label", "import", "include":
+			return nil, p.errorf("%s is not supported", token.value)
+		}
+		if slices.Contains(jqKeywords, token.value) {
+			return nil, p.errorf("unexpected keyword %s", token.value)
+		}
+		p.advance()
+		return p.parseCall(token)
+	default:
+		return nil, p.errorf("unexpected token %s", token.value)
+	}
+}
+func (p *jqParser) parseString(token jqToken) (jqNode, error) {
+	if len(token.parts) == 1 {
+		return jqLiteral{literal: jqString(token.parts[0].(string))}, nil
+	}
+	str := make(jqInterpolation, 0, len(token.parts))
+	for _, part := range token.parts {
+		switch t := part.(type) {
+		case string:
+			str = append(str, jqPart{literal: t})
+		case []jqToken:
+			sub := &jqParser{filter: p.filter, tokens: t, vars: p.vars}
+			expr, err := sub.parsePipe(false)
+			if err != nil {
+				return nil, err
+			}
+			if !sub.is("eof") {
+				return nil, sub.errorf("unexpected token %s", sub.current().value)
+			}
+			str = append(str, jqPart{expr: expr})
+		}
+	}
+	return str, nil
+}
+func (p *jqParser) parseObject() (jqNode, error) {
+	object := make(jqObject, 0)
+	for !p.is("}") {
+		token := p.advance()
+		var key, value jqNode
+		var err error
+		switch token.kind {
+		case "var":
+			if !slices.Contains(p.vars, token.value) {
+				return nil, ErrJq{Filter: p.filter, Offset: token.pos, Reason: fmt.Sprintf("$%s is not defined", token.value)}
+			}
+			key, value = jqLiteral{literal: jqString(token.value)}, jqVar(token.value)
+		case "ident":
+			key = jqLiteral{literal: jqString(token.value)}
+		case "string":
+			if key, err = p.parseString(token); err != nil {
+				return nil, err
+			}
+		case "(":
+			if key, err = p.parsePipe(false); err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			if !p.is(":") {
+				return nil, p.errorf("expected :, got %s", p.current().value)
+			}
+		default:
+			return nil, ErrJq{Filter: p.filter, Offset: token.pos, Reason: fmt.Sprintf("unexpected object key %s", token.value)}
+		}
+		if value == nil {
+			if p.is(":") {
+				p.advance()
+				if value, err = p.parsePipe(true); err != nil {
+					return nil, err
+				}
+			} else {
+				value = jqIndex{target: jqIdentity{}, index: key}
+			}
+		}
+		object = append(object, jqEntry{key: key, value: value})
+		if !p.is("}") {
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+	}
+	p.advance()
+	return object, nil
+}
+func (p *jqParser) parseIf() (jqNode, error) {
+	cond, err := p.parsePipe(false)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect("then"); err != nil {
+		return nil, err
+	}
+	then, err := p.parsePipe(false)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case p.isKeyword("elif"):
+		p.advance()
+		els, err := p.parseIf()
+		return jqIf{cond: cond, then: then, els: els}, err
+	case p.isKeyword("else"):
+		p.advance()
+		els, err := p.parsePipe(false)
+		if err != nil {
+			return nil, err
+		}
+		return jqIf{cond: cond, then: then, els: els}, p.expect("end")
+	default:
+		return jqIf{cond: cond, then: then}, p.expect("end")
+	}
+}
+func (p *jqParser) parseReduce(foreach bool) (jqNode, error) {
+	source, err := p.parsePostfix()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect("as"); err != nil {
+		return nil, err
+	}
+	name, err := p.expectVar()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	init, err := p.parsePipe(false)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(";"); err != nil {
+		return nil, err
+	}
+	p.vars = append(p.vars, name)
+	defer func() { p.vars = p.vars[:len(p.vars)-1] }()
+	update, err := p.parsePipe(false)
+	if err != nil {
+		return nil, err
+	}
+	if !foreach {
+		return jqReduce{source: source, name: name, init: init, update: update}, p.expect(")")
+	}
+	var extract jqNode
+	if p.is(";") {
+		p.advance()
+		if extract, err = p.parsePipe(false); err != nil {
+			return nil, err
+		}
+	}
+	return jqForeach{source: source, name: name, init: init, update: update, extract: extract}, p.expect(")")
+}
+func (p *jqParser) parseCall(name jqToken) (jqNode, error) {
+	args := make([]jqNode, 0)
+	if p.is("(") {
+		p.advance()
+		for {
+			arg, err := p.parsePipe(false)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if p.is(")") {
+				p.advance()
+				break
+			}
+			if err := p.expect(";"); err != nil {
+				return nil, err
+			}
+		}
+	}
+	function, ok := jqFunctions[fmt.Sprintf("%s/%d", name.value, len(args))]
+	if !ok {
+		return nil, ErrJq{Filter: p.filter, Offset: name.pos, Reason: fmt.Sprintf("%s/%d is not defined", name.value, len(args))}
+	}
+	return jqCall{name: name.value, function: function, args: args}, nil
+}
+
+var (
+	errJqUnknownFormat = errors.New("unknown format")
+	jqFunctions        = map[string]jqFunction{
+		"empty/0": func(*jqEnv, JsonValue, []jqNode) iter.Seq2[JsonValue, error] {
+			return func(func(JsonValue, error) bool) {}
+		},
+		"error/0": jqValueFunction(func(v JsonValue, _ []JsonValue) (JsonValue, error) {
+			return nil, ErrJqRuntime{Value: v}
+		}),
+		"error/1": jqValueFunction(func(_ JsonValue, args []JsonValue) (JsonValue, error) {
+			return nil, ErrJqRuntime{Value: args[0]}
+		}),
+		"not/0": jqValueFunction(func(v JsonValue, _ []JsonValue) (JsonValue, error) {
+			return jqBool(!jqTruthy(v)), nil
+		}),
+		"type/0": jqValueFunction(func(v JsonValue, _ []JsonValue) (JsonValue, error) {
+			return jqString(jqType(v)), nil
+		}),
+		"length/0":         jqValueFunction(jqLength),
+		"utf8bytelength/0": jqStringFunction("utf8bytelength", func(s string) JsonValue { return jqNumber(float64(len(s))) }),
+		"keys/0":           jqValueFunction(jqKeys),
+		"keys_unsorted/0":  jqValueFunction(jqKeys),
+		"values/0":         jqSelectFunction(func(v JsonValue) bool { return !v.IsNull() }),
+		"has/1":            jqValueFunction(jqHas),
+		"contains/1": jqValueFunction(func(v JsonValue, args []JsonValue) (JsonValue, error) {
+			contains, err := jqContains(v, args[0])
+			return jqBool(contains), err
+		}),
+		"inside/1": jqValueFunction(func(v JsonValue, args []JsonValue) (JsonValue, error) {
+			contains, err := jqContains(args[0], v)
+			return jqBool(contains), err
+		}),
+		"add/0": jqValueFunction(func(v JsonValue, _ []JsonValue) (JsonValue, error) {
+			if v.IsNull() {
+				return v, nil
+			}
+			elements, err := jqElements(v)
+			if err != nil {
+				return nil, err
+			}
+			sum := jqNull()
+			for _, elem := range elements {
+				if sum, err = jqAdd(sum, elem); err != nil {
+					return nil, err
+				}
+			}
+			return sum, nil
+		}),
+		"any/0":   jqValueFunction(func(v JsonValue, _ []JsonValue) (JsonValue, error) { return jqQuantify(v, true) }),
+		"all/0":   jqValueFunction(func(v JsonValue, _ []JsonValue) (JsonValue, error) { return jqQuantify(v, false) }),
+		"any/1":   jqQuantifyFunction(true),
+		"all/1":   jqQuantifyFunction(false),
+		"range/1": jqRange,
+		"range/2": jqRange,
+		"range/3": jqRange,
+		"floor/0": jqMathFunction("floor", math.Floor),
+		"ceil/0":  jqMathFunction("ceil", math.Ceil),
+		"round/0": jqMathFunction("round", math.Round),
+		"sqrt/0":  jqMathFunction("sqrt", math.Sqrt),
+		"fabs/0":  jqMathFunction("fabs", math.Abs),
+		"abs/0":   jqMathFunction("abs", math.Abs),
+		"tostring/0": jqValueFunction(func(v JsonValue, _ []JsonValue) (JsonValue, error) {
+			s, err := jqApplyFormat("text", v)
+			return jqString(s), err
+		}),
+		"tojson/0": jqValueFunction(func(v JsonValue, _ []JsonValue) (JsonValue, error) {
+			return jqString(compactJson(v)), nil
+		}),
+		"fromjson/0": jqValueFunction(func(v JsonValue, _ []JsonValue) (JsonValue, error) {
+			s, ok := v.(*String)
+			if !ok {
+				return nil, jqErrorf("%s cannot be parsed as JSON", jqDescribe(v))
+			}
+			var root RootValue
+			if err := json.Unmarshal([]byte(*s), &root); err != nil {
+				return nil, jqErrorf("%s (while parsing '%s')", err, *s)
+			}
+			return root.JsonValue, nil
+		}),
+		"tonumber/0": jqValueFunction(func(v JsonValue, _ []JsonValue) (JsonValue, error) {
+			switch t := v.(type) {
+			case *Number:
+				return t, nil
+			case *String:
+				if f, err := strconv.ParseFloat(string(*t), 64); err == nil {
+					return jqNumber(f), nil
+				}
+				return nil, jqErrorf("Cannot parse '%s' as JSON", *t)
+			default:
+				return nil, jqErrorf("%s cannot be parsed as a number", jqDescribe(v))
+			}
+		}),
+		"ascii_downcase/0": jqStringFunction("ascii_downcase", func(s string) JsonValue { return jqString(jqMapASCII(s, 'A', 'Z', 'a'-'A')) }),
+		"ascii_upcase/0":   jqStringFunction("ascii_upcase", func(s string) JsonValue { return jqString(jqMapASCII(s, 'a', 'z', 'A'-'a')) }),
+		"trim/0":           jqStringFunction("trim", func(s string) JsonValue { return jqString(strings.TrimSpace(s)) }),
+		"ltrim/0":          jqStringFunction("ltrim", func(s string) JsonValue { return jqString(strings.TrimLeft(s, " \t\n\r\f\v")) }),
+		"rtrim/0":          jqStringFunction("rtrim", func(s string) JsonValue { return jqString(strings.TrimRight(s, " \t\n\r\f\v")) }),
+		"explode/0": jqStringFunction("explode", func(s string) JsonValue {
+			codepoints := make(Array, 0, len(s))
+			for _, r := range s {
+				codepoints = append(codepoints, jqNumber(float64(r)))
+			}
+			return &codepoints
+		}),
+		"implode/0": jqValueFunction(func(v JsonValue, _ []JsonValue) (JsonValue, error) {
+			a, ok := v.(*Array)
+			if !ok {
+				return nil, jqErrorf("implode input must be an array")
+			}
+			runes := make([]rune, 0, len(*a))
+			for _, elem := range *a {
+				n, ok := elem.(*Number)
+				if !ok {
+					return nil, jqErrorf("Unicode codepoint must be numeric")
+				}
+				runes = append(runes, rune(*n))
+			}
+			return jqString(string(runes)), nil
+		}),
+		"ltrimstr/1": jqValueFunction(func(v JsonValue, args []JsonValue) (JsonValue, error) {
+			s, sok := v.(*String)
+			prefix, pok := args[0].(*String)
+			if sok && pok && strings.HasPrefix(string(*s), string(*prefix)) {
+				return jqString(strings.TrimPrefix(string(*s), string(*prefix))), nil
+			}
+			return v, nil
+		}),
+		"rtrimstr/1": jqValueFunction(func(v JsonValue, args []JsonValue) (JsonValue, error) {
+			s, sok := v.(*String)
+			suffix, pok := args[0].(*String)
+			if sok && pok && strings.HasSuffix(string(*s), string(*suffix)) {
+				return jqString(strings.TrimSuffix(string(*s), string(*suffix))), nil
+			}
+			return v, nil
+		}),
+		"startswith/1": jqStringsFunction("startswith", func(s, prefix string) (JsonValue, error) {
+			return jqBool(strings.HasPrefix(s, prefix)), nil
+		}),
+		"endswith/1": jqStringsFunction("endswith", func(s, suffix string) (JsonValue, error) {
+			return jqBool(strings.HasSuffix(s, suffix)), nil
+		}),
+		"split/1": jqStringsFunction("split", func(s, sep string) (JsonValue, error) {
+			return jqSplit(s, sep), nil
+		}),
+		"split/2": jqValueFunction(jqSplitRegexp),
+		"splits/1": func(env *jqEnv, v JsonValue, args []jqNode) iter.Seq2[JsonValue, error] {
+			return jqIterate{target: jqCall{function: jqValueFunction(jqSplitRegexp), args: args}}.eval(env, v)
+		},
+		"splits/2": func(env *jqEnv, v JsonValue, args []jqNode) iter.Seq2[JsonValue, error] {
+			return jqIterate{target: jqCall{function: jqValueFunction(jqSplitRegexp), args: args}}.eval(env, v)
+		},
+		"join/1": jqValueFunction(jqJoin),
+		"test/1": jqValueFunction(jqTest),
+		"test/2": jqValueFunction(jqTest),
+		"sub/2":  jqSubstitute(false),
+		"gsub/2": jqSubstitute(true),
+		"indices/1": jqValueFunction(func(v JsonValue, args []JsonValue) (JsonValue, error) {
+			return jqIndices(v, args[0])
+		}),
+		"index/1": jqValueFunction(func(v JsonValue, args []JsonValue) (JsonValue, error) {
+			return jqIndicesAt(v, args[0], 0)
+		}),
+		"rindex/1": jqValueFunction(func(v JsonValue, args []JsonValue) (JsonValue, error) {
+			return jqIndicesAt(v, args[0], -1)
+		}),
+		"map/1": func(env *jqEnv, v JsonValue, args []jqNode) iter.Seq2[JsonValue, error] {
+			return jqArray{body: jqPipe{left: jqIterate{target: jqIdentity{}}, right: args[0]}}.eval(env, v)
+		},
+		"select/1": func(env *jqEnv, v JsonValue, args []jqNode) iter.Seq2[JsonValue, error] {
+			return func(yield func(JsonValue, error) bool) {
+				for cond, err := range args[0].eval(env, v) {
+					if err != nil {
+						yield(nil, err)
+						return
+					}
+					if jqTruthy(cond) && !yield(v, nil) {
+						return
+					}
+				}
+			}
+		},
+		"map_values/1": func(env *jqEnv, v JsonValue, args []jqNode) iter.Seq2[JsonValue, error] {
+			mapped, err := jqMapValues(env, v, args[0])
+			if err != nil {
+				return jqFail(err)
+			}
+			return jqOne(mapped)
+		},
+		"to_entries/0":   jqValueFunction(jqToEntries),
+		"from_entries/0": jqValueFunction(jqFromEntries),
+		"with_entries/1": func(env *jqEnv, v JsonValue, args []jqNode) iter.Seq2[JsonValue, error] {
+			entries, err := jqToEntries(v, nil)
+			if err != nil {
+				return jqFail(err)
+			}
+			mapped, err := jqCollect(jqIterate{target: jqIdentity{}}, env, entries)
+			if err != nil {
+				return jqFail(err)
+			}
+			outputs := make(Array, 0, len(mapped))
+			for _, entry := range mapped {
+				results, err := jqCollect(args[0], env, entry)
+				if err != nil {
+					return jqFail(err)
+				}
+				outputs = append(outputs, results...)
+			}
+			object, err := jqFromEntries(&outputs, nil)
+			if err != nil {
+				return jqFail(err)
+			}
+			return jqOne(object)
+		},
+		"recurse/0": func(env *jqEnv, v JsonValue, _ []jqNode) iter.Seq2[JsonValue, error] {
+			return jqRecurse{}.eval(env, v)
+		},
+		"recurse/1": func(env *jqEnv, v JsonValue, args []jqNode) iter.Seq2[JsonValue, error] {
+			return func(yield func(JsonValue, error) bool) {
+				var rec func(v JsonValue) bool
+				rec = func(v JsonValue) bool {
+					if err := env.step(); err != nil {
+						yield(nil, err)
+						return false
+					}
+					if !yield(v, nil) {
+						return false
+					}
+					for child, err := range args[0].eval(env, v) {
+						if err != nil {
+							yield(nil, err)
+							return false
+						}
+						if !rec(child) {
+							return false
+						}
+					}
+					return true
+				}
+				rec(v)
+			}
+		},
+		"walk/1": func(env *jqEnv, v JsonValue, args []jqNode) iter.Seq2[JsonValue, error] {
+			return jqWalk(env, v, args[0])
+		},
+		"sort/0": jqValueFunction(func(v JsonValue, _ []JsonValue) (JsonValue, error) {
+			return jqSortBy(v, nil)
+		}),
+		"sort_by/1": jqByFunction("sort_by", func(a Array, keys []JsonValue) (JsonValue, error) {
+			return jqSortBy(&a, keys)
+		}),
+		"group_by/1": jqByFunction("group_by", func(a Array, keys []JsonValue) (JsonValue, error) {
+			return jqGroupBy(a, keys, false)
+		}),
+		"unique/0": jqValueFunction(func(v JsonValue, _ []JsonValue) (JsonValue, error) {
+			a, ok := v.(*Array)
+			if !ok {
+				return nil, jqErrorf("%s cannot be sorted, as it is not an array", jqDescribe(v))
+			}
+			return jqGroupBy(*a, *a, true)
+		}),
+		"unique_by/1": jqByFunction("unique_by", func(a Array, keys []JsonValue) (JsonValue, error) {
+			return jqGroupBy(a, keys, true)
+		}),
+		"min/0": jqValueFunction(func(v JsonValue, _ []JsonValue) (JsonValue, error) {
+			return jqExtremum("min", v, nil, -1)
+		}),
+		"max/0": jqValueFunction(func(v JsonValue, _ []JsonValue) (JsonValue, error) {
+			return jqExtremum("max", v, nil, 1)
+		}),
+		"min_by/1": jqByFunction("min_by", func(a Array, keys []JsonValue) (JsonValue, error) {
+			return jqExtremum("min_by", &a, keys, -1)
+		}),
+		"max_by/1": jqByFunction("max_by", func(a Array, keys []JsonValue) (JsonValue, error) {
+			return jqExtremum("max_by", &a, keys, 1)
+		}),
+		"reverse/0": jqValueFunction(func(v JsonValue, _ []JsonValue) (JsonValue, error) {
+			switch t := v.(type) {
+			case *Null:
+				return &Array{}, nil
+			case *String:
+				runes := []rune(string(*t))
+				slices.Reverse(runes)
+				return jqString(string(runes)), nil
+			case *Array:
+				reversed := slices.Clone(*t)
+				slices.Reverse(reversed)
+				return &reversed, nil
+			default:
+				return nil, jqErrorf("Cannot reverse %s", jqDescribe(v))
+			}
+		}),
+		"flatten/0": jqValueFunction(func(v JsonValue, _ []JsonValue) (JsonValue, error) {
+			return jqFlatten(v, math.MaxInt)
+		}),
+		"flatten/1": jqValueFunction(func(v JsonValue, args []JsonValue) (JsonValue, error) {
+			depth, ok := args[0].(*Number)
+			if !ok || *depth < 0 {
+				return nil, jqErrorf("flatten depth must not be negative")
+			}
+			return jqFlatten(v, int(*depth))
+		}),
+		"first/0": jqValueFunction(func(v JsonValue, _ []JsonValue) (JsonValue, error) {
+			return jqIndexValue(v, jqNumber(0))
+		}),
+		"last/0": jqValueFunction(func(v JsonValue, _ []JsonValue) (JsonValue, error) {
+			return jqIndexValue(v, jqNumber(-1))
+		}),
+		"first/1": func(env *jqEnv, v JsonValue, args []jqNode) iter.Seq2[JsonValue, error] {
+			return jqLimit(env, v, args[0], 1)
+		},
+		"last/1": func(env *jqEnv, v JsonValue, args []jqNode) iter.Seq2[JsonValue, error] {
+			return func(yield func(JsonValue, error) bool) {
+				var last JsonValue
+				for output, err := range args[0].eval(env, v) {
+					if err != nil {
+						yield(nil, err)
+						return
+					}
+					last = output
+				}
+				if last != nil {
+					yield(last, nil)
+				}
+			}
+		},
+		"limit/2": func(env *jqEnv, v JsonValue, args []jqNode) iter.Seq2[JsonValue, error] {
+			return func(yield func(JsonValue, error) bool) {
+				for n, err := range args[0].eval(env, v) {
+					if err != nil {
+						yield(nil, err)
+						return
+					}
+					limit, ok := n.(*Number)
+					if !ok {
+						yield(nil, jqErrorf("Invalid limit %s", jqDescribe(n)))
+						return
+					}
+					for output, err := range jqLimit(env, v, args[1], int(*limit)) {
+						if !yield(output, err) || err != nil {
+							return
+						}
+					}
+				}
+			}
+		},
+		"IN/1": func(env *jqEnv, v JsonValue, args []jqNode) iter.Seq2[JsonValue, error] {
+			return jqIn(env, v, jqIdentity{}, args[0])
+		},
+		"IN/2": func(env *jqEnv, v JsonValue, args []jqNode) iter.Seq2[JsonValue, error] {
+			return jqIn(env, v, args[0], args[1])
+		},
+		"path/1": func(env *jqEnv, v JsonValue, args []jqNode) iter.Seq2[JsonValue, error] {
+			return func(yield func(JsonValue, error) bool) {
+				for pv, err := range jqPaths(env, args[0], jqPathValue{path: Array{}, value: v}) {
+					if err != nil {
+						yield(nil, err)
+						return
+					}
+					if !yield(&pv.path, nil) {
+						return
+					}
+				}
+			}
+		},
+		"paths/0": func(env *jqEnv, v JsonValue, _ []jqNode) iter.Seq2[JsonValue, error] {
+			return jqLeafPaths(env, v, nil)
+		},
+		"paths/1": func(env *jqEnv, v JsonValue, args []jqNode) iter.Seq2[JsonValue, error] {
+			return jqLeafPaths(env, v, args[0])
+		},
+		"getpath/1": jqValueFunction(func(v JsonValue, args []JsonValue) (JsonValue, error) {
+			path, ok := args[0].(*Array)
+			if !ok {
+				return nil, jqErrorf("Path must be specified as an array")
+			}
+			for _, key := range *path {
+				if v.IsNull() {
+					return v, nil
+				}
+				var err error
+				if v, err = jqIndexValue(v, key); err != nil {
+					return nil, err
+				}
+			}
+			return v, nil
+		}),
+		"delpaths/1": jqValueFunction(func(v JsonValue, args []JsonValue) (JsonValue, error) {
+			paths, ok := args[0].(*Array)
+			if !ok {
+				return nil, jqErrorf("Paths must be specified as an array")
+			}
+			return jqDeletePaths(v, *paths)
+		}),
+		"del/1": func(env *jqEnv, v JsonValue, args []jqNode) iter.Seq2[JsonValue, error] {
+			paths := make(Array, 0)
+			for pv, err := range jqPaths(env, args[0], jqPathValue{path: Array{}, value: v}) {
+				if err != nil {
+					return jqFail(err)
+				}
+				paths = append(paths, &pv.path)
+			}
+			deleted, err := jqDeletePaths(v, paths)
+			if err != nil {
+				return jqFail(err)
+			}
+			return jqOne(deleted)
+		},
+		"arrays/0":    jqSelectFunction(func(v JsonValue) bool { return v.IsArray() }),
+		"objects/0":   jqSelectFunction(func(v JsonValue) bool { return v.IsObject() }),
+		"iterables/0": jqSelectFunction(func(v JsonValue) bool { return v.IsArray() || v.IsObject() }),
+		"scalars/0":   jqSelectFunction(func(v JsonValue) bool { return !v.IsArray() && !v.IsObject() }),
+		"strings/0":   jqSelectFunction(func(v JsonValue) bool { return v.IsString() }),
+		"numbers/0":   jqSelectFunction(func(v JsonValue) bool { return v.IsNumber() }),
+		"booleans/0":  jqSelectFunction(func(v JsonValue) bool { return v.IsBool() }),
+		"nulls/0":     jqSelectFunction(func(v JsonValue) bool { return v.IsNull() }),
+	}
+)
+
+// jqValueFunction defines the function whose arguments are evaluated as values, multiple outputs of arguments are cartesian product.
+func jqValueFunction(f func(v JsonValue, args []JsonValue) (JsonValue, error)) jqFunction {
+	return func(env *jqEnv, v JsonValue, args []jqNode) iter.Seq2[JsonValue, error] {
+		return func(yield func(JsonValue, error) bool) {
+			for values, err := range jqCartesian(env, v, args) {
+				if err != nil {
+					yield(nil, err)
+					return
+				}
+				result, err := f(v, values)
+				if err != nil {
+					yield(nil, err)
+					return
+				}
+				if !yield(result, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+func jqSelectFunction(f func(v JsonValue) bool) jqFunction {
+	return func(_ *jqEnv, v JsonValue, _ []jqNode) iter.Seq2[JsonValue, error] {
+		return func(yield func(JsonValue, error) bool) {
+			if f(v) {
+				yield(v, nil)
+			}
+		}
+	}
+}
+func jqStringFunction(name string, f func(s string) JsonValue) jqFunction {
+	return jqValueFunction(func(v JsonValue, _ []JsonValue) (JsonValue, error) {
+		s, ok := v.(*String)
+		if !ok {
+			return nil, jqErrorf("%s input must be a string", name)
+		}
+		return f(string(*s)), nil
+	})
+}
+func jqStringsFunction(name string, f func(s, arg string) (JsonValue, error)) jqFunction {
+	return jqValueFunction(func(v JsonValue, args []JsonValue) (JsonValue, error) {
+		s, sok := v.(*String)
+		arg, aok := args[0].(*String)
+		if !sok || !aok {
+			return nil, jqErrorf("%s() requires string inputs", name)
+		}
+		return f(string(*s), string(*arg))
+	})
+}
+func jqMathFunction(name string, f func(float64) float64) jqFunction {
+	return jqValueFunction(func(v JsonValue, _ []JsonValue) (JsonValue, error) {
+		n, ok := v.(*Number)
+		if !ok {
+			return nil, jqErrorf("%s number required", jqDescribe(v))
+		}
+		return jqNumber(f(float64(*n))), nil
+	})
+}
+
+// jqByFunction defines the function such as sort_by(f), whose key of each element is the array of outputs of f.
+func jqByFunction(name string, f func(a Array, keys []JsonValue) (JsonValue, error)) jqFunction {
+	return func(env *jqEnv, v JsonValue, args []jqNode) iter.Seq2[JsonValue, error] {
+		a, ok := v.(*Array)
+		if !ok {
+			return jqFail(jqErrorf("Cannot index %s with %s", jqType(v), name))
+		}
+		keys := make([]JsonValue, 0, len(*a))
+		for _, elem := range *a {
+			outputs, err := jqCollect(args[0], env, elem)
+			if err != nil {
+				return jqFail(err)
+			}
+			key := Array(outputs)
+			keys = append(keys, &key)
+		}
+		result, err := f(*a, keys)
+		if err != nil {
+			return jqFail(err)
+		}
+		return jqOne(result)
+	}
+}
+func jqQuantifyFunction(any bool) jqFunction {
+	return func(env *jqEnv, v JsonValue, args []jqNode) iter.Seq2[JsonValue, error] {
+		elements, err := jqElements(v)
+		if err != nil {
+			return jqFail(err)
+		}
+		for _, elem := range elements {
+			for cond, err := range args[0].eval(env, elem) {
+				if err != nil {
+					return jqFail(err)
+				}
+				if jqTruthy(cond) == any {
+					return jqOne(jqBool(any))
+				}
+			}
+		}
+		return jqOne(jqBool(!any))
+	}
+}
+func jqQuantify(v JsonValue, any bool) (JsonValue, error) {
+	elements, err := jqElements(v)
+	if err != nil {
+		return nil, err
+	}
+	for _, elem := range elements {
+		if jqTruthy(elem) == any {
+			return jqBool(any), nil
+		}
+	}
+	return jqBool(!any), nil
+}
+func jqRange(env *jqEnv, v JsonValue, args []jqNode) iter.Seq2[JsonValue, error] {
+	return func(yield func(JsonValue, error) bool) {
+		for bounds, err := range jqCartesian(env, v, args) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			switch len(bounds) {
+			case 1:
+				bounds = []JsonValue{jqNumber(0), bounds[0], jqNumber(1)}
+			case 2:
+				bounds = append(bounds, jqNumber(1))
+			}
+			from, fok := bounds[0].(*Number)
+			upto, uok := bounds[1].(*Number)
+			by, bok := bounds[2].(*Number)
+			if !fok || !uok || !bok {
+				yield(nil, jqErrorf("Range bounds must be numeric"))
+				return
+			}
+			// the zero step yields nothing as jq does
+			for i := *from; *by > 0 && i < *upto || *by < 0 && i > *upto; i += *by {
+				if err := env.step(); err != nil {
+					yield(nil, err)
+					return
+				}
+				if !yield(jqNumber(float64(i)), nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// jqIn defines IN, which reports whether any output of source equals any output of s.
+func jqIn(env *jqEnv, v JsonValue, source, s jqNode) iter.Seq2[JsonValue, error] {
+	return func(yield func(JsonValue, error) bool) {
+		for src, err := range source.eval(env, v) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for candidate, err := range s.eval(env, v) {
+				if err != nil {
+					yield(nil, err)
+					return
+				}
+				if Compare(src, candidate) == 0 {
+					yield(jqBool(true), nil)
+					return
+				}
+			}
+		}
+		yield(jqBool(false), nil)
+	}
+}
+func jqLimit(env *jqEnv, v JsonValue, f jqNode, limit int) iter.Seq2[JsonValue, error] {
+	return func(yield func(JsonValue, error) bool) {
+		if limit <= 0 {
+			return
+		}
+		count := 0
+		for output, err := range f.eval(env, v) {
+			if !yield(output, err) || err != nil {
+				return
+			}
+			if count++; count >= limit {
+				return
+			}
+		}
+	}
+}
+func jqLength(v JsonValue, _ []JsonValue) (JsonValue, error) {
+	switch t := v.(type) {
+	case *Null:
+		return jqNumber(0), nil
+	case *Number:
+		return jqNumber(math.Abs(float64(*t))), nil
+	case *String:
+		return jqNumber(float64(utf8.RuneCountInString(string(*t)))), nil
+	case *Array:
+		return jqNumber(float64(len(*t))), nil
+	case *Object:
+		return jqNumber(float64(len(*t))), nil
+	default:
+		return nil, jqErrorf("%s has no length", jqDescribe(v))
+	}
+}
+func jqKeys(v JsonValue, _ []JsonValue) (JsonValue, error) {
+	keys := make(Array, 0)
+	switch t := v.(type) {
+	case *Object:
+		for _, k := range slices.Sorted(maps.Keys(*t)) {
+			keys = append(keys, jqString(k))
+		}
+	case *Array:
+		for i := range *t {
+			keys = append(keys, jqNumber(float64(i)))
+		}
+	default:
+		return nil, jqErrorf("%s has no keys", jqDescribe(v))
+	}
+	return &keys, nil
+}
+func jqHas(v JsonValue, args []JsonValue) (JsonValue, error) {
+	switch t := v.(type) {
+	case *Object:
+		if k, ok := args[0].(*String); ok {
+			_, has := (*t)[string(*k)]
+			return jqBool(has), nil
+		}
+	case *Array:
+		if i, ok := args[0].(*Number); ok {
+			return jqBool(*i >= 0 && int(*i) < len(*t)), nil
+		}
+	}
+	return nil, jqErrorf("Cannot check whether %s has a %s key", jqType(v), jqType(args[0]))
+}
+func jqContains(a, b JsonValue) (bool, error) {
+	switch at := a.(type) {
+	case *Object:
+		if bt, ok := b.(*Object); ok {
+			for k, bv := range *bt {
+				av, ok := (*at)[k]
+				if !ok {
+					return false, nil
+				}
+				if contains, err := jqContains(av, bv); err != nil || !contains {
+					return false, err
+				}
+			}
+			return true, nil
+		}
+	case *Array:
+		if bt, ok := b.(*Array); ok {
+			for _, bv := range *bt {
+				found := false
+				for _, av := range *at {
+					if contains, err := jqContains(av, bv); err != nil {
+						return false, err
+					} else if contains {
+						found = true
+						break
+					}
+				}
+				if !found {
+					return false, nil
+				}
+			}
+			return true, nil
+		}
+	case *String:
+		if bt, ok := b.(*String); ok {
+			return strings.Contains(string(*at), string(*bt)), nil
+		}
+	default:
+		if jqType(a) == jqType(b) {
+			return Compare(a, b) == 0, nil
+		}
+	}
+	return false, jqErrorf("%s and %s cannot have their containment checked", jqDescribe(a), jqDescribe(b))
+}
+func jqSplitRegexp(v JsonValue, args []JsonValue) (JsonValue, error) {
+	s, ok := v.(*String)
+	if !ok {
+		return nil, jqErrorf("%s cannot be matched, as it is not a string", jqDescribe(v))
+	}
+	var flags JsonValue
+	if len(args) > 1 {
+		flags = args[1]
+	}
+	re, err := jqCompileRegexp(args[0], flags)
+	if err != nil {
+		return nil, err
+	}
+	split := make(Array, 0)
+	for _, part := range re.Split(string(*s), -1) {
+		split = append(split, jqString(part))
+	}
+	return &split, nil
+}
+func jqMapASCII(s string, from, to byte, shift int) string {
+	b := []byte(s)
+	for i, c := range b {
+		if from <= c && c <= to {
+			b[i] = byte(int(c) + shift)
+		}
+	}
+	return string(b)
+}
+func jqJoin(v JsonValue, args []JsonValue) (JsonValue, error) {
+	sep, ok := args[0].(*String)
+	if !ok {
+		return nil, jqErrorf("%s cannot be used as separator", jqDescribe(args[0]))
+	}
+	elements, err := jqElements(v)
+	if err != nil {
+		return nil, err
+	}
+	strs := make([]string, 0, len(elements))
+	for _, elem := range elements {
+		switch elem.(type) {
+		case *Null:
+			strs = append(strs, "")
+		case *String, *Number, *Bool:
+			s, _ := jqApplyFormat("text", elem)
+			strs = append(strs, s)
+		default:
+			return nil, jqErrorf("Cannot join with %s", jqDescribe(elem))
+		}
+	}
+	return jqString(strings.Join(strs, string(*sep))), nil
+}
+func jqCompileRegexp(pattern, flags JsonValue) (*regexp.Regexp, error) {
+	p, ok := pattern.(*String)
+	if !ok {
+		return nil, jqErrorf("%s cannot be matched, as it is not a string", jqDescribe(pattern))
+	}
+	prefix := ""
+	if flags != nil && !flags.IsNull() {
+		f, ok := flags.(*String)
+		if !ok {
+			return nil, jqErrorf("%s is not a string", jqDescribe(flags))
+		}
+		for _, c := range *f {
+			switch c {
+			case 'i', 's':
+				prefix += string(c)
+			case 'g', 'n', 'x', 'l', 'p':
+			default:
+				return nil, jqErrorf("%s is not a valid modifier string", *f)
+			}
+		}
+	}
+	if prefix != "" {
+		prefix = "(?" + prefix + ")"
+	}
+	if re, ok := jqRegexps.Load(prefix + string(*p)); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(prefix + string(*p))
+	if err != nil {
+		return nil, jqErrorf("%s (at offset 0) is not a valid regex: %s", *p, err)
+	}
+	jqRegexps.Store(prefix+string(*p), re)
+	return re, nil
+}
+func jqTest(v JsonValue, args []JsonValue) (JsonValue, error) {
+	s, ok := v.(*String)
+	if !ok {
+		return nil, jqErrorf("%s cannot be matched, as it is not a string", jqDescribe(v))
+	}
+	var flags JsonValue
+	if len(args) > 1 {
+		flags = args[1]
+	}
+	re, err := jqCompileRegexp(args[0], flags)
+	if err != nil {
+		return nil, err
+	}
+	return jqBool(re.MatchString(string(*s))), nil
+}
+
+// jqSubstitute defines sub and gsub, the replacement is evaluated with the object of named captures as input.
+func jqSubstitute(global bool) jqFunction {
+	return func(env *jqEnv, v JsonValue, args []jqNode) iter.Seq2[JsonValue, error] {
+		s, ok := v.(*String)
+		if !ok {
+			return jqFail(jqErrorf("%s cannot be matched, as it is not a string", jqDescribe(v)))
+		}
+		patterns, err := jqCollect(args[0], env, v)
+		if err != nil {
+			return jqFail(err)
+		}
+		return func(yield func(JsonValue, error) bool) {
+			for _, pattern := range patterns {
+				re, err := jqCompileRegexp(pattern, nil)
+				if err != nil {
+					yield(nil, err)
+					return
+				}
+				n := 1
+				if global {
+					n = -1
+				}
+				var replaced strings.Builder
+				last := 0
+				for _, match := range re.FindAllStringSubmatchIndex(string(*s), n) {
+					captures := make(Object)
+					for i, name := range re.SubexpNames() {
+						if name == "" {
+							continue
+						} else if match[2*i] < 0 {
+							captures[name] = jqNull()
+						} else {
+							captures[name] = jqString(string(*s)[match[2*i]:match[2*i+1]])
+						}
+					}
+					replacement, err := jqCollect(args[1], env, &captures)
+					if err != nil {
+						yield(nil, err)
+						return
+					}
+					if len(replacement) == 0 || !replacement[0].IsString() {
+						yield(nil, jqErrorf("replacement must be a string"))
+						return
+					}
+					replaced.WriteString(string(*s)[last:match[0]])
+					replaced.WriteString(string(*replacement[0].(*String)))
+					last = match[1]
+				}
+				replaced.WriteString(string(*s)[last:])
+				if !yield(jqString(replaced.String()), nil) {
+					return
+				}
+			}
+		}
+	}
+}
+func jqIndices(v, target JsonValue) (JsonValue, error) {
+	indices := make(Array, 0)
+	switch t := v.(type) {
+	case *Null:
+		return jqNull(), nil
+	case *String:
+		sub, ok := target.(*String)
+		if !ok {
+			return nil, jqErrorf("Cannot determine indices of %s in string", jqDescribe(target))
+		}
+		runes, subRunes := []rune(string(*t)), []rune(string(*sub))
+		for i := 0; len(subRunes) > 0 && i+len(subRunes) <= len(runes); i++ {
+			if slices.Equal(runes[i:i+len(subRunes)], subRunes) {
+				indices = append(indices, jqNumber(float64(i)))
+			}
+		}
+	case *Array:
+		sub, ok := target.(*Array)
+		if !ok {
+			sub = &Array{target}
+		}
+		for i := 0; len(*sub) > 0 && i+len(*sub) <= len(*t); i++ {
+			if slices.EqualFunc((*t)[i:i+len(*sub)], *sub, func(a, b JsonValue) bool { return Compare(a, b) == 0 }) {
+				indices = append(indices, jqNumber(float64(i)))
+			}
+		}
+	default:
+		return nil, jqErrorf("Cannot determine indices in %s", jqDescribe(v))
+	}
+	return &indices, nil
+}
+func jqIndicesAt(v, target JsonValue, at int) (JsonValue, error) {
+	indices, err := jqIndices(v, target)
+	if err != nil || indices.IsNull() {
+		return indices, err
+	}
+	return jqIndexValue(indices, jqNumber(float64(at)))
+}
+func jqMapValues(env *jqEnv, v JsonValue, f jqNode) (JsonValue, error) {
+	first := func(elem JsonValue) (JsonValue, bool, error) {
+		for output, err := range f.eval(env, elem) {
+			return output, err == nil, err
+		}
+		return nil, false, nil
+	}
+	switch t := v.(type) {
+	case *Array:
+		mapped := make(Array, 0, len(*t))
+		for _, elem := range *t {
+			output, ok, err := first(elem)
+			if err != nil {
+				return nil, err
+			} else if ok {
+				mapped = append(mapped, output)
+			}
+		}
+		return &mapped, nil
+	case *Object:
+		mapped := make(Object, len(*t))
+		for k, elem := range *t {
+			output, ok, err := first(elem)
+			if err != nil {
+				return nil, err
+			} else if ok {
+				mapped[k] = output
+			}
+		}
+		return &mapped, nil
+	default:
+		return nil, jqErrorf("Cannot iterate over %s", jqDescribe(v))
+	}
+}
+func jqWalk(env *jqEnv, v JsonValue, f jqNode) iter.Seq2[JsonValue, error] {
+	var walked JsonValue = v
+	switch t := v.(type) {
+	case *Array:
+		elements := make(Array, 0, len(*t))
+		for _, elem := range *t {
+			outputs, err := jqCollect(jqCall{function: func(env *jqEnv, v JsonValue, _ []jqNode) iter.Seq2[JsonValue, error] { return jqWalk(env, v, f) }}, env, elem)
+			if err != nil {
+				return jqFail(err)
+			}
+			elements = append(elements, outputs...)
+		}
+		walked = &elements
+	case *Object:
+		mapped, err := jqMapValues(env, v, jqCall{function: func(env *jqEnv, v JsonValue, _ []jqNode) iter.Seq2[JsonValue, error] { return jqWalk(env, v, f) }})
+		if err != nil {
+			return jqFail(err)
+		}
+		walked = mapped
+	}
+	return f.eval(env, walked)
+}
+
+// jqPaths evaluates the node as path expression against the input, and yields the paths of the outputs with their values.
+func jqPaths(env *jqEnv, node jqNode, input jqPathValue) iter.Seq2[jqPathValue, error] {
+	return func(yield func(jqPathValue, error) bool) {
+		// each passes the paths of the node to f until f returns false, and reports whether the iteration continues
+		each := func(env *jqEnv, node jqNode, input jqPathValue, f func(jqPathValue) bool) bool {
+			for pv, err := range jqPaths(env, node, input) {
+				if err != nil {
+					yield(jqPathValue{}, err)
+					return false
+				}
+				if !f(pv) {
+					return false
+				}
+			}
+			return true
+		}
+		// values passes the outputs of the node to f in the same way as each
+		values := func(node jqNode, f func(JsonValue) bool) bool {
+			for output, err := range node.eval(env, input.value) {
+				if err != nil {
+					yield(jqPathValue{}, err)
+					return false
+				}
+				if !f(output) {
+					return false
+				}
+			}
+			return true
+		}
+		emit := func(pv jqPathValue) bool { return yield(pv, nil) }
+
+		switch n := node.(type) {
+		case jqIdentity:
+			yield(input, nil)
+			return
+		case jqRecurse:
+			jqRecursePaths(env, input, yield)
+			return
+		case jqIndex:
+			each(env, n.target, input, func(target jqPathValue) bool {
+				return values(n.index, func(index JsonValue) bool {
+					pv, err := jqChildPath(target, index)
+					return yield(pv, err) && err == nil
+				})
+			})
+			return
+		case jqIterate:
+			each(env, n.target, input, func(target jqPathValue) bool {
+				if !target.value.IsArray() && !target.value.IsObject() {
+					yield(jqPathValue{}, jqErrorf("Cannot iterate over %s", jqDescribe(target.value)))
+					return false
+				}
+				keys, _ := jqKeys(target.value, nil)
+				for _, key := range *keys.(*Array) {
+					if err := env.step(); err != nil {
+						yield(jqPathValue{}, err)
+						return false
+					}
+					pv, _ := jqChildPath(target, key)
+					if !yield(pv, nil) {
+						return false
+					}
+				}
+				return true
+			})
+			return
+		case jqOptional:
+			jqPaths(env, jqTry{body: n.body}, input)(yield)
+			return
+		case jqTry:
+			if n.catch != nil {
+				break
+			}
+			for pv, err := range jqPaths(env, n.body, input) {
+				if err != nil {
+					if jqAborted(err) {
+						yield(jqPathValue{}, err)
+					}
+					return
+				}
+				if !yield(pv, nil) {
+					return
+				}
+			}
+			return
+		case jqPipe:
+			each(env, n.left, input, func(left jqPathValue) bool {
+				if err := env.step(); err != nil {
+					yield(jqPathValue{}, err)
+					return false
+				}
+				return each(env, n.right, left, emit)
+			})
+			return
+		case jqComma:
+			_ = each(env, n.left, input, emit) && each(env, n.right, input, emit)
+			return
+		case jqIf:
+			values(n.cond, func(cond JsonValue) bool {
+				branch := n.els
+				if jqTruthy(cond) {
+					branch = n.then
+				} else if branch == nil {
+					branch = jqIdentity{}
+				}
+				return each(env, branch, input, emit)
+			})
+			return
+		case jqBind:
+			values(n.source, func(source JsonValue) bool {
+				return each(env.bind(n.name, source), n.body, input, emit)
+			})
+			return
+		case jqCall:
+			switch fmt.Sprintf("%s/%d", n.name, len(n.args)) {
+			case "empty/0":
+				return
+			case "recurse/0":
+				jqRecursePaths(env, input, yield)
+				return
+			case "select/1":
+				values(n.args[0], func(cond JsonValue) bool {
+					return !jqTruthy(cond) || yield(input, nil)
+				})
+				return
+			case "getpath/1":
+				values(n.args[0], func(path JsonValue) bool {
+					keys, ok := path.(*Array)
+					if !ok {
+						yield(jqPathValue{}, jqErrorf("Path must be specified as an array"))
+						return false
+					}
+					pv := input
+					for _, key := range *keys {
+						var err error
+						if pv, err = jqChildPath(pv, key); err != nil {
+							yield(jqPathValue{}, err)
+							return false
+						}
+					}
+					return yield(pv, nil)
+				})
+				return
+			}
+		}
+
+		// the other filters are not paths, which is the error if they have outputs
+		values(node, func(output JsonValue) bool {
+			yield(jqPathValue{}, jqErrorf("Invalid path expression with result %s", compactJson(output)))
+			return false
+		})
+	}
+}
+func jqRecursePaths(env *jqEnv, input jqPathValue, yield func(jqPathValue, error) bool) bool {
+	if err := env.step(); err != nil {
+		yield(jqPathValue{}, err)
+		return false
+	}
+	if !yield(input, nil) {
+		return false
+	}
+	if keys, err := jqKeys(input.value, nil); err == nil {
+		for _, key := range *keys.(*Array) {
+			child, _ := jqChildPath(input, key)
+			if !jqRecursePaths(env, child, yield) {
+				return false
+			}
+		}
+	}
+	return true
+}
+func jqChildPath(parent jqPathValue, key JsonValue) (jqPathValue, error) {
+	value, err := jqIndexValue(parent.value, key)
+	return jqPathValue{path: append(slices.Clip(parent.path), key), value: value}, err
+}
+
+// jqLeafPaths defines paths, which yields the paths except the input itself whose values satisfy the filter if given.
+func jqLeafPaths(env *jqEnv, v JsonValue, filter jqNode) iter.Seq2[JsonValue, error] {
+	return func(yield func(JsonValue, error) bool) {
+		for pv, err := range jqPaths(env, jqRecurse{}, jqPathValue{path: Array{}, value: v}) {
+			if err != nil {
+				yield(nil, err)
+				return
+			} else if len(pv.path) == 0 {
+				continue
+			} else if filter == nil {
+				if !yield(&pv.path, nil) {
+					return
+				}
+				continue
+			}
+			for cond, err := range filter.eval(env, pv.value) {
+				if err != nil {
+					yield(nil, err)
+					return
+				}
+				if jqTruthy(cond) && !yield(&pv.path, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// jqDeletePaths deletes the paths from the last one in sorted order, so that deleting an element of array does not shift the other paths.
+// The value is copied along the paths, and is not modified.
+func jqDeletePaths(v JsonValue, paths Array) (JsonValue, error) {
+	sorted := slices.Clone(paths)
+	slices.SortFunc(sorted, Compare)
+	sorted = slices.CompactFunc(sorted, func(a, b JsonValue) bool { return Compare(a, b) == 0 })
+	for i := len(sorted) - 1; i >= 0; i-- {
+		path, ok := sorted[i].(*Array)
+		if !ok {
+			return nil, jqErrorf("Path must be specified as an array")
+		}
+		var err error
+		if v, err = jqDeletePath(v, *path); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+func jqDeletePath(v JsonValue, path Array) (JsonValue, error) {
+	if len(path) == 0 {
+		return jqNull(), nil
+	} else if v.IsNull() {
+		return v, nil
+	}
+	key := path[0]
+	if len(path) > 1 {
+		child, err := jqIndexValue(v, key)
+		if err != nil || child.IsNull() {
+			return v, err
+		}
+		deleted, err := jqDeletePath(child, path[1:])
+		if err != nil {
+			return nil, err
+		}
+		return jqReplaceChild(v, key, deleted, false), nil
+	}
+
+	switch t := v.(type) {
+	case *Object:
+		if _, ok := key.(*String); ok {
+			return jqReplaceChild(t, key, nil, true), nil
+		}
+	case *Array:
+		if _, ok := key.(*Number); ok {
+			return jqReplaceChild(t, key, nil, true), nil
+		}
+	}
+	if key.IsString() {
+		return nil, jqErrorf("Cannot delete field at object index of %s", jqType(v))
+	}
+	return nil, jqErrorf("Cannot delete field at index of %s", jqType(v))
+}
+
+// jqReplaceChild returns the copy of the object or array whose child at the valid key is replaced or deleted.
+func jqReplaceChild(v, key, child JsonValue, remove bool) JsonValue {
+	switch t := v.(type) {
+	case *Object:
+		replaced := maps.Clone(*t)
+		if k := string(*key.(*String)); remove {
+			delete(replaced, k)
+		} else {
+			replaced[k] = child
+		}
+		return &replaced
+	case *Array:
+		i := int(math.Floor(float64(*key.(*Number))))
+		if i < 0 {
+			i += len(*t)
+		}
+		if i < 0 || i >= len(*t) {
+			return v
+		}
+		replaced := slices.Clone(*t)
+		if remove {
+			replaced = slices.Delete(replaced, i, i+1)
+		} else {
+			replaced[i] = child
+		}
+		return &replaced
+	}
+	return v
+}
+func jqToEntries(v JsonValue, _ []JsonValue) (JsonValue, error) {
+	o, ok := v.(*Object)
+	if !ok {
+		return nil, jqErrorf("%s has no keys", jqDescribe(v))
+	}
+	entries := make(Array, 0, len(*o))
+	for _, k := range slices.Sorted(maps.Keys(*o)) {
+		entries = append(entries, &Object{"key": jqString(k), "value": (*o)[k]})
+	}
+	return &entries, nil
+}
+func jqFromEntries(v JsonValue, _ []JsonValue) (JsonValue, error) {
+	entries, err := jqElements(v)
+	if err != nil {
+		return nil, err
+	}
+	object := make(Object, len(entries))
+	for _, entry := range entries {
+		e, ok := entry.(*Object)
+		if !ok {
+			return nil, jqErrorf("Cannot index %s with \"key\"", jqType(entry))
+		}
+		lookup := func(names ...string) JsonValue {
+			for _, name := range names {
+				if v, ok := (*e)[name]; ok && jqTruthy(v) {
+					return v
+				}
+			}
+			for _, name := range names {
+				if v, ok := (*e)[name]; ok {
+					return v
+				}
+			}
+			return jqNull()
+		}
+		key := lookup("key", "k", "name", "Name", "Key", "K")
+		switch key.(type) {
+		case *String:
+		case *Number, *Bool, *Null:
+			key = jqString(compactJson(key))
+		default:
+			return nil, jqErrorf("Cannot use %s as object key", jqDescribe(key))
+		}
+		object[string(*key.(*String))] = lookup("value", "v", "Value", "V")
+	}
+	return &object, nil
+}
+func jqSortBy(v JsonValue, keys []JsonValue) (JsonValue, error) {
+	a, ok := v.(*Array)
+	if !ok {
+		return nil, jqErrorf("%s cannot be sorted, as it is not an array", jqDescribe(v))
+	}
+	if keys == nil {
+		keys = *a
+	}
+	indices := make([]int, len(*a))
+	for i := range indices {
+		indices[i] = i
+	}
+	slices.SortStableFunc(indices, func(i, j int) int { return Compare(keys[i], keys[j]) })
+	sorted := make(Array, 0, len(*a))
+	for _, i := range indices {
+		sorted = append(sorted, (*a)[i])
+	}
+	return &sorted, nil
+}
+func jqGroupBy(a Array, keys []JsonValue, unique bool) (JsonValue, error) {
+	indices := make([]int, len(a))
+	for i := range indices {
+		indices[i] = i
+	}
+	slices.SortStableFunc(indices, func(i, j int) int { return Compare(keys[i], keys[j]) })
+	groups := make(Array, 0)
+	for n, i := range indices {
+		if n == 0 || Compare(keys[indices[n-1]], keys[i]) != 0 {
+			if unique {
+				groups = append(groups, a[i])
+			} else {
+				groups = append(groups, &Array{})
+			}
+		}
+		if !unique {
+			group := groups[len(groups)-1].(*Array)
+			*group = append(*group, a[i])
+		}
+	}
+	return &groups, nil
+}
+func jqExtremum(name string, v JsonValue, keys []JsonValue, sign int) (JsonValue, error) {
+	a, ok := v.(*Array)
+	if !ok {
+		return nil, jqErrorf("%s cannot be used in %s, as it is not an array", jqDescribe(v), name)
+	}
+	if len(*a) == 0 {
+		return jqNull(), nil
+	}
+	if keys == nil {
+		keys = *a
+	}
+	best := 0
+	for i := 1; i < len(*a); i++ {
+		if c := Compare(keys[i], keys[best]) * sign; c > 0 || c == 0 && sign > 0 {
+			best = i
+		}
+	}
+	return (*a)[best], nil
+}
+func jqFlatten(v JsonValue, depth int) (JsonValue, error) {
+	a, ok := v.(*Array)
+	if !ok {
+		return nil, jqErrorf("Cannot iterate over %s", jqDescribe(v))
+	}
+	flattened := make(Array, 0, len(*a))
+	for _, elem := range *a {
+		if inner, ok := elem.(*Array); ok && depth > 0 {
+			f, _ := jqFlatten(inner, depth-1)
+			flattened = append(flattened, *f.(*Array)...)
+		} else {
+			flattened = append(flattened, elem)
+		}
+	}
+	return &flattened, nil
+}
+
+// jqApplyFormat applies the format such as @base64 to the value.
+func jqApplyFormat(name string, v JsonValue) (string, error) {
+	text := func(v JsonValue) string {
+		if s, ok := v.(*String); ok {
+			return string(*s)
+		}
+		return compactJson(v)
+	}
+	switch name {
+	case "text":
+		return text(v), nil
+	case "json":
+		return compactJson(v), nil
+	case "html":
+		return strings.NewReplacer("<", "&lt;", ">", "&gt;", "&", "&amp;", "'", "&#39;", `"`, "&quot;").Replace(text(v)), nil
+	case "uri":
+		var b strings.Builder
+		for _, c := range []byte(text(v)) {
+			if jpIsAlpha(c) || jpIsDigit(c) || strings.IndexByte("-_.~", c) >= 0 {
+				b.WriteByte(c)
+			} else {
+				fmt.Fprintf(&b, "%%%02X", c)
+			}
+		}
+		return b.String(), nil
+	case "base64":
+		return base64.StdEncoding.EncodeToString([]byte(text(v))), nil
+	case "base64d":
+		decoded, err := base64.RawStdEncoding.DecodeString(strings.TrimRight(text(v), "="))
+		if err != nil {
+			return "", jqErrorf("%s is not valid base64 data", jqDescribe(v))
+		}
+		return string(decoded), nil
+	case "csv", "tsv", "sh":
+		elements := []JsonValue{v}
+		if a, ok := v.(*Array); ok {
+			elements = *a
+		} else if name != "sh" {
+			return "", jqErrorf("%s cannot be %s-formatted, only an array can be", jqDescribe(v), name)
+		}
+		fields := make([]string, 0, len(elements))
+		for _, elem := range elements {
+			switch t := elem.(type) {
+			case *String:
+				switch name {
+				case "csv":
+					fields = append(fields, `"`+strings.ReplaceAll(string(*t), `"`, `""`)+`"`)
+				case "tsv":
+					fields = append(fields, strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`).Replace(string(*t)))
+				default:
+					fields = append(fields, "'"+strings.ReplaceAll(string(*t), "'", `'\''`)+"'")
+				}
+			case *Number, *Bool:
+				fields = append(fields, compactJson(elem))
+			case *Null:
+				fields = append(fields, map[string]string{"csv": "", "tsv": "", "sh": "null"}[name])
+			default:
+				return "", jqErrorf("%s is not valid in a %s row", jqDescribe(elem), name)
+			}
+		}
+		return strings.Join(fields, map[string]string{"csv": ",", "tsv": "\t", "sh": " "}[name]), nil
+	default:
+		return "", errJqUnknownFormat
+	}
+}
@@ -0,0 +1,294 @@
+package fluffyjson_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	fluffyjson "github.com/hayas1/go-fluffy-json"
+)
+
+func ExampleJq_Run() {
+	var value fluffyjson.RootValue
+	if err := json.Unmarshal([]byte(TestBookstore), &value); err != nil {
+		panic(err)
+	}
+
+	jq, err := fluffyjson.ParseJq(`.store.book[] | select(.price < 10) | "\(.title) by \(.author)"`)
+	if err != nil {
+		panic(err)
+	}
+	outputs, errFn := jq.Run(&value)
+	for output := range outputs {
+		fmt.Println(*output.(*fluffyjson.String))
+	}
+	if err := errFn(); err != nil {
+		panic(err)
+	}
+	// Output:
+	// Sayings of the Century by Nigel Rees
+	// Moby Dick by Herman Melville
+}
+
+func TestJqRun(t *testing.T) {
+	testcases := map[string]struct {
+		filter   string
+		input    string
+		expected string
+	}{
+		// paths
+		"identity":         {`.`, `{"a": 1}`, `[{"a": 1}]`},
+		"field":            {`.a.b`, `{"a": {"b": 2}}`, `[2]`},
+		"quoted field":     {`."a b"`, `{"a b": 1}`, `[1]`},
+		"missing field":    {`.a.b`, `{"a": null}`, `[null]`},
+		"index":            {`.[1]`, `[1, 2, 3]`, `[2]`},
+		"negative index":   {`.[-1]`, `[1, 2, 3]`, `[3]`},
+		"out of range":     {`.[5]`, `[1, 2, 3]`, `[null]`},
+		"string index":     {`.["a"]`, `{"a": 1}`, `[1]`},
+		"slice":            {`.[1:3]`, `[1, 2, 3, 4]`, `[[2, 3]]`},
+		"open slice":       {`.[2:], .[:-3]`, `[1, 2, 3, 4]`, `[[3, 4], [1]]`},
+		"string slice":     {`.[1:3]`, `"abcd"`, `["bc"]`},
+		"iterate array":    {`.[]`, `[1, 2]`, `[1, 2]`},
+		"iterate object":   {`.[]`, `{"b": 2, "a": 1}`, `[1, 2]`},
+		"nested iterate":   {`.a[].b`, `{"a": [{"b": 1}, {"b": 2}]}`, `[1, 2]`},
+		"dot bracket":      {`.a.[0]`, `{"a": [1]}`, `[1]`},
+		"optional stops":   {`.[]?.a?`, `[{"a": 2}, 1, {"a": 3}]`, `[2]`},
+		"optional iterate": {`.a[]?`, `{"a": 1}`, `[]`},
+		"recurse":          {`[..] | length`, `{"a": [1, {"b": 2}]}`, `[5]`},
+		"recurse select":   {`[.. | numbers]`, `{"a": [1, {"b": 2}]}`, `[[1, 2]]`},
+		// operators
+		"pipe":              {`.a | .b`, `{"a": {"b": 1}}`, `[1]`},
+		"comma":             {`.a, .b`, `{"a": 1, "b": 2}`, `[1, 2]`},
+		"arithmetic":        {`(.a + .b) * 2 - 1, 7 % 3, 10 / 4`, `{"a": 1, "b": 2}`, `[5, 1, 2.5]`},
+		"precedence":        {`1 + 2 * 3`, `null`, `[7]`},
+		"negation":          {`-.a, -1 + 2`, `{"a": 1}`, `[-1, 1]`},
+		"add values":        {`"a" + "b", [1] + [2], {"a": 1} + {"b": 2}, null + 1`, `null`, `["ab", [1, 2], {"a": 1, "b": 2}, 1]`},
+		"subtract arrays":   {`[1, 2, 3, 2] - [2]`, `null`, `[[1, 3]]`},
+		"multiply objects":  {`{"a": {"b": 1}} * {"a": {"c": 2}}`, `null`, `[{"a": {"b": 1, "c": 2}}]`},
+		"repeat string":     {`"ab" * 2`, `null`, `["abab"]`},
+		"divide strings":    {`"a,b" / ","`, `null`, `[["a", "b"]]`},
+		"cartesian":         {`(1, 2) + (10, 20)`, `null`, `[11, 12, 21, 22]`},
+		"compare":           {`1 < 2, "a" > "b", [1] == [1], null < false, {} > []`, `null`, `[true, false, true, true, true]`},
+		"and or not":        {`true and false, true or error, (null | not)`, `null`, `[false, true, true]`},
+		"alternative":       {`.a // "default", .b // "default"`, `{"a": false, "b": 1}`, `["default", 1]`},
+		"alternative error": {`(error("x")) // 1`, `null`, `[1]`},
+		// construction
+		"array":              {`[.[] | . * 2]`, `[1, 2]`, `[[2, 4]]`},
+		"empty array":        {`[]`, `null`, `[[]]`},
+		"object":             {`{a: .x, "b": 2, (.k): 3}`, `{"x": 1, "k": "c"}`, `[{"a": 1, "b": 2, "c": 3}]`},
+		"object shorthand":   {`{name, age}`, `{"name": "a", "age": 1, "x": 2}`, `[{"name": "a", "age": 1}]`},
+		"object variable":    {`. as $x | {$x}`, `1`, `[{"x": 1}]`},
+		"object cartesian":   {`{a: (1, 2)}`, `null`, `[{"a": 1}, {"a": 2}]`},
+		"object keyword key": {`{if: 1}`, `null`, `[{"if": 1}]`},
+		"interpolation":      {`"x=\(.x), y=\(.y)"`, `{"x": 1, "y": [1]}`, `["x=1, y=[1]"]`},
+		"nested string":      {`"a\("b\("c")")"`, `null`, `["abc"]`},
+		"unicode escape":     {`"é😀"`, `null`, `["é😀"]`},
+		"literals":           {`null, true, false, 1.5e1`, `null`, `[null, true, false, 15]`},
+		// control flow
+		"if":                {`if . > 1 then "big" elif . == 1 then "one" else "small" end`, `1`, `["one"]`},
+		"if without else":   {`if . then "yes" end`, `false`, `[false]`},
+		"try catch":         {`try error("oops") catch .`, `null`, `["oops"]`},
+		"try without catch": {`[.[] | try tonumber]`, `["1", "x", "2"]`, `[[1, 2]]`},
+		"error object":      {`try error({a: 1}) catch .a`, `null`, `[1]`},
+		"variable":          {`.a as $x | .b | . + $x`, `{"a": 1, "b": 2}`, `[3]`},
+		"variable scope":    {`[.[] as $x | $x * 2]`, `[1, 2]`, `[[2, 4]]`},
+		"reduce":            {`reduce .[] as $x (0; . + $x)`, `[1, 2, 3]`, `[6]`},
+		"foreach":           {`[foreach .[] as $x (0; . + $x)]`, `[1, 2, 3]`, `[[1, 3, 6]]`},
+		"foreach extract":   {`[foreach .[] as $x (0; . + $x; [$x, .])]`, `[1, 2]`, `[[[1, 1], [2, 3]]]`},
+		"comment":           {"1 # comment\n+ 1", `null`, `[2]`},
+		// builtins
+		"length":            {`.[] | length`, `[[1, 2], "abc", {"a": 1}, null, -3]`, `[2, 3, 1, 0, 3]`},
+		"keys":              {`keys, (.a | keys)`, `{"b": 1, "a": [5, 6]}`, `[["a", "b"], [0, 1]]`},
+		"has":               {`has("a"), has("c")`, `{"a": 1}`, `[true, false]`},
+		"map":               {`map(. + 1)`, `[1, 2]`, `[[2, 3]]`},
+		"map values":        {`map_values(. * 10)`, `{"a": 1, "b": 2}`, `[{"a": 10, "b": 20}]`},
+		"select":            {`map(select(. > 1))`, `[1, 2, 3]`, `[[2, 3]]`},
+		"add":               {`add, ([] | add), (["a", "b"] | add)`, `[1, 2, 3]`, `[6, null, "ab"]`},
+		"any all":           {`any, all, any(. > 2), all(. > 0)`, `[1, 2, 3]`, `[true, true, true, true]`},
+		"range":             {`[range(3)], [range(1; 3)]`, `null`, `[[0, 1, 2], [1, 2]]`},
+		"range by":          {`[range(0; 10; 3)], [range(5; 0; -2)], [range(0; 3; 0)]`, `null`, `[[0, 3, 6, 9], [5, 3, 1], []]`},
+		"type":              {`map(type)`, `[null, true, 1, "a", [], {}]`, `[["null", "boolean", "number", "string", "array", "object"]]`},
+		"tostring tojson":   {`map(tostring), (. | tojson)`, `[1, "a", [1]]`, `[["1", "a", "[1]"], "[1,\"a\",[1]]"]`},
+		"fromjson":          {`fromjson | .a`, `"{\"a\": 1}"`, `[1]`},
+		"tonumber":          {`map(tonumber)`, `["1.5", 2]`, `[[1.5, 2]]`},
+		"case":              {`ascii_downcase, ascii_upcase`, `"aBc"`, `["abc", "ABC"]`},
+		"split join":        {`split(", ") | join("-")`, `"a, b, c"`, `["a-b-c"]`},
+		"splits":            {`[splits(", *")], split("B"; "i")`, `"a, b,c"`, `[["a", "b", "c"], ["a, ", ",c"]]`},
+		"join mixed":        {`join(",")`, `["a", 1, null, true]`, `["a,1,,true"]`},
+		"trim str":          {`ltrimstr("foo"), rtrimstr("bar"), ltrimstr(1)`, `"foobar"`, `["bar", "foo", "foobar"]`},
+		"startswith":        {`startswith("foo"), endswith("foo")`, `"foobar"`, `[true, false]`},
+		"test":              {`test("B"), test("B"; "i"), test("^f")`, `"foobar"`, `[false, true, true]`},
+		"sub gsub":          {`sub("o"; "0"), gsub("o"; "0")`, `"foo"`, `["f0o", "f00"]`},
+		"sub captures":      {`gsub("(?P<x>[a-z])(?P<y>[0-9])"; "\(.y)\(.x)")`, `"a1b2"`, `["1a2b"]`},
+		"contains mismatch": {`contains({"a": [1]}), contains("bar"), ("foo" | contains("o"))`, `{"a": [1, 2], "b": "c"}`, `error`},
+		"contains object":   {`contains({"a": [1]}), contains({"c": 1})`, `{"a": [1, 2], "b": "c"}`, `[true, false]`},
+		"inside":            {`inside([1, 2, 3])`, `[1, 3]`, `[true]`},
+		"in":                {`map(IN(2, 3)), IN(.[]; 5, 1)`, `[1, 2]`, `[[false, true], true]`},
+		"indices":           {`indices(", "), index(", "), rindex(", ")`, `"a, b, c"`, `[[1, 4], 1, 4]`},
+		"array indices":     {`indices(1), indices([1, 2])`, `[0, 1, 2, 1, 3, 1, 2]`, `[[1, 3, 5], [1, 5]]`},
+		"entries":           {`to_entries`, `{"a": 1}`, `[[{"key": "a", "value": 1}]]`},
+		"from entries":      {`from_entries`, `[{"key": "a", "value": 1}, {"name": "b", "v": 2}, {"k": 3}]`, `[{"a": 1, "b": 2, "3": null}]`},
+		"with entries map":  {`with_entries({key: ("x_" + .key), value})`, `{"a": 1}`, `[{"x_a": 1}]`},
+		"sort":              {`sort`, `[3, "a", null, [1], true, 1, {}]`, `[[null, true, 1, 3, "a", [1], {}]]`},
+		"sort by":           {`sort_by(.a) | map(.b)`, `[{"a": 2, "b": 1}, {"a": 1, "b": 2}, {"a": 2, "b": 3}]`, `[[2, 1, 3]]`},
+		"group by":          {`group_by(.a) | map(length)`, `[{"a": 2}, {"a": 1}, {"a": 2}]`, `[[1, 2]]`},
+		"unique":            {`unique, unique_by(length)`, `["ab", "c", "ab", "d"]`, `[["ab", "c", "d"], ["c", "ab"]]`},
+		"min max":           {`min, max, (map({a: .}) | max_by(.a).a, min_by(.a).a)`, `[3, 1, 2]`, `[1, 3, 3, 1]`},
+		"reverse":           {`reverse, ("abc" | reverse)`, `[1, 2]`, `[[2, 1], "cba"]`},
+		"flatten":           {`flatten, flatten(1)`, `[1, [2, [3]]]`, `[[1, 2, 3], [1, 2, [3]]]`},
+		"first last":        {`first, last, first(.[] | select(. > 1)), last(.[]), [limit(2; .[])]`, `[1, 2, 3]`, `[1, 3, 2, 3, [1, 2]]`},
+		"recurse children":  {`[recurse(.children[]) | .name]`, `{"name": "a", "children": [{"name": "b", "children": []}]}`, `[["a", "b"]]`},
+		"path":              {`path(.a[0].b?), [path(..)]`, `{"a": [{"b": 1}]}`, `[["a", 0, "b"], [[], ["a"], ["a", 0], ["a", 0, "b"]]]`},
+		"paths":             {`[paths], [paths(type == "number")]`, `{"a": [1, {"b": 2}]}`, `[[["a"], ["a", 0], ["a", 1], ["a", 1, "b"]], [["a", 0], ["a", 1, "b"]]]`},
+		"getpath":           {`getpath(["a", 1, "b"]), getpath(["x", "y"])`, `{"a": [1, {"b": 2}]}`, `[2, null]`},
+		"del":               {`del(.a[0], .c), del(.a[] | select(. == 1))`, `{"a": [1, {"b": 2}], "c": null}`, `[{"a": [{"b": 2}]}, {"a": [{"b": 2}], "c": null}]`},
+		"del indices":       {`del(.[1, 2]), del(.[-1]), delpaths([[0], [1]])`, `[1, 2, 3]`, `[[1], [1, 2], [3]]`},
+		"walk":              {`walk(if type == "number" then . + 1 else . end)`, `{"a": [1, {"b": 2}]}`, `[{"a": [2, {"b": 3}]}]`},
+		"explode implode":   {`explode, (explode | implode)`, `"aé"`, `[[97, 233], "aé"]`},
+		"math":              {`floor, ceil, sqrt, fabs`, `2.25`, `[2, 3, 1.5, 2.25]`},
+		"empty":             {`1, empty, 2`, `null`, `[1, 2]`},
+		"values":            {`[.[] | values]`, `[1, null, 2]`, `[[1, 2]]`},
+		"formats":           {`@base64, (@base64 | @base64d), @uri, @html, @json`, `"<a&b>"`, `["PGEmYj4=", "<a&b>", "%3Ca%26b%3E", "&lt;a&amp;b&gt;", "\"<a&b>\""]`},
+		"csv tsv":           {`@csv, @tsv`, `["a\"b", 1, null, "c\td"]`, `["\"a\"\"b\",1,,\"c\td\"", "a\"b\t1\t\tc\\td"]`},
+		// errors
+		"index number":    {`.a`, `1`, `error`},
+		"iterate null":    {`.[]`, `null`, `error`},
+		"add mismatch":    {`1 + "a"`, `null`, `error`},
+		"divide by zero":  {`1 / 0`, `null`, `error`},
+		"error function":  {`error("custom")`, `null`, `error`},
+		"invalid path":    {`path(1)`, `null`, `error`},
+		"delete mismatch": {`del(.a)`, `[1]`, `error`},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			jq, err := fluffyjson.ParseJq(tc.filter)
+			if tc.expected == "error" && err != nil {
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			value := HelperUnmarshalValue(t, tc.input)
+			outputs, errFn := jq.Run(&value)
+			actual := make([]fluffyjson.JsonValue, 0)
+			for output := range outputs {
+				actual = append(actual, output)
+			}
+			if err := errFn(); tc.expected == "error" {
+				if err == nil {
+					t.Fatalf("expected error, but got %v", actual)
+				}
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			actualJson, err := json.Marshal(actual)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expectedJson, err := json.Marshal(HelperUnmarshalValue(t, tc.expected))
+			if err != nil {
+				t.Fatal(err)
+			}
+			HelperFatalEvaluate(t, string(expectedJson), string(actualJson))
+		})
+	}
+}
+
+func TestJqRunBudget(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	testcases := map[string]struct {
+		filter   string
+		opts     []fluffyjson.JqOption
+		expected error
+	}{
+		"step limit":         {`[limit(3; .[0] | recurse(. + 1))]`, []fluffyjson.JqOption{fluffyjson.JqStepLimit(100)}, nil},
+		"infinite recursion": {`.[0] | last(recurse(. + 1))`, []fluffyjson.JqOption{fluffyjson.JqStepLimit(100)}, fluffyjson.ErrJqStepLimit{}},
+		"infinite range":     {`[range(0; 1e9)] | length`, []fluffyjson.JqOption{fluffyjson.JqStepLimit(100)}, fluffyjson.ErrJqStepLimit{}},
+		"uncaught by try":    {`.[0] | try last(recurse(. + 1)) catch 0`, []fluffyjson.JqOption{fluffyjson.JqStepLimit(100)}, fluffyjson.ErrJqStepLimit{}},
+		"uncaught by alt":    {`.[0] | last(recurse(. + 1))? // 0`, []fluffyjson.JqOption{fluffyjson.JqStepLimit(100)}, fluffyjson.ErrJqStepLimit{}},
+		"repeat output":      {`"ab" * 1000`, []fluffyjson.JqOption{fluffyjson.JqStepLimit(100)}, fluffyjson.ErrJqStepLimit{}},
+		"canceled":           {`[.[]]`, []fluffyjson.JqOption{fluffyjson.JqContext(canceled)}, context.Canceled},
+		"not canceled":       {`[.[]]`, []fluffyjson.JqOption{fluffyjson.JqContext(context.Background())}, nil},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			jq, err := fluffyjson.ParseJq(tc.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			value := HelperUnmarshalValue(t, `[0]`)
+			outputs, errFn := jq.Run(&value, tc.opts...)
+			for range outputs {
+			}
+			if err := errFn(); tc.expected == nil && err != nil {
+				t.Fatal(err)
+			} else if !errors.Is(err, tc.expected) {
+				t.Fatalf("expected %v, but got %v", tc.expected, err)
+			}
+		})
+	}
+}
+
+func TestJqErrors(t *testing.T) {
+	testcases := map[string]struct {
+		filter  string
+		input   string
+		syntax  bool
+		message string
+	}{
+		"unbound variable":      {filter: `$x`, syntax: true},
+		"undefined function":    {filter: `foo(1)`, syntax: true},
+		"wrong arity":           {filter: `map`, syntax: true},
+		"unclosed bracket":      {filter: `.[0`, syntax: true},
+		"assignment":            {filter: `.a = 1`, syntax: true},
+		"update assignment":     {filter: `.a |= 1`, syntax: true},
+		"definition":            {filter: `def f: 1; f`, syntax: true},
+		"unterminated string":   {filter: `"abc`, syntax: true},
+		"unterminated interp":   {filter: `"\(1"`, syntax: true},
+		"non associative":       {filter: `1 < 2 < 3`, syntax: true},
+		"unknown format":        {filter: `@unknown`, syntax: true},
+		"variable out of scope": {filter: `(1 as $x | $x), $x`, syntax: true},
+		"index error":           {filter: `.a`, input: `[1]`, message: `Cannot index array with "a"`},
+		"iterate error":         {filter: `.[]`, input: `1`, message: `Cannot iterate over number (1)`},
+		"add error":             {filter: `. + 1`, input: `{}`, message: `object ({}) and number (1) cannot be added`},
+		"custom error":          {filter: `error("custom")`, input: `null`, message: `custom`},
+		"non string error":      {filter: `error({a: 1})`, input: `null`, message: `{"a":1} (not a string)`},
+		"huge repeat":           {filter: `"ab" * 1e19`, input: `null`, message: `string ("ab") repeated 1e+19 times exceeds the limit of 16777216 bytes`},
+		"large repeat":          {filter: `"ab" * 1e8`, input: `null`, message: `string ("ab") repeated 1e+08 times exceeds the limit of 16777216 bytes`},
+		"nan repeat":            {filter: `"ab" * (1e308 * 10 - 1e308 * 10)`, input: `null`, message: `string ("ab") cannot be repeated NaN times`},
+		"inf repeat":            {filter: `"ab" * (1e308 * 10)`, input: `null`, message: `string ("ab") cannot be repeated +Inf times`},
+		"negative inf repeat":   {filter: `-1e308 * 10 * "ab"`, input: `null`, message: `string ("ab") cannot be repeated -Inf times`},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			jq, err := fluffyjson.ParseJq(tc.filter)
+			if tc.syntax {
+				var errJq fluffyjson.ErrJq
+				if !errors.As(err, &errJq) {
+					t.Fatalf("expected syntax error, but got %v", err)
+				}
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			value := HelperUnmarshalValue(t, tc.input)
+			outputs, errFn := jq.Run(&value)
+			for range outputs {
+			}
+			var errRuntime fluffyjson.ErrJqRuntime
+			if err := errFn(); !errors.As(err, &errRuntime) {
+				t.Fatalf("expected runtime error, but got %v", err)
+			}
+			HelperFatalEvaluate(t, tc.message, errRuntime.Error())
+		})
+	}
+}
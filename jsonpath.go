@@ -0,0 +1,1112 @@
+package fluffyjson
+
+import (
+	"fmt"
+	"iter"
+	"maps"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+type (
+	// JsonPath is the query of RFC 9535 JSONPath(https://www.rfc-editor.org/rfc/rfc9535).
+	JsonPath struct {
+		query    string
+		segments []jpSegment
+	}
+
+	ErrJsonPath struct {
+		Query  string
+		Offset int
+		Reason string
+	}
+
+	jpNode struct {
+		pointer Pointer
+		value   JsonValue
+	}
+	jpContext struct {
+		root    JsonValue
+		current JsonValue
+	}
+	jpSegment struct {
+		descendant bool
+		selectors  []jpSelector
+	}
+	jpSelector interface {
+		selecting(jpNode, jpContext, func(jpNode) bool) bool
+	}
+	jpNameSelector     string
+	jpWildcardSelector struct{}
+	jpIndexSelector    int
+	jpSliceSelector    struct {
+		start, end, step *int
+	}
+	jpFilterSelector struct {
+		expr jpLogical
+	}
+
+	// filter expression, which is typed by RFC 9535 type system
+	jpExpr    any
+	jpLogical interface {
+		test(jpContext) bool
+	}
+	jpValue interface {
+		value(jpContext) JsonValue
+	}
+	jpNodes interface {
+		nodes(jpContext) []jpNode
+	}
+	jpType       int
+	jpOr         []jpLogical
+	jpAnd        []jpLogical
+	jpNot        struct{ expr jpLogical }
+	jpExists     struct{ nodelist jpNodes }
+	jpComparison struct {
+		op          string
+		left, right jpValue
+	}
+	jpLiteral struct{ literal JsonValue }
+	jpQuery   struct {
+		relative bool
+		singular bool
+		segments []jpSegment
+	}
+	jpCall struct {
+		name     string
+		function jpFunction
+		args     []any
+	}
+	jpFunction struct {
+		params []jpType
+		result jpType
+		call   func(args []any) any
+	}
+
+	jpParser struct {
+		query string
+		pos   int
+	}
+)
+
+const (
+	jpValueType jpType = iota
+	jpLogicalType
+	jpNodesType
+)
+
+const jpMaxInt = 1<<53 - 1
+
+var (
+	jpFunctions = map[string]jpFunction{
+		"length": {params: []jpType{jpValueType}, result: jpValueType, call: jpLength},
+		"count":  {params: []jpType{jpNodesType}, result: jpValueType, call: jpCount},
+		"match":  {params: []jpType{jpValueType, jpValueType}, result: jpLogicalType, call: jpMatch},
+		"search": {params: []jpType{jpValueType, jpValueType}, result: jpLogicalType, call: jpSearch},
+		"value":  {params: []jpType{jpNodesType}, result: jpValueType, call: jpValueOf},
+	}
+	jpRegexps sync.Map
+)
+
+func (e ErrJsonPath) Error() string {
+	return fmt.Sprintf("invalid JSONPath %q at %d: %s", e.Query, e.Offset, e.Reason)
+}
+
+// ParseJsonPath parses the RFC 9535 JSONPath query, such as `$.store.book[?@.price < 10].title`.
+func ParseJsonPath(query string) (JsonPath, error) {
+	p := &jpParser{query: query}
+	if p.peek() != '$' {
+		return JsonPath{}, p.errorf("query must start with $")
+	}
+	p.pos++
+	segments, err := p.parseSegments()
+	if err != nil {
+		return JsonPath{}, err
+	}
+	if p.pos != len(p.query) {
+		return JsonPath{}, p.errorf("unexpected character %q", p.query[p.pos])
+	}
+	return JsonPath{query: query, segments: segments}, nil
+}
+
+func (jp JsonPath) String() string {
+	return jp.query
+}
+
+// Query yields the nodes selected by the JSONPath, with the pointer of each node.
+func (jp JsonPath) Query(v JsonValue) iter.Seq2[Pointer, JsonValue] {
+	return func(yield func(Pointer, JsonValue) bool) {
+		v = unwrapRoot(v)
+		ctx := jpContext{root: v, current: v}
+		jpSelect(jp.segments, jpNode{value: v}, ctx, func(n jpNode) bool { return yield(n.pointer, n.value) })
+	}
+}
+
+// NormalizedPath returns the RFC 9535 normalized path, such as `$['store']['book'][0]`.
+func (p Pointer) NormalizedPath() (string, error) {
+	var b strings.Builder
+	b.WriteString("$")
+	if err := p.writeNormalizedPath(&b); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+func (p Pointer) writeNormalizedPath(b *strings.Builder) error {
+	for _, acc := range p {
+		switch a := acc.(type) {
+		case Pointer:
+			if err := a.writeNormalizedPath(b); err != nil {
+				return err
+			}
+		case IndexAccess:
+			fmt.Fprintf(b, "[%d]", a)
+		case KeyAccess:
+			b.WriteString("[" + jpQuote(string(a)) + "]")
+		case KeyIndexAccess:
+			b.WriteString("[" + jpQuote(string(a)) + "]")
+		case FuzzyKeyAccess:
+			b.WriteString("[" + jpQuote(string(a)) + "]")
+		default:
+			return fmt.Errorf("%T cannot be represented as normalized path", a)
+		}
+	}
+	return nil
+}
+func jpQuote(s string) string {
+	var b strings.Builder
+	b.WriteByte('\'')
+	for _, r := range s {
+		switch r {
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\'':
+			b.WriteString(`\'`)
+		case '\\':
+			b.WriteString(`\\`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(&b, `\u%04x`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('\'')
+	return b.String()
+}
+
+func jpSelect(segments []jpSegment, node jpNode, ctx jpContext, yield func(jpNode) bool) bool {
+	if len(segments) == 0 {
+		return yield(node)
+	}
+	return segments[0].selecting(node, ctx, func(n jpNode) bool {
+		return jpSelect(segments[1:], n, ctx, yield)
+	})
+}
+func jpCollect(segments []jpSegment, node jpNode, ctx jpContext) []jpNode {
+	nodes := make([]jpNode, 0)
+	jpSelect(segments, node, ctx, func(n jpNode) bool {
+		nodes = append(nodes, n)
+		return true
+	})
+	return nodes
+}
+
+// jpChildren yields the children of the node, object members are yielded in key order.
+func jpChildren(node jpNode, yield func(jpNode) bool) bool {
+	switch t := node.value.(type) {
+	case *Object:
+		for _, k := range slices.Sorted(maps.Keys(*t)) {
+			if !yield(jpNode{pointer: append(slices.Clip(node.pointer), KeyAccess(k)), value: (*t)[k]}) {
+				return false
+			}
+		}
+	case *Array:
+		for i, v := range *t {
+			if !yield(jpNode{pointer: append(slices.Clip(node.pointer), IndexAccess(i)), value: v}) {
+				return false
+			}
+		}
+	}
+	return true
+}
+func jpDescendants(node jpNode, yield func(jpNode) bool) bool {
+	if !yield(node) {
+		return false
+	}
+	return jpChildren(node, func(child jpNode) bool {
+		return jpDescendants(child, yield)
+	})
+}
+
+func (s jpSegment) selecting(node jpNode, ctx jpContext, yield func(jpNode) bool) bool {
+	apply := func(n jpNode) bool {
+		for _, sel := range s.selectors {
+			if !sel.selecting(n, ctx, yield) {
+				return false
+			}
+		}
+		return true
+	}
+	if s.descendant {
+		return jpDescendants(node, apply)
+	}
+	return apply(node)
+}
+func (s jpNameSelector) selecting(node jpNode, _ jpContext, yield func(jpNode) bool) bool {
+	if o, ok := node.value.(*Object); ok {
+		if v, ok := (*o)[string(s)]; ok {
+			return yield(jpNode{pointer: append(slices.Clip(node.pointer), KeyAccess(s)), value: v})
+		}
+	}
+	return true
+}
+func (s jpWildcardSelector) selecting(node jpNode, _ jpContext, yield func(jpNode) bool) bool {
+	return jpChildren(node, yield)
+}
+func (s jpIndexSelector) selecting(node jpNode, _ jpContext, yield func(jpNode) bool) bool {
+	if a, ok := node.value.(*Array); ok {
+		if v, err := IndexAccess(s).Accessing(a); err == nil {
+			index := int(s)
+			if index < 0 {
+				index += len(*a)
+			}
+			return yield(jpNode{pointer: append(slices.Clip(node.pointer), IndexAccess(index)), value: v})
+		}
+	}
+	return true
+}
+func (s jpSliceSelector) selecting(node jpNode, _ jpContext, yield func(jpNode) bool) bool {
+	a, ok := node.value.(*Array)
+	if !ok {
+		return true
+	}
+	slice := SliceAccess{Step: 1}
+	if s.step != nil {
+		slice.Step = *s.step
+	}
+	if slice.Step == 0 {
+		return true
+	}
+	if slice.Start, slice.End = 0, math.MaxInt; slice.Step < 0 {
+		slice.Start, slice.End = math.MaxInt, math.MinInt
+	}
+	if s.start != nil {
+		slice.Start = *s.start
+	}
+	if s.end != nil {
+		slice.End = *s.end
+	}
+	start, end, step := slice.Indices(len(*a))
+	for i := start; (step > 0 && i < end) || (step < 0 && i > end); i += step {
+		if !yield(jpNode{pointer: append(slices.Clip(node.pointer), IndexAccess(i)), value: (*a)[i]}) {
+			return false
+		}
+	}
+	return true
+}
+func (s jpFilterSelector) selecting(node jpNode, ctx jpContext, yield func(jpNode) bool) bool {
+	return jpChildren(node, func(child jpNode) bool {
+		ctx.current = child.value
+		if s.expr.test(ctx) {
+			return yield(child)
+		}
+		return true
+	})
+}
+
+func (e jpOr) test(ctx jpContext) bool {
+	for _, l := range e {
+		if l.test(ctx) {
+			return true
+		}
+	}
+	return false
+}
+func (e jpAnd) test(ctx jpContext) bool {
+	for _, l := range e {
+		if !l.test(ctx) {
+			return false
+		}
+	}
+	return true
+}
+func (e jpNot) test(ctx jpContext) bool {
+	return !e.expr.test(ctx)
+}
+func (e jpExists) test(ctx jpContext) bool {
+	return len(e.nodelist.nodes(ctx)) > 0
+}
+func (e jpComparison) test(ctx jpContext) bool {
+	l, r := e.left.value(ctx), e.right.value(ctx)
+	switch e.op {
+	case "==":
+		return equal(l, r)
+	case "!=":
+		return !equal(l, r)
+	case "<":
+		return jpLess(l, r)
+	case "<=":
+		return jpLess(l, r) || equal(l, r)
+	case ">":
+		return jpLess(r, l)
+	case ">=":
+		return jpLess(r, l) || equal(l, r)
+	default:
+		return false
+	}
+}
+func jpLess(a, b JsonValue) bool {
+	switch at := a.(type) {
+	case *Number:
+		bt, ok := b.(*Number)
+		return ok && *at < *bt
+	case *String:
+		bt, ok := b.(*String)
+		return ok && *at < *bt
+	default:
+		return false
+	}
+}
+func (e jpLiteral) value(jpContext) JsonValue {
+	return e.literal
+}
+func (e *jpQuery) nodes(ctx jpContext) []jpNode {
+	start := ctx.root
+	if e.relative {
+		start = ctx.current
+	}
+	return jpCollect(e.segments, jpNode{value: start}, ctx)
+}
+func (e *jpQuery) value(ctx jpContext) JsonValue {
+	if nodes := e.nodes(ctx); len(nodes) == 1 {
+		return nodes[0].value
+	}
+	return nil
+}
+func (e *jpCall) evaluate(ctx jpContext) any {
+	args := make([]any, 0, len(e.args))
+	for i, arg := range e.args {
+		switch e.function.params[i] {
+		case jpValueType:
+			args = append(args, arg.(jpValue).value(ctx))
+		case jpLogicalType:
+			args = append(args, arg.(jpLogical).test(ctx))
+		case jpNodesType:
+			args = append(args, arg.(jpNodes).nodes(ctx))
+		}
+	}
+	return e.function.call(args)
+}
+func (e *jpCall) test(ctx jpContext) bool {
+	b, _ := e.evaluate(ctx).(bool)
+	return b
+}
+func (e *jpCall) value(ctx jpContext) JsonValue {
+	v, _ := e.evaluate(ctx).(JsonValue)
+	return v
+}
+func (e *jpCall) nodes(ctx jpContext) []jpNode {
+	n, _ := e.evaluate(ctx).([]jpNode)
+	return n
+}
+
+func jpLength(args []any) any {
+	var length int
+	switch t := args[0].(type) {
+	case *String:
+		length = utf8.RuneCountInString(string(*t))
+	case *Array:
+		length = len(*t)
+	case *Object:
+		length = len(*t)
+	default:
+		return nil
+	}
+	n := Number(length)
+	return &n
+}
+func jpCount(args []any) any {
+	n := Number(len(args[0].([]jpNode)))
+	return &n
+}
+func jpMatch(args []any) any {
+	return jpRegexpTest(args[0], args[1], true)
+}
+func jpSearch(args []any) any {
+	return jpRegexpTest(args[0], args[1], false)
+}
+func jpValueOf(args []any) any {
+	if nodes := args[0].([]jpNode); len(nodes) == 1 {
+		return nodes[0].value
+	}
+	return nil
+}
+func jpRegexpTest(target, pattern any, full bool) bool {
+	s, ok := target.(*String)
+	if !ok {
+		return false
+	}
+	p, ok := pattern.(*String)
+	if !ok {
+		return false
+	}
+	re := jpCompileRegexp(string(*p), full)
+	return re != nil && re.MatchString(string(*s))
+}
+
+// jpCompileRegexp compiles the RFC 9485 I-Regexp as Go regexp, nil means invalid I-Regexp.
+func jpCompileRegexp(pattern string, full bool) *regexp.Regexp {
+	key := fmt.Sprintf("%t:%s", full, pattern)
+	if re, ok := jpRegexps.Load(key); ok {
+		return re.(*regexp.Regexp)
+	}
+
+	var b strings.Builder
+	inClass, valid := false, true
+	for i := 0; i < len(pattern) && valid; i++ {
+		switch c := pattern[i]; {
+		case c == '\\':
+			if i+1 >= len(pattern) || !strings.ContainsRune(`()*+-.?[\]^{|}nrtpP`, rune(pattern[i+1])) {
+				valid = false
+			} else {
+				b.WriteString(pattern[i : i+2])
+				i++
+			}
+		case inClass:
+			inClass = c != ']'
+			b.WriteByte(c)
+		case c == '[':
+			inClass = true
+			b.WriteByte(c)
+		case c == '.':
+			// I-Regexp dot does not match line terminators
+			b.WriteString(`[^\n\r]`)
+		case c == '^' || c == '$':
+			// I-Regexp has no anchors
+			b.WriteString(`\` + string(c))
+		case c == '(' && i+1 < len(pattern) && pattern[i+1] == '?':
+			valid = false
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	var re *regexp.Regexp
+	if valid {
+		translated := b.String()
+		if full {
+			translated = `\A(?:` + translated + `)\z`
+		}
+		re, _ = regexp.Compile(translated)
+	}
+	jpRegexps.Store(key, re)
+	return re
+}
+
+func (p *jpParser) errorf(format string, args ...any) error {
+	return ErrJsonPath{Query: p.query, Offset: p.pos, Reason: fmt.Sprintf(format, args...)}
+}
+func (p *jpParser) peek() byte {
+	if p.pos < len(p.query) {
+		return p.query[p.pos]
+	}
+	return 0
+}
+func (p *jpParser) blank() {
+	for p.pos < len(p.query) && strings.IndexByte(" \t\n\r", p.query[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+func jpIsDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+func jpIsAlpha(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func (p *jpParser) parseSegments() ([]jpSegment, error) {
+	segments := make([]jpSegment, 0)
+	for {
+		save := p.pos
+		p.blank()
+		if c := p.peek(); c != '.' && c != '[' {
+			p.pos = save
+			return segments, nil
+		}
+		segment, err := p.parseSegment()
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, segment)
+	}
+}
+func (p *jpParser) parseSegment() (jpSegment, error) {
+	switch {
+	case strings.HasPrefix(p.query[p.pos:], ".."):
+		p.pos += 2
+		switch p.peek() {
+		case '[':
+			selectors, err := p.parseBracketed()
+			return jpSegment{descendant: true, selectors: selectors}, err
+		case '*':
+			p.pos++
+			return jpSegment{descendant: true, selectors: []jpSelector{jpWildcardSelector{}}}, nil
+		default:
+			name, err := p.parseMemberName()
+			return jpSegment{descendant: true, selectors: []jpSelector{jpNameSelector(name)}}, err
+		}
+	case p.peek() == '.':
+		p.pos++
+		if p.peek() == '*' {
+			p.pos++
+			return jpSegment{selectors: []jpSelector{jpWildcardSelector{}}}, nil
+		}
+		name, err := p.parseMemberName()
+		return jpSegment{selectors: []jpSelector{jpNameSelector(name)}}, err
+	default:
+		selectors, err := p.parseBracketed()
+		return jpSegment{selectors: selectors}, err
+	}
+}
+func (p *jpParser) parseMemberName() (string, error) {
+	start := p.pos
+	for p.pos < len(p.query) {
+		c := p.query[p.pos]
+		if jpIsAlpha(c) || c == '_' || (p.pos > start && jpIsDigit(c)) {
+			p.pos++
+		} else if c >= utf8.RuneSelf {
+			r, size := utf8.DecodeRuneInString(p.query[p.pos:])
+			if r == utf8.RuneError && size == 1 {
+				return "", p.errorf("invalid UTF-8")
+			}
+			p.pos += size
+		} else {
+			break
+		}
+	}
+	if p.pos == start {
+		return "", p.errorf("member name expected")
+	}
+	return p.query[start:p.pos], nil
+}
+func (p *jpParser) parseBracketed() ([]jpSelector, error) {
+	p.pos++ // [
+	selectors := make([]jpSelector, 0)
+	for {
+		p.blank()
+		selector, err := p.parseSelector()
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, selector)
+		p.blank()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+			p.pos++
+			return selectors, nil
+		default:
+			return nil, p.errorf("expected , or ]")
+		}
+	}
+}
+func (p *jpParser) parseSelector() (jpSelector, error) {
+	switch c := p.peek(); {
+	case c == '\'' || c == '"':
+		name, err := p.parseStringLiteral()
+		return jpNameSelector(name), err
+	case c == '*':
+		p.pos++
+		return jpWildcardSelector{}, nil
+	case c == '?':
+		p.pos++
+		p.blank()
+		expr, err := p.parseLogicalOr()
+		if err != nil {
+			return nil, err
+		}
+		logical, err := p.toLogical(expr)
+		return jpFilterSelector{expr: logical}, err
+	case c == '-' || c == ':' || jpIsDigit(c):
+		return p.parseIndexOrSlice()
+	default:
+		return nil, p.errorf("selector expected")
+	}
+}
+func (p *jpParser) parseIndexOrSlice() (jpSelector, error) {
+	var start, end, step *int
+	if p.peek() != ':' {
+		i, err := p.parseInt()
+		if err != nil {
+			return nil, err
+		}
+		start = &i
+	}
+	p.blank()
+	if p.peek() != ':' {
+		return jpIndexSelector(*start), nil
+	}
+	p.pos++
+	p.blank()
+	if c := p.peek(); c == '-' || jpIsDigit(c) {
+		i, err := p.parseInt()
+		if err != nil {
+			return nil, err
+		}
+		end = &i
+		p.blank()
+	}
+	if p.peek() == ':' {
+		p.pos++
+		p.blank()
+		if c := p.peek(); c == '-' || jpIsDigit(c) {
+			i, err := p.parseInt()
+			if err != nil {
+				return nil, err
+			}
+			step = &i
+		}
+	}
+	return jpSliceSelector{start: start, end: end, step: step}, nil
+}
+func (p *jpParser) parseInt() (int, error) {
+	start := p.pos
+	if p.peek() == '-' {
+		p.pos++
+	}
+	switch c := p.peek(); {
+	case c == '0':
+		p.pos++
+		if p.query[start] == '-' {
+			return 0, p.errorf("-0 is not allowed")
+		} else if jpIsDigit(p.peek()) {
+			return 0, p.errorf("leading zero is not allowed")
+		}
+	case '1' <= c && c <= '9':
+		for jpIsDigit(p.peek()) {
+			p.pos++
+		}
+	default:
+		return 0, p.errorf("integer expected")
+	}
+	i, err := strconv.ParseInt(p.query[start:p.pos], 10, 64)
+	if err != nil || i < -jpMaxInt || jpMaxInt < i {
+		return 0, p.errorf("integer %s out of range", p.query[start:p.pos])
+	}
+	return int(i), nil
+}
+func (p *jpParser) parseStringLiteral() (string, error) {
+	quote := p.peek()
+	p.pos++
+	var b strings.Builder
+	for {
+		if p.pos >= len(p.query) {
+			return "", p.errorf("unterminated string")
+		}
+		switch c := p.query[p.pos]; {
+		case c == quote:
+			p.pos++
+			return b.String(), nil
+		case c == '\\':
+			p.pos++
+			e := p.peek()
+			p.pos++
+			switch e {
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case '/', '\\', quote:
+				b.WriteByte(e)
+			case 'u':
+				r, err := p.parseUnicodeEscape()
+				if err != nil {
+					return "", err
+				}
+				b.WriteRune(r)
+			default:
+				return "", p.errorf("invalid escape")
+			}
+		case c < 0x20:
+			return "", p.errorf("control character in string")
+		default:
+			r, size := utf8.DecodeRuneInString(p.query[p.pos:])
+			if r == utf8.RuneError && size == 1 {
+				return "", p.errorf("invalid UTF-8")
+			}
+			b.WriteString(p.query[p.pos : p.pos+size])
+			p.pos += size
+		}
+	}
+}
+func (p *jpParser) parseHex4() (rune, error) {
+	if p.pos+4 > len(p.query) {
+		return 0, p.errorf("invalid unicode escape")
+	}
+	hex := p.query[p.pos : p.pos+4]
+	if strings.ContainsAny(hex, "+-") {
+		return 0, p.errorf("invalid unicode escape")
+	}
+	r, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return 0, p.errorf("invalid unicode escape")
+	}
+	p.pos += 4
+	return rune(r), nil
+}
+func (p *jpParser) parseUnicodeEscape() (rune, error) {
+	r, err := p.parseHex4()
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case 0xDC00 <= r && r <= 0xDFFF:
+		return 0, p.errorf("lone low surrogate")
+	case 0xD800 <= r && r <= 0xDBFF:
+		if !strings.HasPrefix(p.query[p.pos:], `\u`) {
+			return 0, p.errorf("lone high surrogate")
+		}
+		p.pos += 2
+		low, err := p.parseHex4()
+		if err != nil {
+			return 0, err
+		} else if low < 0xDC00 || 0xDFFF < low {
+			return 0, p.errorf("invalid low surrogate")
+		}
+		return (r-0xD800)<<10 + (low - 0xDC00) + 0x10000, nil
+	default:
+		return r, nil
+	}
+}
+
+func (p *jpParser) parseLogicalOr() (jpExpr, error) {
+	return p.parseLogicalChain("||", p.parseLogicalAnd, func(ls []jpLogical) jpExpr { return jpOr(ls) })
+}
+func (p *jpParser) parseLogicalAnd() (jpExpr, error) {
+	return p.parseLogicalChain("&&", p.parseBasic, func(ls []jpLogical) jpExpr { return jpAnd(ls) })
+}
+func (p *jpParser) parseLogicalChain(op string, operand func() (jpExpr, error), build func([]jpLogical) jpExpr) (jpExpr, error) {
+	first, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	exprs := []jpExpr{first}
+	for {
+		save := p.pos
+		p.blank()
+		if !strings.HasPrefix(p.query[p.pos:], op) {
+			p.pos = save
+			break
+		}
+		p.pos += len(op)
+		p.blank()
+		next, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, next)
+	}
+	if len(exprs) == 1 {
+		return first, nil
+	}
+
+	logicals := make([]jpLogical, 0, len(exprs))
+	for _, e := range exprs {
+		l, err := p.toLogical(e)
+		if err != nil {
+			return nil, err
+		}
+		logicals = append(logicals, l)
+	}
+	return build(logicals), nil
+}
+func (p *jpParser) parseBasic() (jpExpr, error) {
+	switch p.peek() {
+	case '!':
+		p.pos++
+		p.blank()
+		if p.peek() == '(' {
+			inner, err := p.parseParen()
+			return jpNot{expr: inner}, err
+		}
+		e, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		logical, err := p.toLogical(e)
+		if err != nil {
+			return nil, err
+		}
+		save := p.pos
+		p.blank()
+		if p.comparisonOp() != "" {
+			return nil, p.errorf("comparison cannot be negated without parentheses")
+		}
+		p.pos = save
+		return jpNot{expr: logical}, nil
+	case '(':
+		return p.parseParen()
+	default:
+		left, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		save := p.pos
+		p.blank()
+		op := p.comparisonOp()
+		if op == "" {
+			p.pos = save
+			return left, nil
+		}
+		p.pos += len(op)
+		p.blank()
+		right, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		l, err := p.toComparable(left)
+		if err != nil {
+			return nil, err
+		}
+		r, err := p.toComparable(right)
+		if err != nil {
+			return nil, err
+		}
+		return jpComparison{op: op, left: l, right: r}, nil
+	}
+}
+func (p *jpParser) comparisonOp() string {
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if strings.HasPrefix(p.query[p.pos:], op) {
+			return op
+		}
+	}
+	return ""
+}
+func (p *jpParser) parseParen() (jpLogical, error) {
+	p.pos++ // (
+	p.blank()
+	inner, err := p.parseLogicalOr()
+	if err != nil {
+		return nil, err
+	}
+	p.blank()
+	if p.peek() != ')' {
+		return nil, p.errorf("expected )")
+	}
+	p.pos++
+	return p.toLogical(inner)
+}
+func (p *jpParser) parsePrimary() (jpExpr, error) {
+	switch c := p.peek(); {
+	case c == '@' || c == '$':
+		p.pos++
+		segments, err := p.parseSegments()
+		if err != nil {
+			return nil, err
+		}
+		singular := true
+		for _, s := range segments {
+			if s.descendant || len(s.selectors) != 1 {
+				singular = false
+			} else {
+				switch s.selectors[0].(type) {
+				case jpNameSelector, jpIndexSelector:
+				default:
+					singular = false
+				}
+			}
+		}
+		return &jpQuery{relative: c == '@', singular: singular, segments: segments}, nil
+	case c == '\'' || c == '"':
+		s, err := p.parseStringLiteral()
+		str := String(s)
+		return jpLiteral{literal: &str}, err
+	case c == '-' || jpIsDigit(c):
+		return p.parseNumberLiteral()
+	case 'a' <= c && c <= 'z':
+		start := p.pos
+		for c := p.peek(); ('a' <= c && c <= 'z') || jpIsDigit(c) || c == '_'; c = p.peek() {
+			p.pos++
+		}
+		name := p.query[start:p.pos]
+		if p.peek() == '(' {
+			return p.parseCall(name)
+		}
+		switch name {
+		case "true", "false":
+			b := Bool(name == "true")
+			return jpLiteral{literal: &b}, nil
+		case "null":
+			return jpLiteral{literal: new(Null)}, nil
+		default:
+			p.pos = start
+			return nil, p.errorf("unexpected %s", name)
+		}
+	default:
+		return nil, p.errorf("expression expected")
+	}
+}
+func (p *jpParser) parseNumberLiteral() (jpExpr, error) {
+	start := p.pos
+	if p.peek() == '-' {
+		p.pos++
+	}
+	switch c := p.peek(); {
+	case c == '0':
+		p.pos++
+	case '1' <= c && c <= '9':
+		for jpIsDigit(p.peek()) {
+			p.pos++
+		}
+	default:
+		return nil, p.errorf("number expected")
+	}
+	if p.peek() == '.' {
+		p.pos++
+		if !jpIsDigit(p.peek()) {
+			return nil, p.errorf("fraction expected")
+		}
+		for jpIsDigit(p.peek()) {
+			p.pos++
+		}
+	}
+	if c := p.peek(); c == 'e' || c == 'E' {
+		p.pos++
+		if c := p.peek(); c == '+' || c == '-' {
+			p.pos++
+		}
+		if !jpIsDigit(p.peek()) {
+			return nil, p.errorf("exponent expected")
+		}
+		for jpIsDigit(p.peek()) {
+			p.pos++
+		}
+	}
+	f, err := strconv.ParseFloat(p.query[start:p.pos], 64)
+	if err != nil {
+		return nil, p.errorf("invalid number %s", p.query[start:p.pos])
+	}
+	n := Number(f)
+	return jpLiteral{literal: &n}, nil
+}
+func (p *jpParser) parseCall(name string) (jpExpr, error) {
+	function, ok := jpFunctions[name]
+	if !ok {
+		return nil, p.errorf("unknown function %s", name)
+	}
+	p.pos++ // (
+	p.blank()
+	exprs := make([]jpExpr, 0)
+	if p.peek() != ')' {
+		for {
+			arg, err := p.parseLogicalOr()
+			if err != nil {
+				return nil, err
+			}
+			exprs = append(exprs, arg)
+			p.blank()
+			if p.peek() != ',' {
+				break
+			}
+			p.pos++
+			p.blank()
+		}
+	}
+	if p.peek() != ')' {
+		return nil, p.errorf("expected )")
+	}
+	p.pos++
+
+	if len(exprs) != len(function.params) {
+		return nil, p.errorf("function %s takes %d arguments, got %d", name, len(function.params), len(exprs))
+	}
+	args := make([]any, 0, len(exprs))
+	for i, e := range exprs {
+		arg, err := p.toArgument(e, function.params[i])
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	return &jpCall{name: name, function: function, args: args}, nil
+}
+
+func (p *jpParser) toLogical(e jpExpr) (jpLogical, error) {
+	switch t := e.(type) {
+	case *jpQuery:
+		return jpExists{nodelist: t}, nil
+	case *jpCall:
+		switch t.function.result {
+		case jpLogicalType:
+			return t, nil
+		case jpNodesType:
+			return jpExists{nodelist: t}, nil
+		default:
+			return nil, p.errorf("result of function %s must be compared", t.name)
+		}
+	case jpLiteral:
+		return nil, p.errorf("literal must be compared")
+	case jpLogical:
+		return t, nil
+	default:
+		return nil, p.errorf("logical expression expected")
+	}
+}
+func (p *jpParser) toComparable(e jpExpr) (jpValue, error) {
+	switch t := e.(type) {
+	case jpLiteral:
+		return t, nil
+	case *jpQuery:
+		if !t.singular {
+			return nil, p.errorf("non-singular query is not comparable")
+		}
+		return t, nil
+	case *jpCall:
+		if t.function.result != jpValueType {
+			return nil, p.errorf("result of function %s is not comparable", t.name)
+		}
+		return t, nil
+	default:
+		return nil, p.errorf("comparable expected")
+	}
+}
+func (p *jpParser) toArgument(e jpExpr, param jpType) (any, error) {
+	switch param {
+	case jpValueType:
+		return p.toComparable(e)
+	case jpNodesType:
+		switch t := e.(type) {
+		case *jpQuery:
+			return t, nil
+		case *jpCall:
+			if t.function.result == jpNodesType {
+				return t, nil
+			}
+		}
+		return nil, p.errorf("nodes expected")
+	default:
+		return p.toLogical(e)
+	}
+}
@@ -0,0 +1,485 @@
+package fluffyjson_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"testing"
+
+	fluffyjson "github.com/hayas1/go-fluffy-json"
+)
+
+const TestBookstore = `{
+	"store": {
+		"book": [
+			{"category": "reference", "author": "Nigel Rees", "title": "Sayings of the Century", "price": 8.95},
+			{"category": "fiction", "author": "Evelyn Waugh", "title": "Sword of Honour", "price": 12.99},
+			{"category": "fiction", "author": "Herman Melville", "title": "Moby Dick", "isbn": "0-553-21311-3", "price": 8.99},
+			{"category": "fiction", "author": "J. R. R. Tolkien", "title": "The Lord of the Rings", "isbn": "0-395-19395-8", "price": 22.99}
+		],
+		"bicycle": {"color": "red", "price": 399}
+	}
+}`
+
+func ExampleJsonPath_Query() {
+	var value fluffyjson.RootValue
+	if err := json.Unmarshal([]byte(TestBookstore), &value); err != nil {
+		panic(err)
+	}
+
+	path, err := fluffyjson.ParseJsonPath(`$.store.book[?@.price < 10].title`)
+	if err != nil {
+		panic(err)
+	}
+	for pointer, title := range path.Query(&value) {
+		normalized, err := pointer.NormalizedPath()
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(normalized, *title.(*fluffyjson.String))
+	}
+	// Output:
+	// $['store']['book'][0]['title'] Sayings of the Century
+	// $['store']['book'][2]['title'] Moby Dick
+}
+
+func TestJsonPathQuery(t *testing.T) {
+	const filterTarget = `{
+		"a": [3, 5, 1, 2, 4, 6, {"b": "j"}, {"b": "k"}, {"b": {}}, {"b": "kilo"}],
+		"o": {"p": 1, "q": 2, "r": 3, "s": 5, "t": {"u": 6}},
+		"e": "f"
+	}`
+	const sliceTarget = `["a", "b", "c", "d", "e", "f", "g"]`
+
+	testcases := map[string]struct {
+		target   string
+		query    string
+		expected []string
+	}{
+		"root": {
+			target:   `{"k": "v"}`,
+			query:    `$`,
+			expected: []string{`$`},
+		},
+		"authors of all books": {
+			target:   TestBookstore,
+			query:    `$.store.book[*].author`,
+			expected: []string{`$['store']['book'][0]['author']`, `$['store']['book'][1]['author']`, `$['store']['book'][2]['author']`, `$['store']['book'][3]['author']`},
+		},
+		"all authors": {
+			target:   TestBookstore,
+			query:    `$..author`,
+			expected: []string{`$['store']['book'][0]['author']`, `$['store']['book'][1]['author']`, `$['store']['book'][2]['author']`, `$['store']['book'][3]['author']`},
+		},
+		"all things in store": {
+			target:   TestBookstore,
+			query:    `$.store.*`,
+			expected: []string{`$['store']['bicycle']`, `$['store']['book']`},
+		},
+		"prices of everything": {
+			target:   TestBookstore,
+			query:    `$.store..price`,
+			expected: []string{`$['store']['bicycle']['price']`, `$['store']['book'][0]['price']`, `$['store']['book'][1]['price']`, `$['store']['book'][2]['price']`, `$['store']['book'][3]['price']`},
+		},
+		"third book": {
+			target:   TestBookstore,
+			query:    `$..book[2]`,
+			expected: []string{`$['store']['book'][2]`},
+		},
+		"missing member": {
+			target:   TestBookstore,
+			query:    `$..book[2].publisher`,
+			expected: []string{},
+		},
+		"last book": {
+			target:   TestBookstore,
+			query:    `$..book[-1]`,
+			expected: []string{`$['store']['book'][3]`},
+		},
+		"first two books": {
+			target:   TestBookstore,
+			query:    `$..book[0,1]`,
+			expected: []string{`$['store']['book'][0]`, `$['store']['book'][1]`},
+		},
+		"books with isbn": {
+			target:   TestBookstore,
+			query:    `$..book[?@.isbn]`,
+			expected: []string{`$['store']['book'][2]`, `$['store']['book'][3]`},
+		},
+		"cheap books": {
+			target:   TestBookstore,
+			query:    `$..book[?@.price<10]`,
+			expected: []string{`$['store']['book'][0]`, `$['store']['book'][2]`},
+		},
+		"name selector with quotes": {
+			target:   `{"o": {"j j": {"k.k": 3}}, "'": {"@": 2}}`,
+			query:    `$.o['j j']["k.k"]`,
+			expected: []string{`$['o']['j j']['k.k']`},
+		},
+		"escaped name selector": {
+			target:   `{"o": {"j j": {"k.k": 3}}, "'": {"@": 2}}`,
+			query:    `$["'"]["@"]`,
+			expected: []string{`$['\'']['@']`},
+		},
+		"whitespace": {
+			target:   `{"a": [1, 2]}`,
+			query:    `$ [ 'a' ] [ 0 , 1 ]`,
+			expected: []string{`$['a'][0]`, `$['a'][1]`},
+		},
+		"index": {
+			target:   `["a", "b"]`,
+			query:    `$[1]`,
+			expected: []string{`$[1]`},
+		},
+		"negative index": {
+			target:   `["a", "b"]`,
+			query:    `$[-2]`,
+			expected: []string{`$[0]`},
+		},
+		"out of range index": {
+			target:   `["a", "b"]`,
+			query:    `$[2]`,
+			expected: []string{},
+		},
+		"slice": {
+			target:   sliceTarget,
+			query:    `$[1:3]`,
+			expected: []string{`$[1]`, `$[2]`},
+		},
+		"slice without end": {
+			target:   sliceTarget,
+			query:    `$[5:]`,
+			expected: []string{`$[5]`, `$[6]`},
+		},
+		"slice with step": {
+			target:   sliceTarget,
+			query:    `$[1:5:2]`,
+			expected: []string{`$[1]`, `$[3]`},
+		},
+		"slice with negative step": {
+			target:   sliceTarget,
+			query:    `$[5:1:-2]`,
+			expected: []string{`$[5]`, `$[3]`},
+		},
+		"reverse slice": {
+			target:   sliceTarget,
+			query:    `$[::-1]`,
+			expected: []string{`$[6]`, `$[5]`, `$[4]`, `$[3]`, `$[2]`, `$[1]`, `$[0]`},
+		},
+		"zero step slice": {
+			target:   sliceTarget,
+			query:    `$[::0]`,
+			expected: []string{},
+		},
+		"descendant": {
+			target:   `{"o": {"j": 1, "k": 2}, "a": [5, 3, [{"j": 4}, {"k": 6}]]}`,
+			query:    `$..j`,
+			expected: []string{`$['a'][2][0]['j']`, `$['o']['j']`},
+		},
+		"descendant index": {
+			target:   `{"o": {"j": 1, "k": 2}, "a": [5, 3, [{"j": 4}, {"k": 6}]]}`,
+			query:    `$..[0]`,
+			expected: []string{`$['a'][0]`, `$['a'][2][0]`},
+		},
+		"filter equal string": {
+			target:   filterTarget,
+			query:    `$.a[?@.b == 'kilo']`,
+			expected: []string{`$['a'][9]`},
+		},
+		"filter parenthesized": {
+			target:   filterTarget,
+			query:    `$.a[?(@.b == 'kilo')]`,
+			expected: []string{`$['a'][9]`},
+		},
+		"filter greater": {
+			target:   filterTarget,
+			query:    `$.a[?@>3.5]`,
+			expected: []string{`$['a'][1]`, `$['a'][4]`, `$['a'][5]`},
+		},
+		"filter existence": {
+			target:   filterTarget,
+			query:    `$.a[?@.b]`,
+			expected: []string{`$['a'][6]`, `$['a'][7]`, `$['a'][8]`, `$['a'][9]`},
+		},
+		"filter existence of children": {
+			target:   filterTarget,
+			query:    `$[?@.*]`,
+			expected: []string{`$['a']`, `$['o']`},
+		},
+		"nested filter": {
+			target:   filterTarget,
+			query:    `$[?@[?@.b]]`,
+			expected: []string{`$['a']`},
+		},
+		"multiple filters": {
+			target:   filterTarget,
+			query:    `$.o[?@<3, ?@<3]`,
+			expected: []string{`$['o']['p']`, `$['o']['q']`, `$['o']['p']`, `$['o']['q']`},
+		},
+		"filter or": {
+			target:   filterTarget,
+			query:    `$.a[?@<2 || @.b == "k"]`,
+			expected: []string{`$['a'][2]`, `$['a'][7]`},
+		},
+		"filter and": {
+			target:   filterTarget,
+			query:    `$.o[?@>1 && @<4]`,
+			expected: []string{`$['o']['q']`, `$['o']['r']`},
+		},
+		"filter or existence": {
+			target:   filterTarget,
+			query:    `$.o[?@.u || @.x]`,
+			expected: []string{`$['o']['t']`},
+		},
+		"filter not": {
+			target:   filterTarget,
+			query:    `$.o[?!(@ <= 3)]`,
+			expected: []string{`$['o']['s']`, `$['o']['t']`},
+		},
+		"filter nothing equals nothing": {
+			target:   filterTarget,
+			query:    `$.a[?@.b == $.x]`,
+			expected: []string{`$['a'][0]`, `$['a'][1]`, `$['a'][2]`, `$['a'][3]`, `$['a'][4]`, `$['a'][5]`},
+		},
+		"filter self equals": {
+			target:   filterTarget,
+			query:    `$.a[?@ == @]`,
+			expected: []string{`$['a'][0]`, `$['a'][1]`, `$['a'][2]`, `$['a'][3]`, `$['a'][4]`, `$['a'][5]`, `$['a'][6]`, `$['a'][7]`, `$['a'][8]`, `$['a'][9]`},
+		},
+		"filter structural equals": {
+			target:   `[{"a": [1, {"b": null}]}, {"a": [1, {"b": false}]}]`,
+			query:    `$[?@.a == $[0].a]`,
+			expected: []string{`$[0]`},
+		},
+		"filter match": {
+			target:   filterTarget,
+			query:    `$.a[?match(@.b, "[jk]")]`,
+			expected: []string{`$['a'][6]`, `$['a'][7]`},
+		},
+		"filter search": {
+			target:   filterTarget,
+			query:    `$.a[?search(@.b, "[jk]")]`,
+			expected: []string{`$['a'][6]`, `$['a'][7]`, `$['a'][9]`},
+		},
+		"filter match dot": {
+			target:   `["a\nb", "a-b"]`,
+			query:    `$[?match(@, 'a.b')]`,
+			expected: []string{`$[1]`},
+		},
+		"filter length": {
+			target:   `["a", "ab", "abc", [1, 2], {"k": 1}, 1]`,
+			query:    `$[?length(@) < 3]`,
+			expected: []string{`$[0]`, `$[1]`, `$[3]`, `$[4]`},
+		},
+		"filter count": {
+			target:   `[[1], [1, 2], {"k": 1}, 1]`,
+			query:    `$[?count(@.*) == 1]`,
+			expected: []string{`$[0]`, `$[2]`},
+		},
+		"filter value": {
+			target:   TestBookstore,
+			query:    `$.store[?value(@..color) == "red"]`,
+			expected: []string{`$['store']['bicycle']`},
+		},
+		"filter literals": {
+			target:   `[true, false, null, 1, -1.5e1, "é"]`,
+			query:    `$[?@ == true || @ == null || @ == -15 || @ == 'é']`,
+			expected: []string{`$[0]`, `$[2]`, `$[4]`, `$[5]`},
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			value := HelperUnmarshalValue(t, tc.target)
+			path, err := fluffyjson.ParseJsonPath(tc.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			actual := make([]string, 0)
+			for pointer, v := range path.Query(&value) {
+				normalized, err := pointer.NormalizedPath()
+				if err != nil {
+					t.Fatal(err)
+				}
+				accessed, err := value.Access(pointer)
+				HelperFatalEvaluateError(t, v, accessed, nil, err)
+				actual = append(actual, normalized)
+			}
+			HelperFatalEvaluate(t, tc.expected, actual)
+		})
+	}
+}
+
+// The cases are a subset transcribed by hand from https://github.com/jsonpath-standard/jsonpath-compliance-test-suite,
+// not the upstream cts.json, so passing them does not show conformance to RFC 9535 (see testdata/jsonpath-compliance-test-suite/README.md).
+func TestJsonPathCtsSubset(t *testing.T) {
+	// skipped are the cases that this implementation does not follow, with the reasons
+	skipped := map[string]string{}
+
+	b, err := os.ReadFile("testdata/jsonpath-compliance-test-suite/cts.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var suite struct {
+		Tests []struct {
+			Name         string            `json:"name"`
+			Selector     string            `json:"selector"`
+			Document     json.RawMessage   `json:"document"`
+			Result       json.RawMessage   `json:"result"`
+			Results      []json.RawMessage `json:"results"`
+			ResultPaths  []string          `json:"result_paths"`
+			ResultsPaths [][]string        `json:"results_paths"`
+			Invalid      bool              `json:"invalid_selector"`
+		} `json:"tests"`
+	}
+	if err := json.Unmarshal(b, &suite); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range suite.Tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			if reason, ok := skipped[tc.Name]; ok {
+				t.Skip(reason)
+			}
+			path, err := fluffyjson.ParseJsonPath(tc.Selector)
+			if tc.Invalid {
+				if !errors.As(err, &fluffyjson.ErrJsonPath{}) {
+					t.Fatalf("%s must be invalid, but %v", tc.Selector, err)
+				}
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			document := HelperUnmarshalValue(t, string(tc.Document))
+			values, paths := make([]fluffyjson.JsonValue, 0), make([]string, 0)
+			for pointer, v := range path.Query(&document) {
+				normalized, err := pointer.NormalizedPath()
+				if err != nil {
+					t.Fatal(err)
+				}
+				values, paths = append(values, v), append(paths, normalized)
+			}
+			nodes := fluffyjson.Array(values)
+			actual := fluffyjson.RootValue{JsonValue: &nodes}
+
+			results, resultsPaths := tc.Results, tc.ResultsPaths
+			if tc.Result != nil {
+				results, resultsPaths = []json.RawMessage{tc.Result}, [][]string{tc.ResultPaths}
+			}
+			for i, result := range results {
+				expected := HelperUnmarshalValue(t, string(result))
+				if fluffyjson.Equal(&expected, &actual) && (i >= len(resultsPaths) || resultsPaths[i] == nil || slices.Equal(resultsPaths[i], paths)) {
+					return
+				}
+			}
+			t.Fatalf("%s got %s at %v", tc.Selector, HelperMarshalValue(t, actual), paths)
+		})
+	}
+}
+
+func TestJsonPathDescendantOrder(t *testing.T) {
+	value := HelperUnmarshalValue(t, `{"o": {"j": 1}, "a": [5, [6]]}`)
+	path, err := fluffyjson.ParseJsonPath(`$..*`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual := make([]string, 0)
+	for pointer := range path.Query(&value) {
+		normalized, err := pointer.NormalizedPath()
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual = append(actual, normalized)
+	}
+	HelperFatalEvaluate(t, []string{`$['a']`, `$['o']`, `$['a'][0]`, `$['a'][1]`, `$['a'][1][0]`, `$['o']['j']`}, actual)
+}
+
+func TestJsonPathSyntax(t *testing.T) {
+	testcases := map[string]struct {
+		query string
+		valid bool
+	}{
+		"no root":                        {query: `.a`, valid: false},
+		"trailing dot":                   {query: `$.a.`, valid: false},
+		"trailing whitespace":            {query: `$.a `, valid: false},
+		"leading whitespace":             {query: ` $.a`, valid: false},
+		"dangling descendant":            {query: `$..`, valid: false},
+		"leading zero":                   {query: `$[01]`, valid: false},
+		"negative zero":                  {query: `$[-0]`, valid: false},
+		"too large index":                {query: `$[9007199254740992]`, valid: false},
+		"max index":                      {query: `$[9007199254740991]`, valid: true},
+		"unclosed bracket":               {query: `$['a'`, valid: false},
+		"empty bracket":                  {query: `$[]`, valid: false},
+		"too many colons":                {query: `$[1:2:3:4]`, valid: false},
+		"lone surrogate":                 {query: `$["\uD800"]`, valid: false},
+		"surrogate pair":                 {query: `$["𝄞"]`, valid: true},
+		"invalid escape":                 {query: `$['\"']`, valid: false},
+		"control character":              {query: "$['\u0001']", valid: false},
+		"whitespace in name shorthand":   {query: `$. a`, valid: false},
+		"digit first member name":        {query: `$.1a`, valid: false},
+		"unicode member name":            {query: `$.☺`, valid: true},
+		"literal as test":                {query: `$[?1]`, valid: false},
+		"literal in logical":             {query: `$[?@.a && true]`, valid: false},
+		"negated comparison":             {query: `$[?!@.a == 1]`, valid: false},
+		"non singular comparison":        {query: `$[?@.* == 1]`, valid: false},
+		"length of singular":             {query: `$[?length(@) < 3]`, valid: true},
+		"length of non singular":         {query: `$[?length(@.*) < 3]`, valid: false},
+		"count of nodes":                 {query: `$[?count(@.*) == 1]`, valid: true},
+		"count of literal":               {query: `$[?count(1) == 1]`, valid: false},
+		"unknown function":               {query: `$[?count(foo(@.*)) == 1]`, valid: false},
+		"match as test":                  {query: `$[?match(@.timezone, 'Europe/.*')]`, valid: true},
+		"match compared":                 {query: `$[?match(@.timezone, 'Europe/.*') == true]`, valid: false},
+		"value compared":                 {query: `$[?value(@..color) == "red"]`, valid: true},
+		"value as test":                  {query: `$[?value(@..color)]`, valid: false},
+		"wrong number of arguments":      {query: `$[?length(@, @)]`, valid: false},
+		"whitespace before parenthesis":  {query: `$[?length (@) < 3]`, valid: false},
+		"function in logical argument":   {query: `$[?match(@.a, 'a') && search(@.b, 'b')]`, valid: true},
+		"absolute query in filter":       {query: `$[?$.a]`, valid: true},
+		"relative query outside filter":  {query: `@.a`, valid: false},
+		"comparison without right":       {query: `$[?@.a ==]`, valid: false},
+		"dangling logical operator":      {query: `$[?@.a &&]`, valid: false},
+		"number literal with fraction":   {query: `$[?@.a == 1.5e-3]`, valid: true},
+		"number literal without integer": {query: `$[?@.a == .5]`, valid: false},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			_, err := fluffyjson.ParseJsonPath(tc.query)
+			if tc.valid && err != nil {
+				t.Fatal(err)
+			} else if !tc.valid && !errors.As(err, &fluffyjson.ErrJsonPath{}) {
+				t.Fatalf("%s must be invalid, but %v", tc.query, err)
+			}
+		})
+	}
+}
+
+func TestNormalizedPath(t *testing.T) {
+	testcases := map[string]struct {
+		pointer  fluffyjson.Pointer
+		expected string
+	}{
+		"root": {
+			pointer:  nil,
+			expected: `$`,
+		},
+		"key and index": {
+			pointer:  fluffyjson.Pointer{fluffyjson.KeyAccess("a"), fluffyjson.IndexAccess(1)},
+			expected: `$['a'][1]`,
+		},
+		"escape": {
+			pointer:  fluffyjson.Pointer{fluffyjson.KeyAccess("'\\\n\u000b")},
+			expected: `$['\'\\\n\u000b']`,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := tc.pointer.NormalizedPath()
+			HelperFatalEvaluateError(t, tc.expected, actual, nil, err)
+		})
+	}
+}
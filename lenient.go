@@ -0,0 +1,212 @@
+package fluffyjson
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type (
+	// Lenient wraps the value to coerce it into the requested kind instead of failing with [ErrAsValue].
+	// It is not a [JsonValue] itself, so the coerced view cannot be mistaken for the actual value. Nil value is regarded as null.
+	// The coercion rules are:
+	//   - object: null is coerced into empty object.
+	//   - array: null is coerced into empty array, and any other value into one-element array.
+	//   - string: number is formatted in the shortest decimal form, bool into "true" or "false", and null into "".
+	//   - number: string is parsed as finite decimal after trimming spaces, which rejects hexadecimal such as "0x1p3",
+	//     bool into 1 or 0, and null into 0.
+	//   - bool: string is parsed by [strconv.ParseBool], number is true unless it is 0, and null is false.
+	//   - null: only null itself.
+	Lenient struct {
+		Value JsonValue
+	}
+
+	// ErrCoerce is returned when [Lenient] value cannot be coerced into the expected kind.
+	ErrCoerce struct {
+		Expected representation
+		Actual   representation
+		Value    string
+	}
+)
+
+func (e ErrCoerce) Error() string {
+	if e.Value == "" {
+		return fmt.Sprintf("cannot coerce %s into %s", e.Actual, e.Expected)
+	}
+	return fmt.Sprintf("cannot coerce %s %s into %s", e.Actual, e.Value, e.Expected)
+}
+func (e ErrCoerce) Is(target error) bool {
+	_, ok := target.(ErrCoerce)
+	return ok
+}
+
+// value returns the wrapped value, where nil is regarded as null.
+func (l Lenient) value() JsonValue {
+	if v := unwrapRoot(l.Value); v != nil {
+		return v
+	}
+	return &Null{}
+}
+
+func (l Lenient) AsObject() (Object, error) {
+	switch v := l.value().(type) {
+	case *Object:
+		return *v, nil
+	case *Null:
+		return Object{}, nil
+	default:
+		return nil, ErrCoerce{Expected: OBJECT, Actual: v.representation()}
+	}
+}
+func (l Lenient) AsArray() (Array, error) {
+	switch v := l.value().(type) {
+	case *Array:
+		return *v, nil
+	case *Null:
+		return Array{}, nil
+	default:
+		return Array{v}, nil
+	}
+}
+func (l Lenient) AsString() (String, error) {
+	switch v := l.value().(type) {
+	case *String:
+		return *v, nil
+	case *Number:
+		return String(strconv.FormatFloat(float64(*v), 'f', -1, 64)), nil
+	case *Bool:
+		return String(strconv.FormatBool(bool(*v))), nil
+	case *Null:
+		return "", nil
+	default:
+		return "", ErrCoerce{Expected: STRING, Actual: v.representation()}
+	}
+}
+func (l Lenient) AsNumber() (Number, error) {
+	switch v := l.value().(type) {
+	case *Number:
+		return *v, nil
+	case *String:
+		// ParseFloat also accepts hexadecimal with underscores, which are not decimal
+		s := strings.TrimSpace(string(*v))
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil || math.IsInf(n, 0) || math.IsNaN(n) || strings.ContainsAny(s, "xX") {
+			return 0, ErrCoerce{Expected: NUMBER, Actual: STRING, Value: strconv.Quote(string(*v))}
+		}
+		return Number(n), nil
+	case *Bool:
+		if *v {
+			return 1, nil
+		}
+		return 0, nil
+	case *Null:
+		return 0, nil
+	default:
+		return 0, ErrCoerce{Expected: NUMBER, Actual: v.representation()}
+	}
+}
+func (l Lenient) AsBool() (Bool, error) {
+	switch v := l.value().(type) {
+	case *Bool:
+		return *v, nil
+	case *String:
+		b, err := strconv.ParseBool(strings.TrimSpace(string(*v)))
+		if err != nil {
+			return false, ErrCoerce{Expected: BOOL, Actual: STRING, Value: strconv.Quote(string(*v))}
+		}
+		return Bool(b), nil
+	case *Number:
+		return *v != 0, nil
+	case *Null:
+		return false, nil
+	default:
+		return false, ErrCoerce{Expected: BOOL, Actual: v.representation()}
+	}
+}
+func (l Lenient) AsNull() (Null, error) {
+	switch v := l.value().(type) {
+	case *Null:
+		return *v, nil
+	default:
+		return Null{}, ErrCoerce{Expected: NULL, Actual: v.representation()}
+	}
+}
+func (l Lenient) AsInt() (int, error)       { return asInt(l.AsNumber()) }
+func (l Lenient) AsInt64() (int64, error)   { return asInt64(l.AsNumber()) }
+func (l Lenient) AsInt32() (int32, error)   { return asInt32(l.AsNumber()) }
+func (l Lenient) AsUint64() (uint64, error) { return asUint64(l.AsNumber()) }
+
+func (l Lenient) AccessAsObject(ptr ...Accessor) (Object, error) {
+	return lenientAccess(l.value(), ptr, Lenient.AsObject)
+}
+func (l Lenient) AccessAsArray(ptr ...Accessor) (Array, error) {
+	return lenientAccess(l.value(), ptr, Lenient.AsArray)
+}
+func (l Lenient) AccessAsString(ptr ...Accessor) (String, error) {
+	return lenientAccess(l.value(), ptr, Lenient.AsString)
+}
+func (l Lenient) AccessAsNumber(ptr ...Accessor) (Number, error) {
+	return lenientAccess(l.value(), ptr, Lenient.AsNumber)
+}
+func (l Lenient) AccessAsBool(ptr ...Accessor) (Bool, error) {
+	return lenientAccess(l.value(), ptr, Lenient.AsBool)
+}
+func (l Lenient) AccessAsNull(ptr ...Accessor) (Null, error) {
+	return lenientAccess(l.value(), ptr, Lenient.AsNull)
+}
+func (l Lenient) AccessAsInt(ptr ...Accessor) (int, error) {
+	return lenientAccess(l.value(), ptr, Lenient.AsInt)
+}
+func (l Lenient) AccessAsInt64(ptr ...Accessor) (int64, error) {
+	return lenientAccess(l.value(), ptr, Lenient.AsInt64)
+}
+func (l Lenient) AccessAsInt32(ptr ...Accessor) (int32, error) {
+	return lenientAccess(l.value(), ptr, Lenient.AsInt32)
+}
+func (l Lenient) AccessAsUint64(ptr ...Accessor) (uint64, error) {
+	return lenientAccess(l.value(), ptr, Lenient.AsUint64)
+}
+func (l Lenient) SliceAsObject(acc SliceAccessor) ([]Object, error) {
+	return lenientSlice(l.value(), acc, Lenient.AsObject)
+}
+func (l Lenient) SliceAsArray(acc SliceAccessor) ([]Array, error) {
+	return lenientSlice(l.value(), acc, Lenient.AsArray)
+}
+func (l Lenient) SliceAsString(acc SliceAccessor) ([]String, error) {
+	return lenientSlice(l.value(), acc, Lenient.AsString)
+}
+func (l Lenient) SliceAsNumber(acc SliceAccessor) ([]Number, error) {
+	return lenientSlice(l.value(), acc, Lenient.AsNumber)
+}
+func (l Lenient) SliceAsBool(acc SliceAccessor) ([]Bool, error) {
+	return lenientSlice(l.value(), acc, Lenient.AsBool)
+}
+func (l Lenient) SliceAsNull(acc SliceAccessor) ([]Null, error) {
+	return lenientSlice(l.value(), acc, Lenient.AsNull)
+}
+
+func lenientAccess[T any](v JsonValue, ptr []Accessor, as func(Lenient) (T, error)) (T, error) {
+	v, err := Pointer(ptr).Accessing(v)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	t, err := as(Lenient{Value: v})
+	return t, prefixed(ptr, err)
+}
+func lenientSlice[T any](v JsonValue, acc SliceAccessor, as func(Lenient) (T, error)) ([]T, error) {
+	vs, err := acc.Slicing(unwrapRoot(v))
+	if err != nil {
+		return nil, err
+	}
+	slice := make([]T, 0, len(vs))
+	for _, v := range vs {
+		t, err := as(Lenient{Value: v})
+		if err != nil {
+			return nil, err
+		}
+		slice = append(slice, t)
+	}
+	return slice, nil
+}
@@ -0,0 +1,178 @@
+package fluffyjson_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	fluffyjson "github.com/hayas1/go-fluffy-json"
+)
+
+func ExampleLenient() {
+	var value fluffyjson.RootValue
+	if err := json.Unmarshal([]byte(`{"count": "42", "enabled": 1, "tags": "solo"}`), &value); err != nil {
+		panic(err)
+	}
+
+	lenient := fluffyjson.Lenient{Value: &value}
+	count, _ := lenient.AccessAsNumber(fluffyjson.KeyAccess("count"))
+	enabled, _ := lenient.AccessAsBool(fluffyjson.KeyAccess("enabled"))
+	tags, _ := lenient.AccessAsArray(fluffyjson.KeyAccess("tags"))
+	fmt.Println(count, enabled, len(tags))
+	// Output: 42 true 1
+}
+
+func TestLenient(t *testing.T) {
+	testcases := map[string]struct {
+		target   string
+		as       func(l fluffyjson.Lenient) (any, error)
+		expected any
+		err      error
+	}{
+		"object from object": {
+			target:   `{"a": 1}`,
+			as:       func(l fluffyjson.Lenient) (any, error) { return l.AsObject() },
+			expected: fluffyjson.Object{"a": HelperCastNumber(t, 1)},
+		},
+		"object from null": {
+			target:   `null`,
+			as:       func(l fluffyjson.Lenient) (any, error) { return l.AsObject() },
+			expected: fluffyjson.Object{},
+		},
+		"object from array": {
+			target:   `[]`,
+			as:       func(l fluffyjson.Lenient) (any, error) { return l.AsObject() },
+			expected: fluffyjson.Object(nil),
+			err:      fluffyjson.ErrCoerce{},
+		},
+		"array from scalar": {
+			target:   `"a"`,
+			as:       func(l fluffyjson.Lenient) (any, error) { return l.AsArray() },
+			expected: fluffyjson.Array{HelperCastString(t, "a")},
+		},
+		"array from null": {
+			target:   `null`,
+			as:       func(l fluffyjson.Lenient) (any, error) { return l.AsArray() },
+			expected: fluffyjson.Array{},
+		},
+		"string from number": {
+			target:   `1e21`,
+			as:       func(l fluffyjson.Lenient) (any, error) { return l.AsString() },
+			expected: fluffyjson.String("1000000000000000000000"),
+		},
+		"string from fraction": {
+			target:   `-0.25`,
+			as:       func(l fluffyjson.Lenient) (any, error) { return l.AsString() },
+			expected: fluffyjson.String("-0.25"),
+		},
+		"string from bool": {
+			target:   `false`,
+			as:       func(l fluffyjson.Lenient) (any, error) { return l.AsString() },
+			expected: fluffyjson.String("false"),
+		},
+		"string from object": {
+			target:   `{}`,
+			as:       func(l fluffyjson.Lenient) (any, error) { return l.AsString() },
+			expected: fluffyjson.String(""),
+			err:      fluffyjson.ErrCoerce{},
+		},
+		"number from string": {
+			target:   `" 4.5 "`,
+			as:       func(l fluffyjson.Lenient) (any, error) { return l.AsNumber() },
+			expected: fluffyjson.Number(4.5),
+		},
+		"number from invalid string": {
+			target:   `"four"`,
+			as:       func(l fluffyjson.Lenient) (any, error) { return l.AsNumber() },
+			expected: fluffyjson.Number(0),
+			err:      fluffyjson.ErrCoerce{},
+		},
+		"number from nan": {
+			target:   `"NaN"`,
+			as:       func(l fluffyjson.Lenient) (any, error) { return l.AsNumber() },
+			expected: fluffyjson.Number(0),
+			err:      fluffyjson.ErrCoerce{},
+		},
+		"number from hex": {
+			target:   `"0x1p3"`,
+			as:       func(l fluffyjson.Lenient) (any, error) { return l.AsNumber() },
+			expected: fluffyjson.Number(0),
+			err:      fluffyjson.ErrCoerce{},
+		},
+		"number from bool": {
+			target:   `true`,
+			as:       func(l fluffyjson.Lenient) (any, error) { return l.AsNumber() },
+			expected: fluffyjson.Number(1),
+		},
+		"number from null": {
+			target:   `null`,
+			as:       func(l fluffyjson.Lenient) (any, error) { return l.AsNumber() },
+			expected: fluffyjson.Number(0),
+		},
+		"bool from string": {
+			target:   `"TRUE"`,
+			as:       func(l fluffyjson.Lenient) (any, error) { return l.AsBool() },
+			expected: fluffyjson.Bool(true),
+		},
+		"bool from invalid string": {
+			target:   `"yes"`,
+			as:       func(l fluffyjson.Lenient) (any, error) { return l.AsBool() },
+			expected: fluffyjson.Bool(false),
+			err:      fluffyjson.ErrCoerce{},
+		},
+		"bool from number": {
+			target:   `0`,
+			as:       func(l fluffyjson.Lenient) (any, error) { return l.AsBool() },
+			expected: fluffyjson.Bool(false),
+		},
+		"null from string": {
+			target:   `""`,
+			as:       func(l fluffyjson.Lenient) (any, error) { return l.AsNull() },
+			expected: fluffyjson.NullValue,
+			err:      fluffyjson.ErrCoerce{},
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			value := HelperUnmarshalValue(t, tc.target)
+			actual, err := tc.as(fluffyjson.Lenient{Value: &value})
+			HelperFatalEvaluateError(t, tc.expected, actual, tc.err, err)
+		})
+	}
+
+	t.Run("nil", func(t *testing.T) {
+		object, err := fluffyjson.Lenient{}.AsObject()
+		HelperFatalEvaluateError(t, fluffyjson.Object{}, object, nil, err)
+
+		number, err := fluffyjson.Lenient{Value: &fluffyjson.RootValue{}}.AsNumber()
+		HelperFatalEvaluateError(t, fluffyjson.Number(0), number, nil, err)
+
+		str, err := fluffyjson.Lenient{}.AccessAsString(fluffyjson.KeyAccess("a"))
+		HelperFatalEvaluateError(t, "", str, fluffyjson.ErrAccess{
+			Accessor: fmt.Sprintf("%T", fluffyjson.KeyAccess("a")),
+			Expected: fluffyjson.OBJECT,
+			Actual:   fluffyjson.NULL,
+		}, err)
+	})
+
+	t.Run("access", func(t *testing.T) {
+		value := HelperUnmarshalValue(t, `{"a": ["1", 2, true, null], "b": {"c": "x"}}`)
+		lenient := fluffyjson.Lenient{Value: &value}
+
+		numbers, err := lenient.SliceAsNumber(fluffyjson.Pointer{fluffyjson.KeyAccess("a"), fluffyjson.WildcardAccess{}})
+		HelperFatalEvaluateError(t, []fluffyjson.Number{1, 2, 1, 0}, numbers, nil, err)
+
+		strings, err := lenient.SliceAsString(fluffyjson.Pointer{fluffyjson.KeyAccess("a"), fluffyjson.WildcardAccess{}})
+		HelperFatalEvaluateError(t, []fluffyjson.String{"1", "2", "true", ""}, strings, nil, err)
+
+		array, err := lenient.AccessAsArray(fluffyjson.KeyAccess("b"), fluffyjson.KeyAccess("c"))
+		HelperFatalEvaluateError(t, fluffyjson.Array{HelperCastString(t, "x")}, array, nil, err)
+
+		_, err = lenient.AccessAsNumber(fluffyjson.KeyAccess("b"), fluffyjson.KeyAccess("c"))
+		HelperFatalEvaluate(t, `cannot coerce string "x" into number`, err.Error())
+
+		number, err := lenient.AccessAsNumber(fluffyjson.KeyAccess("x"))
+		HelperFatalEvaluateError(t, 0, number, fluffyjson.ErrNotFound{}, err)
+	})
+}
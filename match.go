@@ -0,0 +1,144 @@
+package fluffyjson
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+)
+
+type (
+	// MultiAccessor accesses any number of values, and yields each of them with its pointer relative to the accessed value.
+	// It can be mixed with single [Accessor] in [Pointer], and [Pointer.Matching] fans out across them.
+	MultiAccessor interface {
+		Accessor
+		Matching(JsonValue) iter.Seq2[Pointer, JsonValue]
+	}
+
+	// WildcardAccess matches all children of object or array, as `*`. Object children are yielded in key order.
+	WildcardAccess struct{}
+	// DescendantAccess matches the value itself and all its descendants in pre-order, as `**`.
+	DescendantAccess struct{}
+	// FilterAccess matches the children of object or array that satisfy the predicate,
+	// which is called with the pointer of the child relative to the filtered value and the child itself.
+	FilterAccess func(Pointer, JsonValue) bool
+
+	// ErrMultiAccess is returned when the [MultiAccessor] is used where only single value is allowed.
+	ErrMultiAccess struct {
+		Accessor string
+	}
+)
+
+func (e ErrMultiAccess) Error() string {
+	return fmt.Sprintf("%s may match multiple values, use Matching instead of Accessing", e.Accessor)
+}
+
+func (w WildcardAccess) String() string { return "*" }
+func (w WildcardAccess) Accessing(v JsonValue) (JsonValue, error) {
+	return nil, ErrMultiAccess{Accessor: fmt.Sprintf("%T", w)}
+}
+func (w WildcardAccess) Matching(v JsonValue) iter.Seq2[Pointer, JsonValue] {
+	return func(yield func(Pointer, JsonValue) bool) {
+		jpChildren(jpNode{value: unwrapRoot(v)}, func(n jpNode) bool { return yield(n.pointer, n.value) })
+	}
+}
+
+func (d DescendantAccess) String() string { return "**" }
+func (d DescendantAccess) Accessing(v JsonValue) (JsonValue, error) {
+	return nil, ErrMultiAccess{Accessor: fmt.Sprintf("%T", d)}
+}
+func (d DescendantAccess) Matching(v JsonValue) iter.Seq2[Pointer, JsonValue] {
+	return func(yield func(Pointer, JsonValue) bool) {
+		jpDescendants(jpNode{value: unwrapRoot(v)}, func(n jpNode) bool { return yield(n.pointer, n.value) })
+	}
+}
+
+func (f FilterAccess) String() string { return "?" }
+func (f FilterAccess) Accessing(v JsonValue) (JsonValue, error) {
+	return nil, ErrMultiAccess{Accessor: fmt.Sprintf("%T", f)}
+}
+func (f FilterAccess) Matching(v JsonValue) iter.Seq2[Pointer, JsonValue] {
+	return func(yield func(Pointer, JsonValue) bool) {
+		jpChildren(jpNode{value: unwrapRoot(v)}, func(n jpNode) bool { return !f(n.pointer, n.value) || yield(n.pointer, n.value) })
+	}
+}
+
+func (w WildcardAccess) Slicing(v JsonValue) ([]JsonValue, error)   { return matchedValues(w, v), nil }
+func (d DescendantAccess) Slicing(v JsonValue) ([]JsonValue, error) { return matchedValues(d, v), nil }
+func (f FilterAccess) Slicing(v JsonValue) ([]JsonValue, error)     { return matchedValues(f, v), nil }
+func (p Pointer) Slicing(v JsonValue) ([]JsonValue, error)          { return matchedValues(p, v), nil }
+func matchedValues(m MultiAccessor, v JsonValue) []JsonValue {
+	values := make([]JsonValue, 0)
+	for _, matched := range m.Matching(v) {
+		values = append(values, matched)
+	}
+	return values
+}
+
+// Matching yields all values matched by the pointer, which may contain [MultiAccessor].
+// The yielded pointers consist of [KeyAccess] and [IndexAccess] only, and the values that fail to be accessed are skipped.
+func (p Pointer) Matching(v JsonValue) iter.Seq2[Pointer, JsonValue] {
+	return func(yield func(Pointer, JsonValue) bool) {
+		p.matching(nil, unwrapRoot(v), yield)
+	}
+}
+func (p Pointer) matching(prefix Pointer, v JsonValue, yield func(Pointer, JsonValue) bool) bool {
+	if len(p) == 0 {
+		return yield(prefix, v)
+	}
+	switch acc := p[0].(type) {
+	case MultiAccessor:
+		for rel, child := range acc.Matching(v) {
+			if !p[1:].matching(append(slices.Clip(prefix), rel...), unwrapRoot(child), yield) {
+				return false
+			}
+		}
+		return true
+	default:
+		child, err := acc.Accessing(v)
+		if err != nil {
+			return true
+		}
+		return p[1:].matching(append(slices.Clip(prefix), concreteAccessor(acc, v)), unwrapRoot(child), yield)
+	}
+}
+
+// concreteAccessor resolves the accessor into [KeyAccess] or [IndexAccess] against the accessed value if possible.
+func concreteAccessor(acc Accessor, v JsonValue) Accessor {
+	switch a := acc.(type) {
+	case IndexAccess:
+		if arr, ok := v.(*Array); ok && a < 0 {
+			return a + IndexAccess(len(*arr))
+		}
+	case KeyIndexAccess:
+		if _, ok := v.(*Array); !ok {
+			return KeyAccess(a)
+		} else if index, ok := arrayIndex(string(a)); ok {
+			return index
+		}
+	case FuzzyKeyAccess:
+		o, ok := v.(*Object)
+		if !ok {
+			return acc
+		} else if _, ok := (*o)[string(a)]; ok {
+			return KeyAccess(a)
+		}
+		normalized := normalizeKey(string(a))
+		for k := range *o {
+			if normalizeKey(k) == normalized {
+				return KeyAccess(k)
+			}
+		}
+	}
+	return acc
+}
+
+func unwrapRoot(v JsonValue) JsonValue {
+	switch root := v.(type) {
+	case *RootValue:
+		return root.JsonValue
+	case *CowValue:
+		return root.JsonValue
+	default:
+		return v
+	}
+}
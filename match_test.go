@@ -0,0 +1,149 @@
+package fluffyjson_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	fluffyjson "github.com/hayas1/go-fluffy-json"
+)
+
+func ExamplePointer_Matching() {
+	var value fluffyjson.RootValue
+	if err := json.Unmarshal([]byte(`{"users": [{"name": "alice"}, {"name": "bob"}]}`), &value); err != nil {
+		panic(err)
+	}
+
+	pointer := fluffyjson.Pointer{fluffyjson.KeyAccess("users"), fluffyjson.WildcardAccess{}, fluffyjson.KeyAccess("name")}
+	for p, name := range pointer.Matching(&value) {
+		s, err := p.PointerString()
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(s, *name.(*fluffyjson.String))
+	}
+	// Output:
+	// /users/0/name alice
+	// /users/1/name bob
+}
+
+func TestMatching(t *testing.T) {
+	const target = `{"a": [{"b": 1}, {"b": 2, "c": 3}, {"c": 4}], "d": {"b": 5, "e": {"b": 6}}}`
+
+	testcases := map[string]struct {
+		pointer  fluffyjson.Pointer
+		expected []string
+	}{
+		"no multi accessor": {
+			pointer:  fluffyjson.Pointer{fluffyjson.KeyAccess("a"), fluffyjson.IndexAccess(-1), fluffyjson.KeyAccess("c")},
+			expected: []string{"/a/2/c"},
+		},
+		"wildcard": {
+			pointer:  fluffyjson.Pointer{fluffyjson.KeyAccess("a"), fluffyjson.WildcardAccess{}, fluffyjson.KeyAccess("b")},
+			expected: []string{"/a/0/b", "/a/1/b"},
+		},
+		"wildcard object": {
+			pointer:  fluffyjson.Pointer{fluffyjson.KeyAccess("d"), fluffyjson.WildcardAccess{}},
+			expected: []string{"/d/b", "/d/e"},
+		},
+		"double wildcard": {
+			pointer:  fluffyjson.Pointer{fluffyjson.WildcardAccess{}, fluffyjson.WildcardAccess{}},
+			expected: []string{"/a/0", "/a/1", "/a/2", "/d/b", "/d/e"},
+		},
+		"descendant": {
+			pointer:  fluffyjson.Pointer{fluffyjson.DescendantAccess{}, fluffyjson.KeyAccess("b")},
+			expected: []string{"/a/0/b", "/a/1/b", "/d/b", "/d/e/b"},
+		},
+		"descendant includes self": {
+			pointer:  fluffyjson.Pointer{fluffyjson.KeyAccess("d"), fluffyjson.KeyAccess("e"), fluffyjson.DescendantAccess{}},
+			expected: []string{"/d/e", "/d/e/b"},
+		},
+		"key index access": {
+			pointer:  fluffyjson.Pointer{fluffyjson.KeyIndexAccess("a"), fluffyjson.WildcardAccess{}, fluffyjson.KeyIndexAccess("c")},
+			expected: []string{"/a/1/c", "/a/2/c"},
+		},
+		"nested pointer": {
+			pointer:  fluffyjson.Pointer{fluffyjson.Pointer{fluffyjson.KeyAccess("a"), fluffyjson.WildcardAccess{}}, fluffyjson.KeyAccess("c")},
+			expected: []string{"/a/1/c", "/a/2/c"},
+		},
+		"wildcard of scalar": {
+			pointer:  fluffyjson.Pointer{fluffyjson.KeyAccess("d"), fluffyjson.KeyAccess("b"), fluffyjson.WildcardAccess{}},
+			expected: []string{},
+		},
+		"missing": {
+			pointer:  fluffyjson.Pointer{fluffyjson.KeyAccess("x"), fluffyjson.WildcardAccess{}},
+			expected: []string{},
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			value := HelperUnmarshalValue(t, target)
+			actual := make([]string, 0)
+			for pointer, matched := range value.Match(tc.pointer...) {
+				accessed, err := value.Access(pointer...)
+				HelperFatalEvaluateError(t, matched, accessed, nil, err)
+				actual = append(actual, HelperFatalPointerString(t, pointer))
+			}
+			HelperFatalEvaluate(t, tc.expected, actual)
+		})
+	}
+
+	t.Run("break", func(t *testing.T) {
+		value := HelperUnmarshalValue(t, target)
+		count := 0
+		for range value.Match(fluffyjson.DescendantAccess{}) {
+			if count++; count == 3 {
+				break
+			}
+		}
+		HelperFatalEvaluate(t, 3, count)
+	})
+
+	t.Run("filter", func(t *testing.T) {
+		value := HelperUnmarshalValue(t, `{"items": [{"id": "x", "status": "active"}, {"id": "y", "status": "closed"}, {"id": "z", "status": "active"}]}`)
+		active := fluffyjson.FilterAccess(func(_ fluffyjson.Pointer, v fluffyjson.JsonValue) bool {
+			status, err := v.AccessAsString(fluffyjson.KeyAccess("status"))
+			return err == nil && status == "active"
+		})
+		ids, err := value.SliceAsString(fluffyjson.Pointer{fluffyjson.KeyAccess("items"), active, fluffyjson.KeyAccess("id")})
+		HelperFatalEvaluateError(t, []fluffyjson.String{"x", "z"}, ids, nil, err)
+
+		actual := make([]string, 0)
+		for pointer := range value.Match(fluffyjson.KeyAccess("items"), active) {
+			actual = append(actual, HelperFatalPointerString(t, pointer))
+		}
+		HelperFatalEvaluate(t, []string{"/items/0", "/items/2"}, actual)
+	})
+
+	t.Run("filter by pointer", func(t *testing.T) {
+		value := HelperUnmarshalValue(t, target)
+		odd := fluffyjson.FilterAccess(func(p fluffyjson.Pointer, _ fluffyjson.JsonValue) bool {
+			return p[0] == fluffyjson.IndexAccess(1)
+		})
+		numbers, err := value.SliceAsNumber(fluffyjson.Pointer{fluffyjson.KeyAccess("a"), odd, fluffyjson.WildcardAccess{}})
+		HelperFatalEvaluateError(t, []fluffyjson.Number{2, 3}, numbers, nil, err)
+	})
+
+	t.Run("access with multi accessor", func(t *testing.T) {
+		value := HelperUnmarshalValue(t, target)
+		_, err := value.Access(fluffyjson.KeyAccess("a"), fluffyjson.WildcardAccess{})
+		var errMulti fluffyjson.ErrMultiAccess
+		if !errors.As(err, &errMulti) {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("string with multi accessor", func(t *testing.T) {
+		for _, acc := range []fluffyjson.Accessor{fluffyjson.WildcardAccess{}, fluffyjson.DescendantAccess{}, fluffyjson.FilterAccess(nil)} {
+			if s, err := (fluffyjson.Pointer{fluffyjson.KeyAccess("a"), acc}).PointerString(); err == nil {
+				t.Fatalf("%T should not be represented as pointer, but got %s", acc, s)
+			}
+		}
+		nested := fluffyjson.Pointer{fluffyjson.KeyAccess("a"), fluffyjson.Pointer{fluffyjson.WildcardAccess{}}}
+		if s, err := nested.PointerString(); err == nil {
+			t.Fatalf("nested multi accessor should not be represented as pointer, but got %s", s)
+		}
+	})
+}
@@ -0,0 +1,200 @@
+package fluffyjson
+
+import (
+	"maps"
+	"slices"
+)
+
+type (
+	// Conflict is the position where ours and theirs change the base differently.
+	// Base, Ours and Theirs are nil if the value is absent in each side.
+	// Hunk reports that the conflict is the elements of arrays changed differently from Pointer,
+	// where Base, Ours and Theirs are *[Array] of the elements, and the resolved array is spliced there.
+	Conflict struct {
+		Pointer Pointer
+		Base    JsonValue
+		Ours    JsonValue
+		Theirs  JsonValue
+		Hunk    bool
+	}
+	// Resolver settles the conflict by returning the value to be merged, which is nil to remove it.
+	// It returns false if it leaves the conflict unresolved.
+	Resolver func(c Conflict) (JsonValue, bool)
+
+	MergeOption  func(*mergeOptions)
+	mergeOptions struct {
+		resolvers []Resolver
+	}
+)
+
+// ResolveWith settles the conflicts by the resolver. If multiple resolvers are given,
+// they are tried in order until one of them settles the conflict.
+func ResolveWith(r Resolver) MergeOption {
+	return func(o *mergeOptions) { o.resolvers = append(o.resolvers, r) }
+}
+
+// PreferOurs is the [Resolver] that settles every conflict with ours.
+func PreferOurs(c Conflict) (JsonValue, bool) {
+	return c.Ours, true
+}
+
+// PreferTheirs is the [Resolver] that settles every conflict with theirs.
+func PreferTheirs(c Conflict) (JsonValue, bool) {
+	return c.Theirs, true
+}
+
+func newMergeOptions(opts []MergeOption) mergeOptions {
+	var options mergeOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+func (o mergeOptions) resolve(c Conflict) (JsonValue, bool) {
+	for _, r := range o.resolvers {
+		if v, ok := r(c); ok {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// Merge3 merges the changes of ours and theirs from the base. The changes in different positions are merged automatically,
+// and the same change in both sides is merged once. Objects are merged member by member. Arrays are aligned with the base by [Diff],
+// and the hunks between the elements kept in both sides are merged, which conflict only if both sides change the same hunk differently.
+// The hunks of the same length in all sides are merged element by element.
+//
+// The conflicts that are not settled by [ResolveWith] are returned in the order of the pointers, and the merged value keeps ours there.
+// None of the base, ours and theirs is modified.
+func Merge3(base, ours, theirs JsonValue, opts ...MergeOption) (JsonValue, []Conflict) {
+	conflicts := make([]Conflict, 0)
+	merged := newMergeOptions(opts).merge(unwrapRoot(base), unwrapRoot(ours), unwrapRoot(theirs), Pointer{}, &conflicts)
+	return merged, conflicts
+}
+
+// merge returns the merged value, which is nil if the value is removed.
+func (o mergeOptions) merge(base, ours, theirs JsonValue, ptr Pointer, conflicts *[]Conflict) JsonValue {
+	switch {
+	case equal(ours, theirs), equal(base, theirs):
+		return deepCopy(ours)
+	case equal(base, ours):
+		return deepCopy(theirs)
+	}
+
+	switch ot := ours.(type) {
+	case *Object:
+		bt, ok := base.(*Object)
+		if tt, isObject := theirs.(*Object); isObject && (ok || base == nil) {
+			if !ok {
+				// both sides add the object, so their members are merged as if they were added to the empty object
+				bt = &Object{}
+			}
+			return o.mergeObject(*bt, *ot, *tt, ptr, conflicts)
+		}
+	case *Array:
+		bt, ok := base.(*Array)
+		if tt, isArray := theirs.(*Array); isArray && ok {
+			return o.mergeArray(*bt, *ot, *tt, ptr, conflicts)
+		}
+	}
+
+	conflict := Conflict{Pointer: ptr, Base: base, Ours: ours, Theirs: theirs}
+	if v, ok := o.resolve(conflict); ok {
+		return deepCopy(v)
+	}
+	*conflicts = append(*conflicts, conflict)
+	return deepCopy(ours)
+}
+
+func (o mergeOptions) mergeObject(base, ours, theirs Object, ptr Pointer, conflicts *[]Conflict) JsonValue {
+	keys := slices.Collect(maps.Keys(base))
+	keys = slices.AppendSeq(keys, maps.Keys(ours))
+	keys = slices.AppendSeq(keys, maps.Keys(theirs))
+	slices.Sort(keys)
+
+	merged := make(Object, len(ours))
+	for _, k := range slices.Compact(keys) {
+		child := append(slices.Clip(ptr), KeyAccess(k))
+		if v := o.merge(member(base, k), member(ours, k), member(theirs, k), child, conflicts); v != nil {
+			merged[k] = v
+		}
+	}
+	return &merged
+}
+func member(o Object, k string) JsonValue {
+	if v, ok := o[k]; ok {
+		return unwrapRoot(v)
+	}
+	return nil
+}
+
+func (o mergeOptions) mergeArray(base, ours, theirs Array, ptr Pointer, conflicts *[]Conflict) JsonValue {
+	toOurs, toTheirs := alignment(base, ours), alignment(base, theirs)
+	merged := make(Array, 0, len(ours))
+	b, oi, ti := 0, 0, 0
+	for i := 0; i <= len(base); i++ {
+		// the element of base kept in both sides is stable, and the hunks between them are merged
+		oe, te := len(ours), len(theirs)
+		if i < len(base) {
+			if toOurs[i] < 0 || toTheirs[i] < 0 {
+				continue
+			}
+			oe, te = toOurs[i], toTheirs[i]
+		}
+		merged = o.mergeHunk(merged, base[b:i], ours[oi:oe], theirs[ti:te], ptr, conflicts)
+		if i < len(base) {
+			merged = append(merged, deepCopy(ours[oe]))
+		}
+		b, oi, ti = i+1, oe+1, te+1
+	}
+	return &merged
+}
+func (o mergeOptions) mergeHunk(merged, base, ours, theirs Array, ptr Pointer, conflicts *[]Conflict) Array {
+	switch {
+	case equal(&ours, &theirs), equal(&base, &theirs):
+		return append(merged, *deepCopy(&ours).(*Array)...)
+	case equal(&base, &ours):
+		return append(merged, *deepCopy(&theirs).(*Array)...)
+	case len(base) == len(ours) && len(base) == len(theirs):
+		for i := range base {
+			child := append(slices.Clip(ptr), IndexAccess(len(merged)))
+			if v := o.merge(unwrapRoot(base[i]), unwrapRoot(ours[i]), unwrapRoot(theirs[i]), child, conflicts); v != nil {
+				merged = append(merged, v)
+			}
+		}
+		return merged
+	}
+
+	conflict := Conflict{
+		Pointer: append(slices.Clip(ptr), IndexAccess(len(merged))),
+		Base:    &base,
+		Ours:    &ours,
+		Theirs:  &theirs,
+		Hunk:    true,
+	}
+	resolved, ok := o.resolve(conflict)
+	if !ok {
+		*conflicts = append(*conflicts, conflict)
+		resolved = &ours
+	}
+	switch r := unwrapRoot(resolved).(type) {
+	case nil:
+		return merged
+	case *Array:
+		return append(merged, *deepCopy(r).(*Array)...)
+	default:
+		return append(merged, deepCopy(r))
+	}
+}
+
+// alignment returns the index of the element of b matched with each element of a by the alignment of [Diff], which is -1 if unmatched.
+func alignment(a, b Array) []int {
+	indices := make([]int, len(a))
+	for i := range indices {
+		indices[i] = -1
+	}
+	for _, match := range align(len(a), len(b), func(i, j int) bool { return equal(a[i], b[j]) }) {
+		indices[match[0]] = match[1]
+	}
+	return indices
+}
@@ -0,0 +1,170 @@
+package fluffyjson_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	fluffyjson "github.com/hayas1/go-fluffy-json"
+)
+
+func ExampleMerge3() {
+	var base, ours, theirs fluffyjson.RootValue
+	if err := json.Unmarshal([]byte(`{"host": "localhost", "port": 80, "debug": false}`), &base); err != nil {
+		panic(err)
+	}
+	if err := json.Unmarshal([]byte(`{"host": "example.com", "port": 8080, "debug": false}`), &ours); err != nil {
+		panic(err)
+	}
+	if err := json.Unmarshal([]byte(`{"host": "localhost", "port": 443, "debug": true}`), &theirs); err != nil {
+		panic(err)
+	}
+
+	merged, conflicts := fluffyjson.Merge3(&base, &ours, &theirs)
+	b, err := json.Marshal(merged)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(b))
+	for _, c := range conflicts {
+		p, _ := c.Pointer.PointerString()
+		o, _ := json.Marshal(c.Ours)
+		t, _ := json.Marshal(c.Theirs)
+		fmt.Println(p, string(o), string(t))
+	}
+	// Output:
+	// {"debug":true,"host":"example.com","port":8080}
+	// /port 8080 443
+}
+
+func TestMerge3(t *testing.T) {
+	testcases := map[string]struct {
+		base      string
+		ours      string
+		theirs    string
+		expected  string
+		conflicts []string
+	}{
+		"unchanged":          {base: `{"a": 1}`, ours: `{"a": 1}`, theirs: `{"a": 1}`, expected: `{"a": 1}`, conflicts: []string{}},
+		"ours only":          {base: `{"a": 1}`, ours: `{"a": 2}`, theirs: `{"a": 1}`, expected: `{"a": 2}`, conflicts: []string{}},
+		"theirs only":        {base: `{"a": 1}`, ours: `{"a": 1}`, theirs: `{"a": 2}`, expected: `{"a": 2}`, conflicts: []string{}},
+		"same change":        {base: `{"a": 1}`, ours: `{"a": 2}`, theirs: `{"a": 2}`, expected: `{"a": 2}`, conflicts: []string{}},
+		"different members":  {base: `{"a": 1, "b": 1}`, ours: `{"a": 2, "b": 1}`, theirs: `{"a": 1, "b": 2}`, expected: `{"a": 2, "b": 2}`, conflicts: []string{}},
+		"add and remove":     {base: `{"a": 1, "b": 1}`, ours: `{"a": 1, "b": 1, "c": 1}`, theirs: `{"b": 1}`, expected: `{"b": 1, "c": 1}`, conflicts: []string{}},
+		"nested":             {base: `{"a": {"b": 1, "c": 1}}`, ours: `{"a": {"b": 2, "c": 1}}`, theirs: `{"a": {"b": 1, "c": 2}}`, expected: `{"a": {"b": 2, "c": 2}}`, conflicts: []string{}},
+		"both add object":    {base: `{}`, ours: `{"a": {"b": 1}}`, theirs: `{"a": {"c": 1}}`, expected: `{"a": {"b": 1, "c": 1}}`, conflicts: []string{}},
+		"array elements":     {base: `[1, 2, 3]`, ours: `[0, 2, 3]`, theirs: `[1, 2, 4]`, expected: `[0, 2, 4]`, conflicts: []string{}},
+		"conflict":           {base: `{"a": 1}`, ours: `{"a": 2}`, theirs: `{"a": 3}`, expected: `{"a": 2}`, conflicts: []string{"/a"}},
+		"both add":           {base: `{}`, ours: `{"a": 1}`, theirs: `{"a": 2}`, expected: `{"a": 1}`, conflicts: []string{"/a"}},
+		"modify and remove":  {base: `{"a": 1, "b": 1}`, ours: `{"b": 1}`, theirs: `{"a": 2, "b": 1}`, expected: `{"b": 1}`, conflicts: []string{"/a"}},
+		"array length":       {base: `{"a": [1, 2]}`, ours: `{"a": [1, 2, 3]}`, theirs: `{"a": [0, 2]}`, expected: `{"a": [0, 2, 3]}`, conflicts: []string{}},
+		"array insertions":   {base: `[1, 2, 3]`, ours: `[0, 1, 2, 3]`, theirs: `[1, 2, 3, 4]`, expected: `[0, 1, 2, 3, 4]`, conflicts: []string{}},
+		"array removals":     {base: `[1, 2, 3, 4, 5]`, ours: `[1, 3, 4, 5]`, theirs: `[1, 2, 3, 5]`, expected: `[1, 3, 5]`, conflicts: []string{}},
+		"array same insert":  {base: `[1, 2]`, ours: `[1, 9, 2]`, theirs: `[1, 9, 2, 3]`, expected: `[1, 9, 2, 3]`, conflicts: []string{}},
+		"array overlapping":  {base: `[1, 2, 3, 4]`, ours: `[0, 1, 5, 3, 4]`, theirs: `[1, 6, 7, 3, 4]`, expected: `[0, 1, 5, 3, 4]`, conflicts: []string{"/2"}},
+		"array both insert":  {base: `{"a": [1]}`, ours: `{"a": [1, 2]}`, theirs: `{"a": [1, 3]}`, expected: `{"a": [1, 2]}`, conflicts: []string{"/a/1"}},
+		"array element":      {base: `[[1], 2]`, ours: `[[2], 2]`, theirs: `[[3], 2]`, expected: `[[2], 2]`, conflicts: []string{"/0/0"}},
+		"type change":        {base: `{"a": {"b": 1}}`, ours: `{"a": {"b": 2}}`, theirs: `{"a": [1]}`, expected: `{"a": {"b": 2}}`, conflicts: []string{"/a"}},
+		"multiple conflicts": {base: `{"b": 1, "a": 1}`, ours: `{"b": 2, "a": 2}`, theirs: `{"b": 3, "a": 3}`, expected: `{"b": 2, "a": 2}`, conflicts: []string{"/a", "/b"}},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			base, ours, theirs := HelperUnmarshalValue(t, tc.base), HelperUnmarshalValue(t, tc.ours), HelperUnmarshalValue(t, tc.theirs)
+			merged, conflicts := fluffyjson.Merge3(&base, &ours, &theirs)
+			HelperFatalEvaluate(t, HelperUnmarshalValue(t, tc.expected), fluffyjson.RootValue{JsonValue: merged})
+
+			pointers := make([]string, 0, len(conflicts))
+			for _, c := range conflicts {
+				pointers = append(pointers, HelperFatalPointerString(t, c.Pointer))
+			}
+			HelperFatalEvaluate(t, tc.conflicts, pointers)
+
+			HelperFatalEvaluate(t, HelperUnmarshalValue(t, tc.base), base)
+			HelperFatalEvaluate(t, HelperUnmarshalValue(t, tc.ours), ours)
+			HelperFatalEvaluate(t, HelperUnmarshalValue(t, tc.theirs), theirs)
+		})
+	}
+}
+
+func TestMerge3Hunk(t *testing.T) {
+	base, ours, theirs := HelperUnmarshalValue(t, `[1, 2, 3, 4]`), HelperUnmarshalValue(t, `[1, 5, 3, 4, 8]`), HelperUnmarshalValue(t, `[0, 1, 6, 7, 3, 4]`)
+	merged, conflicts := fluffyjson.Merge3(&base, &ours, &theirs)
+	HelperFatalEvaluate(t, HelperUnmarshalValue(t, `[0, 1, 5, 3, 4, 8]`), fluffyjson.RootValue{JsonValue: merged})
+	HelperFatalEvaluate(t, 1, len(conflicts))
+	HelperFatalEvaluate(t, "/2", HelperFatalPointerString(t, conflicts[0].Pointer))
+	HelperFatalEvaluate(t, true, conflicts[0].Hunk)
+	HelperFatalEvaluate(t, fluffyjson.JsonValue(&fluffyjson.Array{HelperCastNumber(t, 2)}), conflicts[0].Base)
+	HelperFatalEvaluate(t, fluffyjson.JsonValue(&fluffyjson.Array{HelperCastNumber(t, 5)}), conflicts[0].Ours)
+	HelperFatalEvaluate(t, fluffyjson.JsonValue(&fluffyjson.Array{HelperCastNumber(t, 6), HelperCastNumber(t, 7)}), conflicts[0].Theirs)
+
+	merged, conflicts = fluffyjson.Merge3(&base, &ours, &theirs, fluffyjson.ResolveWith(fluffyjson.PreferTheirs))
+	HelperFatalEvaluate(t, HelperUnmarshalValue(t, `[0, 1, 6, 7, 3, 4, 8]`), fluffyjson.RootValue{JsonValue: merged})
+	HelperFatalEvaluate(t, 0, len(conflicts))
+}
+
+func TestMerge3Resolver(t *testing.T) {
+	const base = `{"a": 1, "b": 1, "c": [1]}`
+	const ours = `{"a": 2, "c": [1, 2]}`
+	const theirs = `{"a": 3, "b": 2, "c": [0]}`
+
+	testcases := map[string]struct {
+		opts      []fluffyjson.MergeOption
+		expected  string
+		conflicts []string
+	}{
+		"no resolver": {
+			expected:  `{"a": 2, "c": [1, 2]}`,
+			conflicts: []string{"/a", "/b", "/c/0"},
+		},
+		"ours": {
+			opts:      []fluffyjson.MergeOption{fluffyjson.ResolveWith(fluffyjson.PreferOurs)},
+			expected:  `{"a": 2, "c": [1, 2]}`,
+			conflicts: []string{},
+		},
+		"theirs": {
+			opts:      []fluffyjson.MergeOption{fluffyjson.ResolveWith(fluffyjson.PreferTheirs)},
+			expected:  `{"a": 3, "b": 2, "c": [0]}`,
+			conflicts: []string{},
+		},
+		"custom": {
+			opts: []fluffyjson.MergeOption{
+				fluffyjson.ResolveWith(func(c fluffyjson.Conflict) (fluffyjson.JsonValue, bool) {
+					ours, ok1 := c.Ours.(*fluffyjson.Number)
+					theirs, ok2 := c.Theirs.(*fluffyjson.Number)
+					if !ok1 || !ok2 {
+						return nil, false
+					}
+					sum := *ours + *theirs
+					return &sum, true
+				}),
+			},
+			expected:  `{"a": 5, "c": [1, 2]}`,
+			conflicts: []string{"/b", "/c/0"},
+		},
+		"fallback": {
+			opts: []fluffyjson.MergeOption{
+				fluffyjson.ResolveWith(func(c fluffyjson.Conflict) (fluffyjson.JsonValue, bool) {
+					return nil, c.Ours == nil || c.Theirs == nil
+				}),
+				fluffyjson.ResolveWith(fluffyjson.PreferTheirs),
+			},
+			expected:  `{"a": 3, "c": [0]}`,
+			conflicts: []string{},
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			b, o, th := HelperUnmarshalValue(t, base), HelperUnmarshalValue(t, ours), HelperUnmarshalValue(t, theirs)
+			merged, conflicts := fluffyjson.Merge3(&b, &o, &th, tc.opts...)
+			HelperFatalEvaluate(t, HelperUnmarshalValue(t, tc.expected), fluffyjson.RootValue{JsonValue: merged})
+
+			pointers := make([]string, 0, len(conflicts))
+			for _, c := range conflicts {
+				pointers = append(pointers, HelperFatalPointerString(t, c.Pointer))
+			}
+			HelperFatalEvaluate(t, tc.conflicts, pointers)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package fluffyjson
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// ErrMergePatch is returned when the modified value cannot be represented by merge patch,
+// because null member of object means deletion in merge patch.
+type ErrMergePatch struct {
+	Pointer Pointer
+}
+
+func (e ErrMergePatch) Error() string {
+	return fmt.Sprintf("null member at %s cannot be represented in merge patch", e.Pointer.location())
+}
+func (e ErrMergePatch) Is(target error) bool {
+	_, ok := target.(ErrMergePatch)
+	return ok
+}
+
+// MergePatch returns the target merged with the patch, where null member deletes and object merges recursively.
+// Neither the target nor the patch is modified.
+//
+// https://tools.ietf.org/html/rfc7386
+func MergePatch(target, patch JsonValue) JsonValue {
+	p, ok := unwrapRoot(patch).(*Object)
+	if !ok {
+		return deepCopy(patch)
+	}
+
+	merged, ok := deepCopy(target).(*Object)
+	if !ok {
+		merged = &Object{}
+	}
+	for k, v := range *p {
+		if v.IsNull() {
+			delete(*merged, k)
+		} else {
+			(*merged)[k] = MergePatch((*merged)[k], v)
+		}
+	}
+	return merged
+}
+
+// CreateMergePatch returns the merge patch that turns the original into the modified by [MergePatch].
+// It fails with [ErrMergePatch] if the modified has null member of object that is not in the original as is.
+func CreateMergePatch(original, modified JsonValue) (JsonValue, error) {
+	return createMergePatch(original, modified, nil)
+}
+func createMergePatch(original, modified JsonValue, ptr Pointer) (JsonValue, error) {
+	o, ok := unwrapRoot(original).(*Object)
+	m, isObject := unwrapRoot(modified).(*Object)
+	if !isObject {
+		return deepCopy(modified), nil
+	} else if !ok {
+		// the patch is merged into empty object, so it must not contain null member
+		if err := checkMergePatch(m, ptr); err != nil {
+			return nil, err
+		}
+		return deepCopy(modified), nil
+	}
+
+	patch := Object{}
+	for k := range *o {
+		if _, ok := (*m)[k]; !ok {
+			null, _ := CastNull(nil)
+			patch[k] = &null
+		}
+	}
+	for _, k := range slices.Sorted(maps.Keys(*m)) {
+		mv, child := (*m)[k], append(slices.Clip(ptr), KeyAccess(k))
+		if ov, ok := (*o)[k]; !ok {
+			if mv.IsNull() {
+				return nil, ErrMergePatch{Pointer: child}
+			} else if err := checkMergePatch(mv, child); err != nil {
+				return nil, err
+			}
+			patch[k] = deepCopy(mv)
+		} else if !equal(ov, mv) {
+			if mv.IsNull() {
+				return nil, ErrMergePatch{Pointer: child}
+			}
+			p, err := createMergePatch(ov, mv, child)
+			if err != nil {
+				return nil, err
+			}
+			patch[k] = p
+		}
+	}
+	return &patch, nil
+}
+func checkMergePatch(v JsonValue, ptr Pointer) error {
+	o, ok := unwrapRoot(v).(*Object)
+	if !ok {
+		return nil
+	}
+	for _, k := range slices.Sorted(maps.Keys(*o)) {
+		v, child := (*o)[k], append(slices.Clip(ptr), KeyAccess(k))
+		if v.IsNull() {
+			return ErrMergePatch{Pointer: child}
+		} else if err := checkMergePatch(v, child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package fluffyjson_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	fluffyjson "github.com/hayas1/go-fluffy-json"
+)
+
+func ExampleMergePatch() {
+	var target, patch fluffyjson.RootValue
+	if err := json.Unmarshal([]byte(`{"title": "Goodbye!", "author": {"givenName": "John", "familyName": "Doe"}, "tags": ["example", "sample"]}`), &target); err != nil {
+		panic(err)
+	}
+	if err := json.Unmarshal([]byte(`{"title": "Hello!", "author": {"familyName": null}, "tags": ["example"]}`), &patch); err != nil {
+		panic(err)
+	}
+
+	b, err := json.Marshal(fluffyjson.MergePatch(&target, &patch))
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(b))
+	// Output: {"author":{"givenName":"John"},"tags":["example"],"title":"Hello!"}
+}
+
+// examples from https://tools.ietf.org/html/rfc7386#appendix-A
+func TestMergePatch(t *testing.T) {
+	testcases := []struct {
+		original string
+		patch    string
+		result   string
+	}{
+		{original: `{"a": "b"}`, patch: `{"a": "c"}`, result: `{"a": "c"}`},
+		{original: `{"a": "b"}`, patch: `{"b": "c"}`, result: `{"a": "b", "b": "c"}`},
+		{original: `{"a": "b"}`, patch: `{"a": null}`, result: `{}`},
+		{original: `{"a": "b", "b": "c"}`, patch: `{"a": null}`, result: `{"b": "c"}`},
+		{original: `{"a": ["b"]}`, patch: `{"a": "c"}`, result: `{"a": "c"}`},
+		{original: `{"a": "c"}`, patch: `{"a": ["b"]}`, result: `{"a": ["b"]}`},
+		{original: `{"a": {"b": "c"}}`, patch: `{"a": {"b": "d", "c": null}}`, result: `{"a": {"b": "d"}}`},
+		{original: `{"a": [{"b": "c"}]}`, patch: `{"a": [1]}`, result: `{"a": [1]}`},
+		{original: `["a", "b"]`, patch: `["c", "d"]`, result: `["c", "d"]`},
+		{original: `{"a": "b"}`, patch: `["c"]`, result: `["c"]`},
+		{original: `{"a": "foo"}`, patch: `null`, result: `null`},
+		{original: `{"a": "foo"}`, patch: `"bar"`, result: `"bar"`},
+		{original: `{"e": null}`, patch: `{"a": 1}`, result: `{"e": null, "a": 1}`},
+		{original: `[1, 2]`, patch: `{"a": "b", "c": null}`, result: `{"a": "b"}`},
+		{original: `{}`, patch: `{"a": {"bb": {"ccc": null}}}`, result: `{"a": {"bb": {}}}`},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.original+" "+tc.patch, func(t *testing.T) {
+			original, patch := HelperUnmarshalValue(t, tc.original), HelperUnmarshalValue(t, tc.patch)
+			actual := fluffyjson.MergePatch(&original, &patch)
+			HelperFatalEvaluate(t, HelperUnmarshalValue(t, tc.result), fluffyjson.RootValue{JsonValue: actual})
+			HelperFatalEvaluate(t, HelperUnmarshalValue(t, tc.original), original)
+		})
+	}
+}
+
+func TestCreateMergePatch(t *testing.T) {
+	testcases := map[string]struct {
+		original string
+		modified string
+		expected string
+		err      error
+	}{
+		"replace":          {original: `{"a": "b"}`, modified: `{"a": "c"}`, expected: `{"a": "c"}`},
+		"add":              {original: `{"a": "b"}`, modified: `{"a": "b", "b": "c"}`, expected: `{"b": "c"}`},
+		"remove":           {original: `{"a": "b", "b": "c"}`, modified: `{"b": "c"}`, expected: `{"a": null}`},
+		"nested":           {original: `{"a": {"b": "c", "c": 1}}`, modified: `{"a": {"b": "d"}}`, expected: `{"a": {"b": "d", "c": null}}`},
+		"array":            {original: `{"a": [1, 2]}`, modified: `{"a": [1]}`, expected: `{"a": [1]}`},
+		"unchanged":        {original: `{"a": [1, 2], "b": {"c": 1}}`, modified: `{"a": [1, 2], "b": {"c": 1}}`, expected: `{}`},
+		"unchanged null":   {original: `{"a": null}`, modified: `{"a": null}`, expected: `{}`},
+		"not object":       {original: `[1]`, modified: `{"a": 1}`, expected: `{"a": 1}`},
+		"scalar":           {original: `{"a": 1}`, modified: `1`, expected: `1`},
+		"null member":      {original: `{"a": 1}`, modified: `{"a": null}`, err: fluffyjson.ErrMergePatch{}},
+		"new null member":  {original: `{}`, modified: `{"a": {"b": null}}`, err: fluffyjson.ErrMergePatch{}},
+		"into non object":  {original: `{"a": 1}`, modified: `{"a": {"b": null}}`, err: fluffyjson.ErrMergePatch{}},
+		"keep null member": {original: `{"a": null, "b": 1}`, modified: `{"a": null, "b": 2}`, expected: `{"b": 2}`},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			original, modified := HelperUnmarshalValue(t, tc.original), HelperUnmarshalValue(t, tc.modified)
+			patch, err := fluffyjson.CreateMergePatch(&original, &modified)
+			if tc.err != nil {
+				HelperFatalEvaluateError(t, nil, patch, tc.err, err)
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			HelperFatalEvaluate(t, HelperUnmarshalValue(t, tc.expected), fluffyjson.RootValue{JsonValue: patch})
+			HelperFatalEvaluate(t, modified, fluffyjson.RootValue{JsonValue: fluffyjson.MergePatch(&original, patch)})
+		})
+	}
+
+	t.Run("error pointer", func(t *testing.T) {
+		original, modified := HelperUnmarshalValue(t, `{}`), HelperUnmarshalValue(t, `{"a": {"c": 1, "b": null}}`)
+		_, err := fluffyjson.CreateMergePatch(&original, &modified)
+		HelperFatalEvaluate(t, "null member at /a/b cannot be represented in merge patch", err.Error())
+	})
+}
@@ -0,0 +1,249 @@
+package fluffyjson
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+type (
+	// Mutate modifies the nested value at the pointer in place.
+	//   - Set replaces the value, or adds the member to object. [EndOfArray] appends to array.
+	//   - Insert inserts the value before the index of array, and [EndOfArray] appends to array. It works as Set on object.
+	//   - Delete removes the member of object or the element of array.
+	Mutate interface {
+		Set(Pointer, JsonValue, ...MutateOption) error
+		Insert(Pointer, JsonValue, ...MutateOption) error
+		Delete(Pointer) error
+	}
+	MutateOption  func(*mutateOptions)
+	mutateOptions struct {
+		createMissing bool
+	}
+
+	// ErrMutate is returned when the value cannot be mutated at the pointer.
+	ErrMutate struct {
+		Pointer Pointer
+		Reason  string
+	}
+)
+
+// EndOfArray is the `-` token of JSON pointer, which refers to the position after the last element of array.
+const EndOfArray KeyIndexAccess = "-"
+
+var (
+	// implemented mutations
+	_ []Mutate = []Mutate{&RootValue{}, &Object{}, &Array{}, &CowValue{}}
+)
+
+func (e ErrMutate) Error() string {
+	return fmt.Sprintf("cannot mutate at %s: %s", e.Pointer.location(), e.Reason)
+}
+func (e ErrMutate) Is(target error) bool {
+	_, ok := target.(ErrMutate)
+	return ok
+}
+
+// CreateMissing creates the missing intermediate values while mutating.
+// The created value is array if the next accessor is [IndexAccess] or [EndOfArray], otherwise object,
+// and the index equal to the length of array appends to it as [EndOfArray] does.
+func CreateMissing() MutateOption {
+	return func(o *mutateOptions) { o.createMissing = true }
+}
+
+func (v *RootValue) Set(ptr Pointer, value JsonValue, opts ...MutateOption) error {
+	return v.mutate(ptr, value, opts, false)
+}
+func (v *RootValue) Insert(ptr Pointer, value JsonValue, opts ...MutateOption) error {
+	return v.mutate(ptr, value, opts, true)
+}
+func (v *RootValue) Delete(ptr Pointer) error {
+	if ptr = ptr.flatten(); len(ptr) == 0 {
+		return ErrMutate{Pointer: ptr, Reason: "root value cannot be deleted"}
+	} else if v.JsonValue == nil {
+		return ErrMutate{Pointer: Pointer{}, Reason: "value is null"}
+	}
+	return deleteAt(v.JsonValue, ptr)
+}
+func (v *RootValue) mutate(ptr Pointer, value JsonValue, opts []MutateOption, insert bool) error {
+	if ptr = ptr.flatten(); len(ptr) == 0 {
+		v.JsonValue = unwrapRoot(value)
+		return nil
+	} else if v.JsonValue != nil {
+		return mutateAt(v.JsonValue, ptr, value, opts, insert)
+	} else if !newMutateOptions(opts).createMissing {
+		return ErrMutate{Pointer: Pointer{}, Reason: "value is null"}
+	}
+
+	// the created root is assigned only if the mutation succeeds, so failed mutation leaves the value unchanged
+	created := newContainer(ptr[0])
+	if err := mutateAt(created, ptr, value, opts, insert); err != nil {
+		return err
+	}
+	v.JsonValue = created
+	return nil
+}
+
+func (o *Object) Set(ptr Pointer, value JsonValue, opts ...MutateOption) error {
+	return mutateAt(o, ptr, value, opts, false)
+}
+func (o *Object) Insert(ptr Pointer, value JsonValue, opts ...MutateOption) error {
+	return mutateAt(o, ptr, value, opts, true)
+}
+func (o *Object) Delete(ptr Pointer) error { return deleteAt(o, ptr) }
+func (a *Array) Set(ptr Pointer, value JsonValue, opts ...MutateOption) error {
+	return mutateAt(a, ptr, value, opts, false)
+}
+func (a *Array) Insert(ptr Pointer, value JsonValue, opts ...MutateOption) error {
+	return mutateAt(a, ptr, value, opts, true)
+}
+func (a *Array) Delete(ptr Pointer) error { return deleteAt(a, ptr) }
+
+func newMutateOptions(opts []MutateOption) mutateOptions {
+	var options mutateOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+func mutateAt(v JsonValue, ptr Pointer, value JsonValue, opts []MutateOption, insert bool) error {
+	if ptr = ptr.flatten(); len(ptr) == 0 {
+		return ErrMutate{Pointer: ptr, Reason: "value itself cannot be replaced, use RootValue"}
+	}
+	options := newMutateOptions(opts)
+	parent, attach, err := parentOf(v, ptr, options)
+	if err != nil {
+		return err
+	}
+	last := ptr[len(ptr)-1]
+	if options.createMissing && appendable(parent, last) {
+		insert = true
+	}
+	if err := setChild(parent, last, unwrapRoot(value), insert); err != nil {
+		return prefixed(ptr[:len(ptr)-1], err)
+	}
+	attach()
+	return nil
+}
+func deleteAt(v JsonValue, ptr Pointer) error {
+	if ptr = ptr.flatten(); len(ptr) == 0 {
+		return ErrMutate{Pointer: ptr, Reason: "value itself cannot be deleted, use RootValue"}
+	}
+	parent, _, err := parentOf(v, ptr, mutateOptions{})
+	if err != nil {
+		return err
+	}
+	return prefixed(ptr[:len(ptr)-1], deleteChild(parent, ptr[len(ptr)-1]))
+}
+
+// parentOf returns the parent of the value at the pointer, creating the missing values if needed.
+// The created values are detached until attach is called, so failed mutation leaves the value unchanged.
+func parentOf(v JsonValue, ptr Pointer, options mutateOptions) (parent JsonValue, attach func(), err error) {
+	curr := unwrapRoot(v)
+	for i, acc := range ptr[:len(ptr)-1] {
+		next, err := acc.Accessing(curr)
+		if missing := errors.Is(err, ErrNotFound{}) || appendable(curr, acc); missing && options.createMissing {
+			// missing member of object and end of array can always be set, so the error is ignored
+			next = newContainer(ptr[i+1])
+			if attached, created := curr, next; attach == nil {
+				attach = func() { setChild(attached, acc, created, true) }
+			} else {
+				setChild(attached, acc, created, true)
+			}
+		} else if err != nil {
+			return nil, nil, prefixed(ptr[:i], err)
+		}
+		curr = unwrapRoot(next)
+	}
+	if attach == nil {
+		attach = func() {}
+	}
+	return curr, attach, nil
+}
+
+// appendable reports whether the accessor refers to the position after the last element of array,
+// which is [EndOfArray] or the index equal to the length.
+func appendable(v JsonValue, acc Accessor) bool {
+	arr, ok := v.(*Array)
+	if !ok {
+		return false
+	} else if acc == EndOfArray {
+		return true
+	}
+	index, ok := concreteAccessor(acc, arr).(IndexAccess)
+	return ok && int(index) == len(*arr)
+}
+
+func newContainer(next Accessor) JsonValue {
+	switch next.(type) {
+	case IndexAccess:
+		return &Array{}
+	default:
+		if next == EndOfArray {
+			return &Array{}
+		}
+		return &Object{}
+	}
+}
+
+func setChild(parent JsonValue, acc Accessor, value JsonValue, insert bool) error {
+	switch p := parent.(type) {
+	case *Object:
+		switch a := concreteAccessor(acc, p).(type) {
+		case KeyAccess:
+			(*p)[string(a)] = value
+			return nil
+		case FuzzyKeyAccess:
+			(*p)[string(a)] = value
+			return nil
+		}
+	case *Array:
+		if acc == EndOfArray {
+			*p = append(*p, value)
+			return nil
+		}
+		if index, ok := concreteAccessor(acc, p).(IndexAccess); ok {
+			length := len(*p)
+			if insert {
+				length++
+			}
+			if index < 0 || int(index) >= length {
+				return ErrOutOfRange{Pointer: Pointer{acc}, Value: p, Index: int(index), Length: len(*p)}
+			}
+			if insert {
+				*p = slices.Insert(*p, int(index), value)
+			} else {
+				(*p)[index] = value
+			}
+			return nil
+		}
+	}
+	return ErrMutate{Pointer: Pointer{acc}, Reason: fmt.Sprintf("%T is not allowed on %s", acc, parent.representation())}
+}
+func deleteChild(parent JsonValue, acc Accessor) error {
+	if _, err := acc.Accessing(parent); err != nil {
+		return err
+	}
+	switch a := concreteAccessor(acc, parent).(type) {
+	case KeyAccess:
+		delete(*parent.(*Object), string(a))
+	case IndexAccess:
+		p := parent.(*Array)
+		*p = slices.Delete(*p, int(a), int(a)+1)
+	}
+	return nil
+}
+
+// flatten expands the nested pointers.
+func (p Pointer) flatten() Pointer {
+	flat := make(Pointer, 0, len(p))
+	for _, acc := range p {
+		if nested, ok := acc.(Pointer); ok {
+			flat = append(flat, nested.flatten()...)
+		} else {
+			flat = append(flat, acc)
+		}
+	}
+	return flat
+}
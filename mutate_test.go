@@ -0,0 +1,209 @@
+package fluffyjson_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	fluffyjson "github.com/hayas1/go-fluffy-json"
+)
+
+func ExampleRootValue_Set() {
+	var value fluffyjson.RootValue
+	if err := json.Unmarshal([]byte(`{"users": [{"name": "alice"}]}`), &value); err != nil {
+		panic(err)
+	}
+
+	bob := fluffyjson.String("bob")
+	if err := value.Set(fluffyjson.Pointer{fluffyjson.KeyAccess("users"), fluffyjson.EndOfArray, fluffyjson.KeyAccess("name")}, &bob, fluffyjson.CreateMissing()); err != nil {
+		panic(err)
+	}
+	if err := value.Delete(fluffyjson.Pointer{fluffyjson.KeyAccess("users"), fluffyjson.IndexAccess(0)}); err != nil {
+		panic(err)
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(b))
+	// Output: {"users":[{"name":"bob"}]}
+}
+
+func TestMutate(t *testing.T) {
+	const target = `{"a": {"b": [1, 2, 3]}, "c": "d"}`
+
+	testcases := map[string]struct {
+		mutate   func(v *fluffyjson.RootValue) error
+		expected string
+		err      error
+	}{
+		"set member": {
+			mutate: func(v *fluffyjson.RootValue) error {
+				return v.Set(HelperFatalParsePointer(t, "/a/x"), HelperCastBool(t, true))
+			},
+			expected: `{"a": {"b": [1, 2, 3], "x": true}, "c": "d"}`,
+		},
+		"set replace member": {
+			mutate: func(v *fluffyjson.RootValue) error {
+				return v.Set(HelperFatalParsePointer(t, "/c"), HelperCastNumber(t, 0))
+			},
+			expected: `{"a": {"b": [1, 2, 3]}, "c": 0}`,
+		},
+		"set element": {
+			mutate: func(v *fluffyjson.RootValue) error {
+				return v.Set(HelperFatalParsePointer(t, "/a/b/1"), HelperCastNumber(t, 0))
+			},
+			expected: `{"a": {"b": [1, 0, 3]}, "c": "d"}`,
+		},
+		"set negative index": {
+			mutate: func(v *fluffyjson.RootValue) error {
+				return v.Set(fluffyjson.Pointer{fluffyjson.KeyAccess("a"), fluffyjson.KeyAccess("b"), fluffyjson.IndexAccess(-1)}, HelperCastNumber(t, 0))
+			},
+			expected: `{"a": {"b": [1, 2, 0]}, "c": "d"}`,
+		},
+		"set end of array": {
+			mutate: func(v *fluffyjson.RootValue) error {
+				return v.Set(HelperFatalParsePointer(t, "/a/b/-"), HelperCastNumber(t, 4))
+			},
+			expected: `{"a": {"b": [1, 2, 3, 4]}, "c": "d"}`,
+		},
+		"set out of range": {
+			mutate: func(v *fluffyjson.RootValue) error {
+				return v.Set(HelperFatalParsePointer(t, "/a/b/3"), HelperCastNumber(t, 4))
+			},
+			expected: target,
+			err:      fluffyjson.ErrOutOfRange{},
+		},
+		"set root": {
+			mutate:   func(v *fluffyjson.RootValue) error { return v.Set(nil, HelperCastNumber(t, 1)) },
+			expected: `1`,
+		},
+		"set missing": {
+			mutate: func(v *fluffyjson.RootValue) error {
+				return v.Set(HelperFatalParsePointer(t, "/x/y/-/z"), HelperCastNumber(t, 1))
+			},
+			expected: target,
+			err:      fluffyjson.ErrNotFound{},
+		},
+		"set create missing": {
+			mutate: func(v *fluffyjson.RootValue) error {
+				return v.Set(HelperFatalParsePointer(t, "/x/y/-/z"), HelperCastNumber(t, 1), fluffyjson.CreateMissing())
+			},
+			expected: `{"a": {"b": [1, 2, 3]}, "c": "d", "x": {"y": [{"z": 1}]}}`,
+		},
+		"set create missing array": {
+			mutate: func(v *fluffyjson.RootValue) error {
+				return v.Set(fluffyjson.Pointer{fluffyjson.KeyAccess("x"), fluffyjson.IndexAccess(0)}, HelperCastNumber(t, 1), fluffyjson.CreateMissing())
+			},
+			expected: `{"a": {"b": [1, 2, 3]}, "c": "d", "x": [1]}`,
+		},
+		"set create missing nested array": {
+			mutate: func(v *fluffyjson.RootValue) error {
+				return v.Set(fluffyjson.Pointer{fluffyjson.KeyAccess("a"), fluffyjson.KeyAccess("b"), fluffyjson.IndexAccess(3), fluffyjson.IndexAccess(0), fluffyjson.KeyAccess("k")}, HelperCastNumber(t, 1), fluffyjson.CreateMissing())
+			},
+			expected: `{"a": {"b": [1, 2, 3, [{"k": 1}]]}, "c": "d"}`,
+		},
+		"set create missing beyond length": {
+			mutate: func(v *fluffyjson.RootValue) error {
+				return v.Set(fluffyjson.Pointer{fluffyjson.KeyAccess("x"), fluffyjson.IndexAccess(1)}, HelperCastNumber(t, 1), fluffyjson.CreateMissing())
+			},
+			expected: target,
+			err:      fluffyjson.ErrOutOfRange{},
+		},
+		"set on scalar": {
+			mutate: func(v *fluffyjson.RootValue) error {
+				return v.Set(HelperFatalParsePointer(t, "/c/x"), HelperCastNumber(t, 1))
+			},
+			expected: target,
+			err:      fluffyjson.ErrMutate{},
+		},
+		"insert": {
+			mutate: func(v *fluffyjson.RootValue) error {
+				return v.Insert(HelperFatalParsePointer(t, "/a/b/0"), HelperCastNumber(t, 0))
+			},
+			expected: `{"a": {"b": [0, 1, 2, 3]}, "c": "d"}`,
+		},
+		"insert at length": {
+			mutate: func(v *fluffyjson.RootValue) error {
+				return v.Insert(HelperFatalParsePointer(t, "/a/b/3"), HelperCastNumber(t, 4))
+			},
+			expected: `{"a": {"b": [1, 2, 3, 4]}, "c": "d"}`,
+		},
+		"insert end of array": {
+			mutate: func(v *fluffyjson.RootValue) error {
+				return v.Insert(HelperFatalParsePointer(t, "/a/b/-"), HelperCastNumber(t, 4))
+			},
+			expected: `{"a": {"b": [1, 2, 3, 4]}, "c": "d"}`,
+		},
+		"insert out of range": {
+			mutate: func(v *fluffyjson.RootValue) error {
+				return v.Insert(HelperFatalParsePointer(t, "/a/b/4"), HelperCastNumber(t, 4))
+			},
+			expected: target,
+			err:      fluffyjson.ErrOutOfRange{},
+		},
+		"insert member": {
+			mutate: func(v *fluffyjson.RootValue) error {
+				return v.Insert(HelperFatalParsePointer(t, "/c"), HelperCastNumber(t, 4))
+			},
+			expected: `{"a": {"b": [1, 2, 3]}, "c": 4}`,
+		},
+		"delete member": {
+			mutate:   func(v *fluffyjson.RootValue) error { return v.Delete(HelperFatalParsePointer(t, "/a")) },
+			expected: `{"c": "d"}`,
+		},
+		"delete element": {
+			mutate:   func(v *fluffyjson.RootValue) error { return v.Delete(HelperFatalParsePointer(t, "/a/b/1")) },
+			expected: `{"a": {"b": [1, 3]}, "c": "d"}`,
+		},
+		"delete missing": {
+			mutate:   func(v *fluffyjson.RootValue) error { return v.Delete(HelperFatalParsePointer(t, "/a/x")) },
+			expected: target,
+			err:      fluffyjson.ErrNotFound{},
+		},
+		"delete root": {
+			mutate:   func(v *fluffyjson.RootValue) error { return v.Delete(nil) },
+			expected: target,
+			err:      fluffyjson.ErrMutate{},
+		},
+		"container": {
+			mutate: func(v *fluffyjson.RootValue) error {
+				inner, err := v.AccessAsObject(fluffyjson.KeyAccess("a"))
+				if err != nil {
+					return err
+				}
+				return inner.Set(fluffyjson.Pointer{fluffyjson.KeyAccess("b"), fluffyjson.IndexAccess(0)}, HelperCastNumber(t, 0))
+			},
+			expected: `{"a": {"b": [0, 2, 3]}, "c": "d"}`,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			value := HelperUnmarshalValue(t, target)
+			err := tc.mutate(&value)
+			HelperFatalEvaluateError(t, HelperUnmarshalValue(t, tc.expected), value, tc.err, err)
+		})
+	}
+
+	t.Run("null root", func(t *testing.T) {
+		var value fluffyjson.RootValue
+		err := value.Set(HelperFatalParsePointer(t, "/a"), HelperCastNumber(t, 1))
+		HelperFatalEvaluateError(t, fluffyjson.RootValue{}, value, fluffyjson.ErrMutate{}, err)
+		err = value.Insert(HelperFatalParsePointer(t, "/a"), HelperCastNumber(t, 1))
+		HelperFatalEvaluateError(t, fluffyjson.RootValue{}, value, fluffyjson.ErrMutate{}, err)
+		err = value.Delete(HelperFatalParsePointer(t, "/a"))
+		HelperFatalEvaluateError(t, fluffyjson.RootValue{}, value, fluffyjson.ErrMutate{}, err)
+
+		err = value.Set(fluffyjson.Pointer{fluffyjson.KeyAccess("a"), fluffyjson.IndexAccess(1)}, HelperCastNumber(t, 1), fluffyjson.CreateMissing())
+		HelperFatalEvaluateError(t, fluffyjson.RootValue{}, value, fluffyjson.ErrOutOfRange{}, err)
+		err = value.Set(fluffyjson.Pointer{fluffyjson.IndexAccess(0), fluffyjson.KeyAccess("a")}, HelperCastNumber(t, 1), fluffyjson.CreateMissing())
+		HelperFatalEvaluateError(t, HelperUnmarshalValue(t, `[{"a": 1}]`), value, nil, err)
+	})
+
+	t.Run("error pointer", func(t *testing.T) {
+		value := HelperUnmarshalValue(t, target)
+		err := value.Delete(HelperFatalParsePointer(t, "/a/b/5"))
+		HelperFatalEvaluate(t, "index 5 out of range with length 3 at /a/b/5", err.Error())
+	})
+}
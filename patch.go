@@ -0,0 +1,194 @@
+package fluffyjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+type (
+	// Patch is the JSON Patch document, which is applied atomically by [Patch.Apply].
+	// The paths follow RFC 6901 exactly, so "" is the whole document and "/" is the member of empty key.
+	//
+	// https://tools.ietf.org/html/rfc6902
+	Patch []PatchOperation
+	// PatchOperation is the operation of [Patch]. From is used by move and copy, and Value is used by add, replace and test.
+	PatchOperation struct {
+		Op    string
+		Path  Pointer
+		From  Pointer
+		Value JsonValue
+	}
+
+	// ErrPatch is returned when the operation of [Patch] is invalid or fails. Index is the index of the operation.
+	ErrPatch struct {
+		Index int
+		Op    string
+		Err   error
+	}
+	// ErrPatchTest is returned when the test operation of [Patch] fails.
+	ErrPatchTest struct {
+		Path     Pointer
+		Expected JsonValue
+		Actual   JsonValue
+	}
+)
+
+func (e ErrPatch) Error() string {
+	if e.Op == "" {
+		return fmt.Sprintf("operation %d: %s", e.Index, e.Err)
+	}
+	return fmt.Sprintf("operation %d (%s): %s", e.Index, e.Op, e.Err)
+}
+func (e ErrPatch) Unwrap() error {
+	return e.Err
+}
+func (e ErrPatchTest) Error() string {
+	expected, _ := json.Marshal(e.Expected)
+	actual, _ := json.Marshal(e.Actual)
+	return fmt.Sprintf("test failed at %s, expected %s, but got %s", e.Path.location(), expected, actual)
+}
+func (e ErrPatchTest) Is(target error) bool {
+	_, ok := target.(ErrPatchTest)
+	return ok
+}
+
+// ParsePatch parses the JSON Patch document from the array of operation objects.
+func ParsePatch(v JsonValue) (Patch, error) {
+	operations, err := v.AsArray()
+	if err != nil {
+		return nil, err
+	}
+	patch := make(Patch, 0, len(operations))
+	for i, o := range operations {
+		op, err := parsePatchOperation(o)
+		if err != nil {
+			return nil, ErrPatch{Index: i, Op: op.Op, Err: err}
+		}
+		patch = append(patch, op)
+	}
+	return patch, nil
+}
+func parsePatchOperation(v JsonValue) (PatchOperation, error) {
+	var op PatchOperation
+	o, err := v.AsObject()
+	if err != nil {
+		return op, err
+	}
+
+	kind, err := o.AccessAsString(KeyAccess("op"))
+	if err != nil {
+		return op, err
+	}
+	op.Op = string(kind)
+	if op.Path, err = parsePatchPointer(o, "path"); err != nil {
+		return op, err
+	}
+
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value, err = o.Access(KeyAccess("value")); err != nil {
+			return op, err
+		}
+	case "move", "copy":
+		if op.From, err = parsePatchPointer(o, "from"); err != nil {
+			return op, err
+		}
+	case "remove":
+	default:
+		return op, fmt.Errorf("unknown operation %q", op.Op)
+	}
+	return op, nil
+}
+func parsePatchPointer(o Object, key string) (Pointer, error) {
+	p, err := o.AccessAsString(KeyAccess(key))
+	if err != nil {
+		return nil, err
+	}
+	return ParsePointer(string(p))
+}
+
+func (p *Patch) UnmarshalJSON(data []byte) error {
+	var value RootValue
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	patch, err := ParsePatch(value.JsonValue)
+	if err != nil {
+		return err
+	}
+	*p = patch
+	return nil
+}
+func (op PatchOperation) MarshalJSON() ([]byte, error) {
+	path, err := op.Path.PointerString()
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]any{"op": op.Op, "path": path}
+	switch op.Op {
+	case "add", "replace", "test":
+		m["value"] = op.Value
+	case "move", "copy":
+		if m["from"], err = op.From.PointerString(); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(m)
+}
+
+// Apply applies all operations of the patch to v. If any operation fails, v is left unchanged.
+func (p Patch) Apply(v *RootValue) error {
+	patched := RootValue{deepCopy(v.JsonValue)}
+	for i, op := range p {
+		if err := op.apply(&patched); err != nil {
+			return ErrPatch{Index: i, Op: op.Op, Err: err}
+		}
+	}
+	v.JsonValue = patched.JsonValue
+	return nil
+}
+func (op PatchOperation) apply(v *RootValue) error {
+	switch op.Op {
+	case "add":
+		return v.Insert(op.Path, deepCopy(op.Value))
+	case "remove":
+		return v.Delete(op.Path)
+	case "replace":
+		if _, err := v.Access(op.Path...); err != nil {
+			return err
+		}
+		return v.Set(op.Path, deepCopy(op.Value))
+	case "move":
+		from, _ := op.From.PointerString()
+		path, _ := op.Path.PointerString()
+		if from == path {
+			return nil
+		} else if strings.HasPrefix(path, from+"/") {
+			return fmt.Errorf("cannot move %s into its child %s", from, path)
+		}
+		value, err := v.Access(op.From...)
+		if err != nil {
+			return err
+		} else if err := v.Delete(op.From); err != nil {
+			return err
+		}
+		return v.Insert(op.Path, value)
+	case "copy":
+		value, err := v.Access(op.From...)
+		if err != nil {
+			return err
+		}
+		return v.Insert(op.Path, deepCopy(value))
+	case "test":
+		value, err := v.Access(op.Path...)
+		if err != nil {
+			return err
+		} else if !equal(value, op.Value) {
+			return ErrPatchTest{Path: op.Path, Expected: op.Value, Actual: value}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown operation %q", op.Op)
+	}
+}
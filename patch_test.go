@@ -0,0 +1,218 @@
+package fluffyjson_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	fluffyjson "github.com/hayas1/go-fluffy-json"
+)
+
+func ExamplePatch_Apply() {
+	var value fluffyjson.RootValue
+	if err := json.Unmarshal([]byte(`{"name": "alice", "tags": ["a"]}`), &value); err != nil {
+		panic(err)
+	}
+
+	var patch fluffyjson.Patch
+	if err := json.Unmarshal([]byte(`[
+		{"op": "replace", "path": "/name", "value": "bob"},
+		{"op": "add", "path": "/tags/-", "value": "b"},
+		{"op": "test", "path": "/tags/0", "value": "x"}
+	]`), &patch); err != nil {
+		panic(err)
+	}
+	fmt.Println(patch.Apply(&value))
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(b))
+	// Output:
+	// operation 2 (test): test failed at /tags/0, expected "x", but got "a"
+	// {"name":"alice","tags":["a"]}
+}
+
+// The suites are transcribed by hand from https://github.com/json-patch/json-patch-tests, not the upstream files (see testdata/json-patch-tests/README.md).
+func TestPatchTranscribedSuite(t *testing.T) {
+	for _, file := range []string{"testdata/json-patch-tests/spec_tests.json", "testdata/json-patch-tests/tests.json"} {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var testcases []struct {
+			Comment  string          `json:"comment"`
+			Doc      json.RawMessage `json:"doc"`
+			Patch    json.RawMessage `json:"patch"`
+			Expected json.RawMessage `json:"expected"`
+			Error    string          `json:"error"`
+			Disabled bool            `json:"disabled"`
+		}
+		if err := json.Unmarshal(b, &testcases); err != nil {
+			t.Fatal(err)
+		}
+
+		for i, tc := range testcases {
+			t.Run(fmt.Sprintf("%s/%d %s", filepath.Base(file), i, tc.Comment), func(t *testing.T) {
+				if tc.Disabled {
+					t.Skip("disabled in the suite")
+				}
+				value := HelperUnmarshalValue(t, string(tc.Doc))
+				var patch fluffyjson.Patch
+				err := json.Unmarshal(tc.Patch, &patch)
+				if err == nil {
+					err = patch.Apply(&value)
+				}
+				if tc.Error != "" {
+					if err == nil {
+						t.Fatalf("expected error %q, but got %s", tc.Error, HelperMarshalValue(t, value))
+					}
+					HelperFatalEvaluate(t, HelperUnmarshalValue(t, string(tc.Doc)), value)
+					return
+				} else if err != nil {
+					t.Fatal(err)
+				} else if tc.Expected != nil {
+					HelperFatalEvaluate(t, HelperUnmarshalValue(t, string(tc.Expected)), value)
+				}
+			})
+		}
+	}
+}
+
+func TestPatchApply(t *testing.T) {
+	testcases := map[string]struct {
+		doc      string
+		patch    string
+		expected string
+		err      bool
+	}{
+		"empty list, empty docs":                 {doc: `{}`, patch: `[]`, expected: `{}`},
+		"empty patch list":                       {doc: `{"foo": 1}`, patch: `[]`, expected: `{"foo": 1}`},
+		"rearrangements OK?":                     {doc: `{"foo": 1, "bar": 2}`, patch: `[]`, expected: `{"bar": 2, "foo": 1}`},
+		"toplevel array":                         {doc: `[]`, patch: `[{"op": "add", "path": "/0", "value": "foo"}]`, expected: `["foo"]`},
+		"toplevel array, no change":              {doc: `["foo"]`, patch: `[]`, expected: `["foo"]`},
+		"toplevel object, numeric string":        {doc: `{}`, patch: `[{"op": "add", "path": "/foo", "value": "1"}]`, expected: `{"foo": "1"}`},
+		"toplevel object, integer":               {doc: `{}`, patch: `[{"op": "add", "path": "/foo", "value": 1}]`, expected: `{"foo": 1}`},
+		"replace object document with array":     {doc: `{}`, patch: `[{"op": "add", "path": "", "value": []}]`, expected: `[]`},
+		"replace array document with object":     {doc: `[]`, patch: `[{"op": "add", "path": "", "value": {}}]`, expected: `{}`},
+		"append an array to itself":              {doc: `[]`, patch: `[{"op": "add", "path": "/-", "value": []}]`, expected: `[[]]`},
+		"add to the empty key":                   {doc: `{}`, patch: `[{"op": "add", "path": "/", "value": 1}]`, expected: `{"": 1}`},
+		"add to the empty key of nested":         {doc: `{"foo": {}}`, patch: `[{"op": "add", "path": "/foo/", "value": 1}]`, expected: `{"foo": {"": 1}}`},
+		"add, /foo/- on array":                   {doc: `{"foo": [1, 2]}`, patch: `[{"op": "add", "path": "/foo/-", "value": ["abc", "def"]}]`, expected: `{"foo": [1, 2, ["abc", "def"]]}`},
+		"add, /0 on array":                       {doc: `{"foo": [1, 2]}`, patch: `[{"op": "add", "path": "/foo/0", "value": 0}]`, expected: `{"foo": [0, 1, 2]}`},
+		"add at the end":                         {doc: `{"foo": [1, 2]}`, patch: `[{"op": "add", "path": "/foo/2", "value": 3}]`, expected: `{"foo": [1, 2, 3]}`},
+		"add replaces existing member":           {doc: `{"foo": 1}`, patch: `[{"op": "add", "path": "/foo", "value": null}]`, expected: `{"foo": null}`},
+		"add false":                              {doc: `{"foo": 1}`, patch: `[{"op": "add", "path": "/bar", "value": false}]`, expected: `{"foo": 1, "bar": false}`},
+		"add array index with leading zero":      {doc: `{"foo": [1]}`, patch: `[{"op": "add", "path": "/foo/01", "value": 2}]`, err: true},
+		"add array index with sign":              {doc: `{"foo": [1]}`, patch: `[{"op": "add", "path": "/foo/+1", "value": 2}]`, err: true},
+		"add negative array index":               {doc: `{"foo": [1]}`, patch: `[{"op": "add", "path": "/foo/-1", "value": 2}]`, err: true},
+		"add out of bounds":                      {doc: `{"bar": [1, 2]}`, patch: `[{"op": "add", "path": "/bar/8", "value": 5}]`, err: true},
+		"add to missing parent":                  {doc: `{"foo": "bar"}`, patch: `[{"op": "add", "path": "/baz/bat", "value": "qux"}]`, err: true},
+		"add with bad array index":               {doc: `["foo", "sil"]`, patch: `[{"op": "add", "path": "/bar", "value": 42}]`, err: true},
+		"add to scalar":                          {doc: `{"foo": 1}`, patch: `[{"op": "add", "path": "/foo/bar", "value": 42}]`, err: true},
+		"add without value":                      {doc: `{}`, patch: `[{"op": "add", "path": "/foo"}]`, err: true},
+		"add with null value":                    {doc: `{}`, patch: `[{"op": "add", "path": "/foo", "value": null}]`, expected: `{"foo": null}`},
+		"remove":                                 {doc: `{"foo": 1, "bar": [1, 2, 3, 4]}`, patch: `[{"op": "remove", "path": "/bar"}]`, expected: `{"foo": 1}`},
+		"remove array element":                   {doc: `{"foo": 1, "baz": [{"qux": "hello"}]}`, patch: `[{"op": "remove", "path": "/baz/0/qux"}]`, expected: `{"foo": 1, "baz": [{}]}`},
+		"remove from array":                      {doc: `[1, 2, 3, 4]`, patch: `[{"op": "remove", "path": "/0"}]`, expected: `[2, 3, 4]`},
+		"remove twice from array":                {doc: `[1, 2, 3, 4]`, patch: `[{"op": "remove", "path": "/1"}, {"op": "remove", "path": "/2"}]`, expected: `[1, 3]`},
+		"remove missing":                         {doc: `{"foo": 1}`, patch: `[{"op": "remove", "path": "/bar"}]`, err: true},
+		"remove out of bounds":                   {doc: `[1, 2]`, patch: `[{"op": "remove", "path": "/2"}]`, err: true},
+		"remove end of array":                    {doc: `[1, 2]`, patch: `[{"op": "remove", "path": "/-"}]`, err: true},
+		"replace":                                {doc: `{"foo": 1, "baz": [{"qux": "hello"}]}`, patch: `[{"op": "replace", "path": "/foo", "value": [1, 2, 3, 4]}]`, expected: `{"foo": [1, 2, 3, 4], "baz": [{"qux": "hello"}]}`},
+		"replace nested":                         {doc: `{"foo": [1, 2, 3, 4], "baz": [{"qux": "hello"}]}`, patch: `[{"op": "replace", "path": "/baz/0/qux", "value": "world"}]`, expected: `{"foo": [1, 2, 3, 4], "baz": [{"qux": "world"}]}`},
+		"replace array element":                  {doc: `["foo"]`, patch: `[{"op": "replace", "path": "/0", "value": "bar"}]`, expected: `["bar"]`},
+		"replace with false":                     {doc: `[""]`, patch: `[{"op": "replace", "path": "/0", "value": false}]`, expected: `[false]`},
+		"replace whole document":                 {doc: `{"foo": "bar"}`, patch: `[{"op": "replace", "path": "", "value": {"baz": "qux"}}]`, expected: `{"baz": "qux"}`},
+		"replace missing":                        {doc: `{}`, patch: `[{"op": "replace", "path": "/foo", "value": 1}]`, err: true},
+		"replace end of array":                   {doc: `[1]`, patch: `[{"op": "replace", "path": "/-", "value": 2}]`, err: true},
+		"move to same location":                  {doc: `{"foo": 1}`, patch: `[{"op": "move", "from": "/foo", "path": "/foo"}]`, expected: `{"foo": 1}`},
+		"move member":                            {doc: `{"foo": 1, "baz": [{"qux": "hello"}]}`, patch: `[{"op": "move", "from": "/foo", "path": "/bar"}]`, expected: `{"baz": [{"qux": "hello"}], "bar": 1}`},
+		"move into array":                        {doc: `{"baz": [{"qux": "hello"}], "bar": 1}`, patch: `[{"op": "move", "from": "/baz/0/qux", "path": "/baz/1"}]`, expected: `{"baz": [{}, "hello"], "bar": 1}`},
+		"move within array":                      {doc: `[1, 2, 3, 4]`, patch: `[{"op": "move", "from": "/0", "path": "/3"}]`, expected: `[2, 3, 4, 1]`},
+		"move into its child":                    {doc: `{"foo": {"bar": 1}}`, patch: `[{"op": "move", "from": "/foo", "path": "/foo/bar/baz"}]`, err: true},
+		"move missing":                           {doc: `{"foo": 1}`, patch: `[{"op": "move", "from": "/bar", "path": "/baz"}]`, err: true},
+		"move without from":                      {doc: `{"foo": 1}`, patch: `[{"op": "move", "path": "/bar"}]`, err: true},
+		"copy":                                   {doc: `{"baz": [{"qux": "hello"}], "bar": 1}`, patch: `[{"op": "copy", "from": "/baz/0", "path": "/boo"}]`, expected: `{"baz": [{"qux": "hello"}], "bar": 1, "boo": {"qux": "hello"}}`},
+		"copy is deep":                           {doc: `{"foo": {"bar": 1}}`, patch: `[{"op": "copy", "from": "/foo", "path": "/baz"}, {"op": "replace", "path": "/baz/bar", "value": 2}]`, expected: `{"foo": {"bar": 1}, "baz": {"bar": 2}}`},
+		"copy missing":                           {doc: `{"foo": 1}`, patch: `[{"op": "copy", "from": "/bar", "path": "/baz"}]`, err: true},
+		"test":                                   {doc: `{"foo": {"bar": [1, 2, 5, 4]}}`, patch: `[{"op": "test", "path": "/foo", "value": {"bar": [1, 2, 5, 4]}}]`, expected: `{"foo": {"bar": [1, 2, 5, 4]}}`},
+		"test fails":                             {doc: `{"foo": {"bar": [1, 2, 5, 4]}}`, patch: `[{"op": "test", "path": "/foo", "value": [1, 2]}]`, err: true},
+		"test whole document":                    {doc: `{"foo": 1}`, patch: `[{"op": "test", "path": "", "value": {"foo": 1}}]`, expected: `{"foo": 1}`},
+		"test array order":                       {doc: `[1, 2]`, patch: `[{"op": "test", "path": "", "value": [2, 1]}]`, err: true},
+		"test number equality":                   {doc: `{"foo": 1}`, patch: `[{"op": "test", "path": "/foo", "value": 1.0}]`, expected: `{"foo": 1}`},
+		"test string and number":                 {doc: `{"foo": "1"}`, patch: `[{"op": "test", "path": "/foo", "value": 1}]`, err: true},
+		"test null":                              {doc: `{"foo": null}`, patch: `[{"op": "test", "path": "/foo", "value": null}]`, expected: `{"foo": null}`},
+		"test missing":                           {doc: `{"foo": null}`, patch: `[{"op": "test", "path": "/bar", "value": null}]`, err: true},
+		"test escaped pointer":                   {doc: `{"/": 9, "~1": 10}`, patch: `[{"op": "test", "path": "/~01", "value": 10}]`, expected: `{"/": 9, "~1": 10}`},
+		"test escaped pointer mismatch":          {doc: `{"/": 9, "~1": 10}`, patch: `[{"op": "test", "path": "/~01", "value": "10"}]`, err: true},
+		"unknown operation":                      {doc: `{}`, patch: `[{"op": "spam", "path": "/foo", "value": 1}]`, err: true},
+		"missing op":                             {doc: `{}`, patch: `[{"path": "/foo", "value": 1}]`, err: true},
+		"missing path":                           {doc: `{}`, patch: `[{"op": "add", "value": 1}]`, err: true},
+		"path without slash":                     {doc: `{"foo": 1}`, patch: `[{"op": "add", "path": "foo", "value": 1}]`, err: true},
+		"patch with extra member":                {doc: `{}`, patch: `[{"op": "add", "path": "/foo", "value": 1, "spam": 2}]`, expected: `{"foo": 1}`},
+		"failing patch is atomic":                {doc: `{"foo": 1}`, patch: `[{"op": "add", "path": "/bar", "value": 2}, {"op": "remove", "path": "/baz"}]`, err: true},
+		"test before replace keeps value intact": {doc: `{"foo": [1]}`, patch: `[{"op": "test", "path": "/foo/0", "value": 1}, {"op": "replace", "path": "/foo/0", "value": 2}]`, expected: `{"foo": [2]}`},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			value := HelperUnmarshalValue(t, tc.doc)
+			var patch fluffyjson.Patch
+			err := json.Unmarshal([]byte(tc.patch), &patch)
+			if err == nil {
+				err = patch.Apply(&value)
+			}
+			if tc.err {
+				if err == nil {
+					t.Fatalf("expected error, but got %s", HelperMarshalValue(t, value))
+				}
+				HelperFatalEvaluate(t, HelperUnmarshalValue(t, tc.doc), value)
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			HelperFatalEvaluate(t, HelperUnmarshalValue(t, tc.expected), value)
+		})
+	}
+}
+
+func TestPatchError(t *testing.T) {
+	value := HelperUnmarshalValue(t, `{"foo": [1, 2]}`)
+	var patch fluffyjson.Patch
+	if err := json.Unmarshal([]byte(`[{"op": "test", "path": "/foo/0", "value": 1}, {"op": "remove", "path": "/foo/5"}]`), &patch); err != nil {
+		t.Fatal(err)
+	}
+
+	err := patch.Apply(&value)
+	var errPatch fluffyjson.ErrPatch
+	if !errors.As(err, &errPatch) {
+		t.Fatal(err)
+	}
+	HelperFatalEvaluate(t, 1, errPatch.Index)
+	HelperFatalEvaluate(t, "remove", errPatch.Op)
+	if !errors.Is(err, fluffyjson.ErrOutOfRange{}) {
+		t.Fatal(err)
+	}
+
+	err = json.Unmarshal([]byte(`[{"op": "add", "path": "/foo", "value": 1}, {"op": "copy", "path": "/bar"}]`), &patch)
+	if !errors.As(err, &errPatch) {
+		t.Fatal(err)
+	}
+	HelperFatalEvaluate(t, 1, errPatch.Index)
+	HelperFatalEvaluate(t, "copy", errPatch.Op)
+}
+
+func TestPatchRoundtrip(t *testing.T) {
+	const target = `[{"op":"add","path":"","value":{"a":1}},{"from":"/a","op":"copy","path":"/"},{"op":"remove","path":"/a~1b"}]`
+	var patch fluffyjson.Patch
+	if err := json.Unmarshal([]byte(target), &patch); err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(patch)
+	HelperFatalEvaluateError(t, target, string(b), nil, err)
+}
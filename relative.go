@@ -0,0 +1,150 @@
+package fluffyjson
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// RelativePointer is the relative JSON pointer, such as `0/foo`, `1#` and `2-1/bar`.
+// It is evaluated from the position described by the absolute [Pointer] within the root.
+//   - Up is the number of levels to go up from the position.
+//   - Shift moves the index of the array element reached after going up, such as `0+1` for the next sibling.
+//   - Key reports the key or index of the reached value instead of the value itself, as `#`.
+//
+// https://datatracker.ietf.org/doc/html/draft-bhutton-relative-json-pointer-00
+type RelativePointer struct {
+	Up      int
+	Shift   int
+	Key     bool
+	Pointer Pointer
+}
+
+// ParseRelativePointer parses the relative JSON pointer, such as `0/foo`, `1#` and `2-1/bar`.
+// The levels to go up and the index manipulation must be non-negative integers without leading zero.
+func ParseRelativePointer(p string) (RelativePointer, error) {
+	up, rest, err := parseRelativeInteger(p)
+	if err != nil {
+		return RelativePointer{}, fmt.Errorf("%s does not start with non-negative integer", p)
+	}
+	relative := RelativePointer{Up: up}
+
+	if len(rest) > 0 && (rest[0] == '+' || rest[0] == '-') {
+		shift, r, err := parseRelativeInteger(rest[1:])
+		if err != nil {
+			return RelativePointer{}, fmt.Errorf("invalid index manipulation near %s", rest)
+		}
+		if relative.Shift = shift; rest[0] == '-' {
+			relative.Shift = -shift
+		}
+		rest = r
+	}
+
+	switch {
+	case rest == "#":
+		relative.Key = true
+	case rest == "":
+	default:
+		pointer, err := ParsePointer(rest)
+		if err != nil {
+			return RelativePointer{}, err
+		}
+		relative.Pointer = pointer
+	}
+	return relative, nil
+}
+func parseRelativeInteger(s string) (int, string, error) {
+	end := strings.IndexFunc(s, func(r rune) bool { return r < '0' || r > '9' })
+	if end < 0 {
+		end = len(s)
+	}
+	if end == 0 || end > 1 && s[0] == '0' {
+		return 0, s, fmt.Errorf("invalid integer near %s", s)
+	}
+	i, err := strconv.Atoi(s[:end])
+	return i, s[end:], err
+}
+
+func (r RelativePointer) String() string {
+	var b strings.Builder
+	b.WriteString(strconv.Itoa(r.Up))
+	if r.Shift > 0 {
+		b.WriteString("+")
+	}
+	if r.Shift != 0 {
+		b.WriteString(strconv.Itoa(r.Shift))
+	}
+	if r.Key {
+		b.WriteString("#")
+	} else if len(r.Pointer) > 0 {
+		p, _ := r.Pointer.PointerString()
+		b.WriteString(p)
+	}
+	return b.String()
+}
+
+// Resolve returns the absolute pointer that the relative pointer refers to from the position at within the root.
+// The returned pointer consists of [KeyAccess] and [IndexAccess], except the appended [RelativePointer.Pointer].
+func (r RelativePointer) Resolve(root JsonValue, at Pointer) (Pointer, error) {
+	position := make(Pointer, 0, len(at))
+	curr := root
+	for i, acc := range at {
+		curr = unwrapRoot(curr)
+		next, err := acc.Accessing(curr)
+		if err != nil {
+			return nil, prefixed(at[:i], err)
+		}
+		position, curr = append(position, concreteAccessor(acc, curr)), next
+	}
+
+	if r.Up < 0 || r.Up > len(position) {
+		return nil, fmt.Errorf("cannot go up %d levels from depth %d", r.Up, len(position))
+	}
+	position = slices.Clip(position[:len(position)-r.Up])
+
+	if r.Shift != 0 {
+		index, ok := IndexAccess(0), len(position) > 0
+		if ok {
+			index, ok = position[len(position)-1].(IndexAccess)
+		}
+		if !ok {
+			return nil, fmt.Errorf("index manipulation %+d is only allowed on array element", r.Shift)
+		}
+		parent, err := position[:len(position)-1].Accessing(root)
+		if err != nil {
+			return nil, err
+		}
+		arr, shifted := unwrapRoot(parent).(*Array), int(index)+r.Shift
+		if shifted < 0 || shifted >= len(*arr) {
+			err := ErrOutOfRange{Pointer: Pointer{IndexAccess(shifted)}, Value: parent, Index: shifted, Length: len(*arr)}
+			return nil, prefixed(position[:len(position)-1], err)
+		}
+		position[len(position)-1] = IndexAccess(shifted)
+	}
+
+	if r.Key && len(position) == 0 {
+		return nil, fmt.Errorf("root value has no key or index")
+	}
+	return append(position, r.Pointer...), nil
+}
+
+// Evaluating returns the value that the relative pointer refers to from the position at within the root.
+// If [RelativePointer.Key] is set, it returns the key as [String] or the index as [Number] instead.
+func (r RelativePointer) Evaluating(root JsonValue, at Pointer) (JsonValue, error) {
+	pointer, err := r.Resolve(root, at)
+	if err != nil {
+		return nil, err
+	}
+	if r.Key {
+		switch last := pointer[len(pointer)-1].(type) {
+		case IndexAccess:
+			n := Number(last)
+			return &n, nil
+		default:
+			s := String(fmt.Sprint(last))
+			return &s, nil
+		}
+	}
+	return pointer.Accessing(root)
+}
@@ -0,0 +1,120 @@
+package fluffyjson_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	fluffyjson "github.com/hayas1/go-fluffy-json"
+)
+
+func ExampleRelativePointer_Evaluating() {
+	var value fluffyjson.RootValue
+	if err := json.Unmarshal([]byte(`{"password": "secret", "confirm": "secret"}`), &value); err != nil {
+		panic(err)
+	}
+
+	relative, err := fluffyjson.ParseRelativePointer("1/password")
+	if err != nil {
+		panic(err)
+	}
+	password, err := relative.Evaluating(&value, fluffyjson.Pointer{fluffyjson.KeyAccess("confirm")})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(*password.(*fluffyjson.String))
+	// Output:
+	// secret
+}
+
+func TestRelativePointer(t *testing.T) {
+	// example of https://datatracker.ietf.org/doc/html/draft-bhutton-relative-json-pointer-00#section-5.1
+	const target = `{"foo": ["bar", "baz"], "highly": {"nested": {"objects": true}}}`
+
+	testcases := map[string]struct {
+		at       string
+		relative string
+		expected string
+		err      bool
+	}{
+		"self":               {at: "/foo/1", relative: "0", expected: `"baz"`},
+		"parent":             {at: "/foo/1", relative: "1/0", expected: `"bar"`},
+		"previous":           {at: "/foo/1", relative: "0-1", expected: `"bar"`},
+		"grandparent":        {at: "/foo/1", relative: "2/highly/nested/objects", expected: `true`},
+		"index":              {at: "/foo/1", relative: "0#", expected: `1`},
+		"shifted index":      {at: "/foo/1", relative: "0-1#", expected: `0`},
+		"key":                {at: "/foo/1", relative: "1#", expected: `"foo"`},
+		"nested self":        {at: "/highly/nested", relative: "0/objects", expected: `true`},
+		"nested parent":      {at: "/highly/nested", relative: "1/nested/objects", expected: `true`},
+		"nested grandparent": {at: "/highly/nested", relative: "2/foo/0", expected: `"bar"`},
+		"nested key":         {at: "/highly/nested", relative: "0#", expected: `"nested"`},
+		"parent key":         {at: "/highly/nested", relative: "1#", expected: `"highly"`},
+		"above root":         {at: "/foo/1", relative: "3", err: true},
+		"root key":           {at: "/foo/1", relative: "2#", err: true},
+		"shift out of range": {at: "/foo/1", relative: "0+1", err: true},
+		"shift on object":    {at: "/highly/nested", relative: "0+1", err: true},
+		"missing":            {at: "/foo/1", relative: "1/2", err: true},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			value := HelperUnmarshalValue(t, target)
+			relative, err := fluffyjson.ParseRelativePointer(tc.relative)
+			if err != nil {
+				t.Fatal(err)
+			}
+			HelperFatalEvaluate(t, tc.relative, relative.String())
+			actual, err := relative.Evaluating(&value, HelperFatalParsePointer(t, tc.at))
+			if tc.err {
+				if err == nil {
+					t.Fatalf("expected error, but got %v", actual)
+				}
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			HelperFatalEvaluate(t, HelperUnmarshalValue(t, tc.expected), fluffyjson.RootValue{JsonValue: actual})
+		})
+	}
+
+	t.Run("negative up", func(t *testing.T) {
+		value := HelperUnmarshalValue(t, target)
+		relative := fluffyjson.RelativePointer{Up: -1}
+		if actual, err := relative.Evaluating(&value, HelperFatalParsePointer(t, "/foo/1")); err == nil {
+			t.Fatalf("expected error, but got %v", actual)
+		}
+	})
+}
+
+func TestParseRelativePointer(t *testing.T) {
+	testcases := map[string]struct {
+		expected fluffyjson.RelativePointer
+		err      bool
+	}{
+		"0":         {expected: fluffyjson.RelativePointer{}},
+		"1#":        {expected: fluffyjson.RelativePointer{Up: 1, Key: true}},
+		"2/bar/0":   {expected: fluffyjson.RelativePointer{Up: 2, Pointer: fluffyjson.Pointer{fluffyjson.KeyIndexAccess("bar"), fluffyjson.KeyIndexAccess("0")}}},
+		"0+10/a~1b": {expected: fluffyjson.RelativePointer{Shift: 10, Pointer: fluffyjson.Pointer{fluffyjson.KeyIndexAccess("a/b")}}},
+		"3-2#":      {expected: fluffyjson.RelativePointer{Up: 3, Shift: -2, Key: true}},
+		"":          {err: true},
+		"/foo":      {err: true},
+		"01":        {err: true},
+		"-1":        {err: true},
+		"0+":        {err: true},
+		"0foo":      {err: true},
+		"0#/foo":    {err: true},
+	}
+
+	for input, tc := range testcases {
+		t.Run(input, func(t *testing.T) {
+			actual, err := fluffyjson.ParseRelativePointer(input)
+			if tc.err {
+				if err == nil {
+					t.Fatalf("expected error, but got %v", actual)
+				}
+				return
+			}
+			HelperFatalEvaluateError(t, tc.expected, actual, nil, err)
+		})
+	}
+}
@@ -0,0 +1,275 @@
+package fluffyjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+type (
+	// Change is the change between two values reported by [Changes].
+	// Old is nil if the value is added, and New is nil if the value is removed.
+	Change struct {
+		Kind ChangeKind
+		Path Pointer
+		Old  JsonValue
+		New  JsonValue
+	}
+	ChangeKind string
+
+	ReportOption  func(*reportOptions)
+	reportOptions struct {
+		color     bool
+		tolerance float64
+		context   int
+	}
+)
+
+const (
+	CHANGED ChangeKind = "~"
+	ADDED   ChangeKind = "+"
+	REMOVED ChangeKind = "-"
+)
+
+const (
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+	ansiCyan   = "\x1b[36m"
+	ansiReset  = "\x1b[0m"
+)
+
+// Colored colors the report with ANSI escape sequences.
+func Colored() ReportOption {
+	return func(o *reportOptions) { o.color = true }
+}
+
+// NumericTolerance regards the numbers whose difference is within the tolerance as equal.
+func NumericTolerance(tolerance float64) ReportOption {
+	return func(o *reportOptions) { o.tolerance = tolerance }
+}
+
+// ContextLines sets the number of unchanged lines around the changes in [UnifiedReport], which is 3 by default.
+func ContextLines(n int) ReportOption {
+	return func(o *reportOptions) { o.context = n }
+}
+
+func newReportOptions(opts []ReportOption) reportOptions {
+	options := reportOptions{context: 3}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+func (o reportOptions) differ() differ {
+	return differ{equal: equalOptions{epsilon: o.tolerance}.equal}
+}
+func (o reportOptions) paint(color, s string) string {
+	if !o.color || s == "" {
+		return s
+	}
+	return color + s + ansiReset
+}
+
+// Changes lists the changes from a to b in the order of [Diff]. Each path refers to the value
+// after the preceding changes are applied, as the operations of JSON Patch do.
+func Changes(a, b JsonValue, opts ...ReportOption) []Change {
+	changes, _ := changes(a, b, newReportOptions(opts))
+	return changes
+}
+
+// changes returns the changes and the value that the changes are applied to a,
+// which equals b except the numbers within the tolerance.
+func changes(a, b JsonValue, options reportOptions) ([]Change, JsonValue) {
+	patched := RootValue{deepCopy(a)}
+	changes := make([]Change, 0)
+	for _, op := range options.differ().diff(a, b) {
+		old, _ := patched.Access(op.Path...)
+		switch op.Op {
+		case "add":
+			changes = append(changes, Change{Kind: ADDED, Path: op.Path, New: op.Value})
+		case "remove":
+			changes = append(changes, Change{Kind: REMOVED, Path: op.Path, Old: old})
+		case "replace":
+			changes = append(changes, Change{Kind: CHANGED, Path: op.Path, Old: old, New: op.Value})
+		}
+		// the diff is always applicable to a
+		_ = op.apply(&patched)
+	}
+	return changes, patched.JsonValue
+}
+
+func (c Change) String() string {
+	return c.format(reportOptions{})
+}
+func (c Change) format(options reportOptions) string {
+	path, _ := c.Path.PointerString()
+	switch c.Kind {
+	case ADDED:
+		return options.paint(ansiGreen, fmt.Sprintf("+ %s: %s", path, compactJson(c.New)))
+	case REMOVED:
+		return options.paint(ansiRed, fmt.Sprintf("- %s", path))
+	default:
+		return options.paint(ansiYellow, fmt.Sprintf("~ %s: %s → %s", path, compactJson(c.Old), compactJson(c.New)))
+	}
+}
+
+// Report lists the changes from a to b line by line, such as `~ /a/b: 1 → 2`, `+ /c: "x"` and `- /d`.
+func Report(a, b JsonValue, opts ...ReportOption) string {
+	options := newReportOptions(opts)
+	changes, _ := changes(a, b, options)
+	var r strings.Builder
+	for _, c := range changes {
+		r.WriteString(c.format(options))
+		r.WriteByte('\n')
+	}
+	return r.String()
+}
+
+// UnifiedReport renders the unified diff of the pretty-printed a and b.
+func UnifiedReport(a, b JsonValue, opts ...ReportOption) string {
+	options := newReportOptions(opts)
+	_, patched := changes(a, b, options)
+	left, right := prettyLines(a), prettyLines(patched)
+	script, edits := lineScript(left, right), make([]int, 0)
+	for i, e := range script {
+		if e.kind != ' ' {
+			edits = append(edits, i)
+		}
+	}
+	if len(edits) == 0 {
+		return ""
+	}
+
+	var r strings.Builder
+	r.WriteString(options.paint(ansiRed, "--- a") + "\n")
+	r.WriteString(options.paint(ansiGreen, "+++ b") + "\n")
+	for k := 0; k < len(edits); {
+		// the edits are merged into one hunk while the unchanged lines between them overlap in context
+		first, last := edits[k], edits[k]
+		for k++; k < len(edits) && edits[k]-last-1 <= 2*options.context; k++ {
+			last = edits[k]
+		}
+		hunk := script[max(0, first-options.context):min(len(script), last+1+options.context)]
+
+		var lines, removed, added int
+		for _, e := range hunk {
+			switch e.kind {
+			case '-':
+				removed++
+			case '+':
+				added++
+			default:
+				lines++
+			}
+		}
+		header := fmt.Sprintf("@@ -%s +%s @@", hunkRange(hunk[0].left, lines+removed), hunkRange(hunk[0].right, lines+added))
+		r.WriteString(options.paint(ansiCyan, header) + "\n")
+		for _, e := range hunk {
+			switch e.kind {
+			case '-':
+				r.WriteString(options.paint(ansiRed, "-"+left[e.left]) + "\n")
+			case '+':
+				r.WriteString(options.paint(ansiGreen, "+"+right[e.right]) + "\n")
+			default:
+				r.WriteString(" " + left[e.left] + "\n")
+			}
+		}
+	}
+	return r.String()
+}
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// SideBySideReport renders the pretty-printed a and b in two columns. The marker between the columns is
+// `|` for changed line, `<` for removed line, `>` for added line, and space for unchanged line.
+func SideBySideReport(a, b JsonValue, opts ...ReportOption) string {
+	options := newReportOptions(opts)
+	_, patched := changes(a, b, options)
+	left, right := prettyLines(a), prettyLines(patched)
+	width := 0
+	for _, l := range left {
+		width = max(width, len([]rune(l)))
+	}
+
+	var r strings.Builder
+	row := func(l string, marker byte, rr string) {
+		pad := strings.Repeat(" ", width-len([]rune(l)))
+		switch marker {
+		case '|':
+			l, rr = options.paint(ansiRed, l), options.paint(ansiGreen, rr)
+		case '<':
+			l = options.paint(ansiRed, l)
+		case '>':
+			rr = options.paint(ansiGreen, rr)
+		}
+		r.WriteString(strings.TrimRight(fmt.Sprintf("%s%s %c %s", l, pad, marker, rr), " ") + "\n")
+	}
+
+	script := lineScript(left, right)
+	for i := 0; i < len(script); {
+		if script[i].kind == ' ' {
+			row(left[script[i].left], ' ', right[script[i].right])
+			i++
+			continue
+		}
+		// pair the removed lines with the added lines that follow them
+		var removed, added []string
+		for ; i < len(script) && script[i].kind == '-'; i++ {
+			removed = append(removed, left[script[i].left])
+		}
+		for ; i < len(script) && script[i].kind == '+'; i++ {
+			added = append(added, right[script[i].right])
+		}
+		for k := range max(len(removed), len(added)) {
+			switch {
+			case k < len(removed) && k < len(added):
+				row(removed[k], '|', added[k])
+			case k < len(removed):
+				row(removed[k], '<', "")
+			default:
+				row("", '>', added[k])
+			}
+		}
+	}
+	return r.String()
+}
+
+// lineEdit is the edit of line diff. Kind is ' ' for unchanged, '-' for removed, and '+' for added line,
+// and left and right are the line numbers from 0, which are the next lines for added and removed line respectively.
+type lineEdit struct {
+	kind        byte
+	left, right int
+}
+
+// lineScript returns the edit script between the lines aligned by [align], in which the removed lines precede the added lines.
+func lineScript(a, b []string) []lineEdit {
+	script := make([]lineEdit, 0, len(a)+len(b))
+	i, j := 0, 0
+	for _, match := range append(align(len(a), len(b), func(i, j int) bool { return a[i] == b[j] }), [2]int{len(a), len(b)}) {
+		for ; i < match[0]; i++ {
+			script = append(script, lineEdit{kind: '-', left: i, right: j})
+		}
+		for ; j < match[1]; j++ {
+			script = append(script, lineEdit{kind: '+', left: i, right: j})
+		}
+		if i < len(a) && j < len(b) {
+			script = append(script, lineEdit{kind: ' ', left: i, right: j})
+		}
+		i, j = i+1, j+1
+	}
+	return script
+}
+
+func prettyLines(v JsonValue) []string {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(compactJson(v)), "", "  "); err != nil {
+		return []string{err.Error()}
+	}
+	return strings.Split(buf.String(), "\n")
+}
@@ -0,0 +1,132 @@
+package fluffyjson_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	fluffyjson "github.com/hayas1/go-fluffy-json"
+)
+
+func ExampleReport() {
+	var a, b fluffyjson.RootValue
+	if err := json.Unmarshal([]byte(`{"a": {"b": 1}, "d": true, "e": 0.1}`), &a); err != nil {
+		panic(err)
+	}
+	if err := json.Unmarshal([]byte(`{"a": {"b": 2}, "c": "x", "e": 0.1000001}`), &b); err != nil {
+		panic(err)
+	}
+
+	fmt.Print(fluffyjson.Report(&a, &b, fluffyjson.NumericTolerance(1e-6)))
+	// Output:
+	// - /d
+	// ~ /a/b: 1 → 2
+	// + /c: "x"
+}
+
+func TestReport(t *testing.T) {
+	const a = `{"name": "alice", "tags": ["a", "c"], "age": 20, "score": 1.5}`
+	const b = `{"name": "alice", "tags": ["a", "b", "c"], "email": "<alice@example.com>", "score": 1.50001}`
+
+	testcases := map[string]struct {
+		report   func(a, b fluffyjson.JsonValue) string
+		expected string
+	}{
+		"report": {
+			report: func(a, b fluffyjson.JsonValue) string { return fluffyjson.Report(a, b) },
+			expected: "- /age\n" +
+				"~ /score: 1.5 → 1.50001\n" +
+				"+ /tags/1: \"b\"\n" +
+				"+ /email: \"<alice@example.com>\"\n",
+		},
+		"tolerance": {
+			report: func(a, b fluffyjson.JsonValue) string {
+				return fluffyjson.Report(a, b, fluffyjson.NumericTolerance(0.001))
+			},
+			expected: "- /age\n" +
+				"+ /tags/1: \"b\"\n" +
+				"+ /email: \"<alice@example.com>\"\n",
+		},
+		"color": {
+			report: func(a, b fluffyjson.JsonValue) string {
+				return fluffyjson.Report(a, b, fluffyjson.NumericTolerance(0.001), fluffyjson.Colored())
+			},
+			expected: "\x1b[31m- /age\x1b[0m\n" +
+				"\x1b[32m+ /tags/1: \"b\"\x1b[0m\n" +
+				"\x1b[32m+ /email: \"<alice@example.com>\"\x1b[0m\n",
+		},
+		"unified": {
+			report: func(a, b fluffyjson.JsonValue) string {
+				return fluffyjson.UnifiedReport(a, b, fluffyjson.NumericTolerance(0.001), fluffyjson.ContextLines(1))
+			},
+			expected: "--- a\n" +
+				"+++ b\n" +
+				"@@ -1,3 +1,3 @@\n" +
+				" {\n" +
+				"-  \"age\": 20,\n" +
+				"+  \"email\": \"<alice@example.com>\",\n" +
+				"   \"name\": \"alice\",\n" +
+				"@@ -6,2 +6,3 @@\n" +
+				"     \"a\",\n" +
+				"+    \"b\",\n" +
+				"     \"c\"\n",
+		},
+		"side by side": {
+			report: func(a, b fluffyjson.JsonValue) string {
+				return fluffyjson.SideBySideReport(a, b)
+			},
+			expected: "{                    {\n" +
+				"  \"age\": 20,       |   \"email\": \"<alice@example.com>\",\n" +
+				"  \"name\": \"alice\",     \"name\": \"alice\",\n" +
+				"  \"score\": 1.5,    |   \"score\": 1.50001,\n" +
+				"  \"tags\": [            \"tags\": [\n" +
+				"    \"a\",                 \"a\",\n" +
+				"                   >     \"b\",\n" +
+				"    \"c\"                  \"c\"\n" +
+				"  ]                    ]\n" +
+				"}                    }\n",
+		},
+		"no change": {
+			report: func(a, _ fluffyjson.JsonValue) string {
+				return fluffyjson.Report(a, a) + fluffyjson.UnifiedReport(a, a)
+			},
+			expected: "",
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			av, bv := HelperUnmarshalValue(t, a), HelperUnmarshalValue(t, b)
+			HelperFatalEvaluate(t, tc.expected, tc.report(&av, &bv))
+		})
+	}
+
+	t.Run("changes", func(t *testing.T) {
+		av, bv := HelperUnmarshalValue(t, `[1, 2, 3]`), HelperUnmarshalValue(t, `[1, 3, 3, 4]`)
+		changes := fluffyjson.Changes(&av, &bv)
+		actual := make([]string, 0, len(changes))
+		for _, c := range changes {
+			actual = append(actual, c.String())
+		}
+		HelperFatalEvaluate(t, []string{"~ /1: 2 → 3", "+ /3: 4"}, actual)
+		HelperFatalEvaluate(t, fluffyjson.JsonValue(HelperCastNumber(t, 2)), changes[0].Old)
+	})
+
+	t.Run("large", func(t *testing.T) {
+		elements := make([]string, 0, 100000)
+		for i := range 100000 {
+			elements = append(elements, strconv.Itoa(i))
+		}
+		av := HelperUnmarshalValue(t, "["+strings.Join(elements, ",")+"]")
+		elements[50000] = `"x"`
+		bv := HelperUnmarshalValue(t, "["+strings.Join(elements, ",")+"]")
+		expected := "--- a\n" +
+			"+++ b\n" +
+			"@@ -50002,1 +50002,1 @@\n" +
+			"-  50000,\n" +
+			"+  \"x\",\n"
+		HelperFatalEvaluate(t, expected, fluffyjson.UnifiedReport(&av, &bv, fluffyjson.ContextLines(0)))
+	})
+}
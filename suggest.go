@@ -0,0 +1,88 @@
+package fluffyjson
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// maxSuggestions is the maximum number of keys suggested by [ErrNotFound].
+const maxSuggestions = 3
+
+// normalizeKey folds the case and removes separators, so that `userId`, `user_id` and `UserID` are the same.
+func normalizeKey(key string) string {
+	var b strings.Builder
+	for _, r := range key {
+		switch {
+		case r == '_' || r == '-' || r == '.' || unicode.IsSpace(r):
+		default:
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return b.String()
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev, curr := make([]int, len(rb)+1), make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// suggestKeys returns the keys of the object close to the given key, nearest first.
+func suggestKeys(key string, o Object) []string {
+	type candidate struct {
+		key      string
+		distance int
+	}
+	normalized := normalizeKey(key)
+	threshold := max(1, len([]rune(normalized))/3)
+
+	candidates := make([]candidate, 0)
+	for k := range o {
+		if d := editDistance(normalized, normalizeKey(k)); d <= threshold && d < len([]rune(normalized)) {
+			candidates = append(candidates, candidate{key: k, distance: d})
+		}
+	}
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		if a.distance != b.distance {
+			return a.distance - b.distance
+		}
+		return strings.Compare(a.key, b.key)
+	})
+
+	if len(candidates) == 0 {
+		return nil
+	}
+	suggestions := make([]string, 0, min(len(candidates), maxSuggestions))
+	for _, c := range candidates[:min(len(candidates), maxSuggestions)] {
+		suggestions = append(suggestions, c.key)
+	}
+	return suggestions
+}
+
+func didYouMean(suggestions []string) string {
+	if len(suggestions) == 0 {
+		return ""
+	}
+	quoted := make([]string, 0, len(suggestions))
+	for _, s := range suggestions {
+		quoted = append(quoted, fmt.Sprintf("%q", s))
+	}
+	return fmt.Sprintf(", did you mean %s?", strings.Join(quoted, " or "))
+}
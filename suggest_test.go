@@ -0,0 +1,56 @@
+package fluffyjson_test
+
+import (
+	"errors"
+	"testing"
+
+	fluffyjson "github.com/hayas1/go-fluffy-json"
+)
+
+func TestNotFoundSuggestions(t *testing.T) {
+	testcases := map[string]struct {
+		target      string
+		pointer     string
+		suggestions []string
+		message     string
+	}{
+		"typo": {
+			target:      `{"config": {"timeout": 1, "retry": 2}}`,
+			pointer:     "/config/timout",
+			suggestions: []string{"timeout"},
+			message:     `key "timout" not found at /config/timout, did you mean "timeout"?`,
+		},
+		"case folding": {
+			target:      `{"UserName": "fluffy"}`,
+			pointer:     "/username",
+			suggestions: []string{"UserName"},
+			message:     `key "username" not found at /username, did you mean "UserName"?`,
+		},
+		"snake and camel": {
+			target:      `{"user_id": 1, "userId": 2, "group_id": 3}`,
+			pointer:     "/UserID",
+			suggestions: []string{"userId", "user_id"},
+			message:     `key "UserID" not found at /UserID, did you mean "userId" or "user_id"?`,
+		},
+		"no suggestion": {
+			target:      `{"alpha": 1, "beta": 2}`,
+			pointer:     "/gamma",
+			suggestions: nil,
+			message:     `key "gamma" not found at /gamma`,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			value := HelperUnmarshalValue(t, tc.target)
+			_, err := value.Access(HelperFatalParsePointer(t, tc.pointer))
+
+			var notFound fluffyjson.ErrNotFound
+			if !errors.As(err, &notFound) {
+				t.Fatal(err)
+			}
+			HelperFatalEvaluate(t, tc.suggestions, notFound.Suggestions())
+			HelperFatalEvaluate(t, tc.message, err.Error())
+		})
+	}
+}
@@ -7,6 +7,9 @@ package fluffyjson
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 )
 
 type (
@@ -30,11 +33,8 @@ type (
 	String    string
 	Number    float64
 	Bool      bool
-	Null      func(null)
-
-	null struct {
-		_ struct{}
-	}
+	// Null is the zero-size comparable type, so any two Null values are equal and [NullValue] is canonical.
+	Null struct{}
 
 	ErrCast struct {
 		Unsupported any
@@ -60,6 +60,9 @@ const (
 	NULL   representation = "null"
 )
 
+// NullValue is the canonical value of [Null].
+var NullValue = Null{}
+
 func (v *RootValue) UnmarshalJSON(data []byte) error {
 	switch data[0] {
 	case '{':
@@ -258,16 +261,61 @@ func (n *Null) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &inner); err != nil {
 		return err
 	}
-	null, err := Cast(inner)
+	null, err := CastNull(inner)
 	if err != nil {
 		return err
 	}
-	*n = *null.(*Null)
+	*n = null
 	return nil
 }
 func (n Null) MarshalJSON() ([]byte, error) {
 	return json.Marshal(nil)
 }
-func CastNull(n func(null)) (Null, error) {
-	return Null(n), nil
+func CastNull(n any) (Null, error) {
+	if n != nil {
+		return NullValue, ErrCast{Unsupported: n}
+	}
+	return NullValue, nil
+}
+
+// compactJson encodes the value into compact JSON with sorted keys, without escaping HTML characters.
+func compactJson(v JsonValue) string {
+	var b strings.Builder
+	var encode func(v JsonValue)
+	encode = func(v JsonValue) {
+		switch t := v.(type) {
+		case *Object:
+			b.WriteByte('{')
+			for i, k := range slices.Sorted(maps.Keys(*t)) {
+				if i > 0 {
+					b.WriteByte(',')
+				}
+				key := String(k)
+				encode(&key)
+				b.WriteByte(':')
+				encode((*t)[k])
+			}
+			b.WriteByte('}')
+		case *Array:
+			b.WriteByte('[')
+			for i, elem := range *t {
+				if i > 0 {
+					b.WriteByte(',')
+				}
+				encode(elem)
+			}
+			b.WriteByte(']')
+		case *String:
+			var encoded strings.Builder
+			encoder := json.NewEncoder(&encoded)
+			encoder.SetEscapeHTML(false)
+			encoder.Encode(string(*t))
+			b.WriteString(strings.TrimSuffix(encoded.String(), "\n"))
+		default:
+			encoded, _ := json.Marshal(v)
+			b.Write(encoded)
+		}
+	}
+	encode(v)
+	return b.String()
 }
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -197,6 +198,33 @@ func TestMarshalBasic(t *testing.T) {
 		})
 	}
 }
+func TestNull(t *testing.T) {
+	t.Run("comparable", func(t *testing.T) {
+		var n fluffyjson.Null
+		if err := json.Unmarshal([]byte(`null`), &n); err != nil {
+			t.Fatal(err)
+		}
+		HelperFatalEvaluate(t, true, n == fluffyjson.NullValue)
+
+		counts := map[fluffyjson.Null]int{}
+		counts[n]++
+		counts[fluffyjson.NullValue]++
+		HelperFatalEvaluate(t, map[fluffyjson.Null]int{fluffyjson.NullValue: 2}, counts)
+	})
+
+	t.Run("deep equal", func(t *testing.T) {
+		a, b := HelperUnmarshalValue(t, `{"a": [null, {"b": null}]}`), HelperUnmarshalValue(t, `{"a": [null, {"b": null}]}`)
+		HelperFatalEvaluate(t, true, reflect.DeepEqual(a, b))
+	})
+
+	t.Run("roundtrip", func(t *testing.T) {
+		bytes, err := json.Marshal(fluffyjson.NullValue)
+		HelperFatalEvaluateError(t, "null", string(bytes), nil, err)
+		var n fluffyjson.Null
+		err = json.Unmarshal([]byte(`1`), &n)
+		HelperFatalEvaluateError(t, fluffyjson.NullValue, n, fluffyjson.ErrCast{Unsupported: float64(1)}, err)
+	})
+}
 
 func FuzzMarshalUnmarshalRoundtrip(f *testing.F) {
 	f.Add(`[
@@ -306,13 +334,9 @@ func HelperCastBool(t *testing.T, b bool) *fluffyjson.Bool {
 	}
 	return &v
 }
-func HelperCastNull(t *testing.T, n func()) *fluffyjson.Null {
-	// func HelperCastNull(t *testing.T, n func(null)) *fluffyjson.Null {
+func HelperCastNull(t *testing.T, n any) *fluffyjson.Null {
 	t.Helper()
-	if n != nil {
-		t.Fatalf("%p is not nil", n)
-	}
-	v, err := fluffyjson.CastNull(nil)
+	v, err := fluffyjson.CastNull(n)
 	if err != nil {
 		t.Fatal(err)
 	}